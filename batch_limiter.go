@@ -1,17 +1,35 @@
 package toolbox
 
-import "sync"
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
 
 //BatchLimiter represents a batch limiter
 type BatchLimiter struct {
-	queue chan uint8
-	group *sync.WaitGroup
-	Mutex *sync.RWMutex
+	queue     chan uint8
+	group     *sync.WaitGroup
+	Mutex     *sync.RWMutex
+	cancel    context.CancelFunc
+	errMutex  sync.Mutex
+	errors    []error
+	inFlight  int32
+	completed int32
 }
 
-//Acquire takes token form a channel, or wait if  no more elements in a a channel
-func (r *BatchLimiter) Acquire() {
-	<-r.queue
+//Acquire takes token form a channel, or wait if  no more elements in a a channel, it returns an error if context is done before token is taken
+func (r *BatchLimiter) Acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.queue:
+	}
+	atomic.AddInt32(&r.inFlight, 1)
+	return nil
 }
 
 //Add adds element to wait group
@@ -21,13 +39,67 @@ func (r *BatchLimiter) Add(delta int) {
 
 //Done flags wait group as done, returns back a token to a channel
 func (r *BatchLimiter) Done() {
+	atomic.AddInt32(&r.inFlight, -1)
+	atomic.AddInt32(&r.completed, 1)
 	r.group.Done()
 	r.queue <- uint8(1)
 }
 
-//Wait wait on wait group
-func (r *BatchLimiter) Wait() {
+//Go waits for a token and runs a task in a new go routine, task error or token acquisition error is collected and returned by Wait
+func (r *BatchLimiter) Go(ctx context.Context, task func(ctx context.Context) error) {
+	if err := r.Acquire(ctx); err != nil {
+		r.addError(err)
+		return
+	}
+	r.Add(1)
+	go func() {
+		defer r.Done()
+		if err := task(ctx); err != nil {
+			r.addError(err)
+		}
+	}()
+}
+
+func (r *BatchLimiter) addError(err error) {
+	r.errMutex.Lock()
+	defer r.errMutex.Unlock()
+	r.errors = append(r.errors, err)
+	if len(r.errors) == 1 && r.cancel != nil {
+		r.cancel()
+	}
+}
+
+//Wait wait on wait group, it returns the first collected error if any
+func (r *BatchLimiter) Wait() error {
 	r.group.Wait()
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.errMutex.Lock()
+	defer r.errMutex.Unlock()
+	if len(r.errors) == 0 {
+		return nil
+	}
+	return r.errors[0]
+}
+
+//Errors returns all collected errors
+func (r *BatchLimiter) Errors() []error {
+	r.errMutex.Lock()
+	defer r.errMutex.Unlock()
+	var result = make([]error, len(r.errors))
+	copy(result, r.errors)
+	return result
+}
+
+//InFlight returns number of tasks holding a token
+func (r *BatchLimiter) InFlight() int {
+	return int(atomic.LoadInt32(&r.inFlight))
+}
+
+//Completed returns number of completed tasks
+func (r *BatchLimiter) Completed() int {
+	return int(atomic.LoadInt32(&r.completed))
 }
 
 //NewBatchLimiter creates a new batch limiter with batch size and total number of elements
@@ -45,3 +117,10 @@ func NewBatchLimiter(batchSize, total int) *BatchLimiter {
 	result.group.Add(total)
 	return result
 }
+
+//NewBatchLimiterWithContext creates a new batch limiter with batch size and a derived context, the derived context is cancelled when the first error is collected or once Wait returns
+func NewBatchLimiterWithContext(ctx context.Context, batchSize int) (*BatchLimiter, context.Context) {
+	result := NewBatchLimiter(batchSize, 0)
+	ctx, result.cancel = context.WithCancel(ctx)
+	return result, ctx
+}
@@ -1,9 +1,12 @@
 package toolbox_test
 
 import (
+	"context"
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/toolbox"
 	"testing"
+	"time"
 )
 
 func TestBatchLimiter(t *testing.T) {
@@ -12,7 +15,7 @@ func TestBatchLimiter(t *testing.T) {
 	var sum int32 = 0
 	for _, n := range numbers {
 		go func(n int32) {
-			limiter.Acquire()
+			_ = limiter.Acquire(context.Background())
 			defer limiter.Done()
 			limiter.Mutex.Lock()
 			defer limiter.Mutex.Unlock()
@@ -21,10 +24,60 @@ func TestBatchLimiter(t *testing.T) {
 		}(int32(n))
 
 	}
-	limiter.Wait()
+	assert.Nil(t, limiter.Wait())
 	var expected int32 = 0
 	for _, n := range numbers {
 		expected += int32(n)
 	}
 	assert.Equal(t, expected, sum)
+	assert.Equal(t, len(numbers), limiter.Completed())
+	assert.Equal(t, 0, limiter.InFlight())
+}
+
+func TestBatchLimiter_Acquire(t *testing.T) {
+	limiter := toolbox.NewBatchLimiter(1, 0)
+	assert.Nil(t, limiter.Acquire(context.Background()))
+	assert.Equal(t, 1, limiter.InFlight())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, limiter.Acquire(ctx))
+}
+
+func TestBatchLimiter_Go(t *testing.T) {
+	{
+		limiter, ctx := toolbox.NewBatchLimiterWithContext(context.Background(), 2)
+		for i := 0; i < 10; i++ {
+			limiter.Go(ctx, func(ctx context.Context) error {
+				assert.True(t, limiter.InFlight() <= 2)
+				return nil
+			})
+		}
+		assert.Nil(t, limiter.Wait())
+		assert.Equal(t, 10, limiter.Completed())
+		assert.Equal(t, 0, len(limiter.Errors()))
+	}
+	{
+		limiter, ctx := toolbox.NewBatchLimiterWithContext(context.Background(), 2)
+		var expected = errors.New("test error")
+		for i := 0; i < 10; i++ {
+			i := i
+			limiter.Go(ctx, func(ctx context.Context) error {
+				if i == 1 {
+					return expected
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Second):
+				}
+				return nil
+			})
+		}
+		assert.Equal(t, expected, limiter.Wait())
+		errs := limiter.Errors()
+		assert.True(t, len(errs) > 1)
+		for _, err := range errs[1:] {
+			assert.Equal(t, context.Canceled, err)
+		}
+	}
 }
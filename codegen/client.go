@@ -0,0 +1,522 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/viant/toolbox"
+)
+
+// ClientRoute represents a route with handler signature resolved from ClientOptions.Source
+type ClientRoute struct {
+	Name       string   //optional client method name, handler name by default
+	HTTPMethod string   //HTTP method
+	URI        string   //service routing URI template
+	Handler    string   //handler function or Type.Method name
+	Parameters []string //handler parameter names, same as toolbox.ServiceRouting.Parameters
+}
+
+// ClientOptions represents typed HTTP client generator options
+type ClientOptions struct {
+	Package      string                   //generated code package name, Source package or PackagePath last segment by default
+	PackagePath  string                   //generated code import path, types from this package are not qualified
+	Client       string                   //client type name, Client by default
+	Source       string                   //source directory with Routes handlers
+	SourceImport string                   //Source import path, required if Package differs from Source package
+	Routes       []*ClientRoute           //routes resolved with toolbox.FileSetInfo
+	Routings     []toolbox.ServiceRouting //service routings with handler signatures resolved with reflection
+	Dest         string                   //optional destination file
+}
+
+// clientParameter represents a client method parameter
+type clientParameter struct {
+	name       string //route parameter name
+	identifier string //Go parameter name
+	typeName   string
+	isBody     bool //body candidate type: struct, slice or map
+	isObject   bool //struct or map type, it can not be sent as query parameter
+}
+
+// clientMethod represents a client method calling a route
+type clientMethod struct {
+	name       string
+	httpMethod string
+	URI        string
+	parameters []*clientParameter
+	resultType string
+}
+
+// GenerateClient generates a typed HTTP client with one method per route, path parameters are substituted, query parameters encoded
+// and the first struct, slice or map parameter which is not a path parameter is sent as request body with toolbox.ToolboxHTTPClient,
+// methods return handler result and *toolbox.HTTPError for error status code, another struct or map parameter returns an error. If Dest is specified generated code is also written to that file.
+func GenerateClient(options *ClientOptions) ([]byte, error) {
+	if len(options.Routes) == 0 && len(options.Routings) == 0 {
+		return nil, fmt.Errorf("routes were empty")
+	}
+	if options.Client == "" {
+		options.Client = "Client"
+	}
+	generator := &clientGenerator{options: options, imports: map[string]string{"toolbox": "github.com/viant/toolbox"}}
+	var methods = make([]*clientMethod, 0)
+	if len(options.Routes) > 0 {
+		routeMethods, err := generator.sourceMethods()
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, routeMethods...)
+	}
+	for i := range options.Routings {
+		method, err := generator.routingMethod(&options.Routings[i])
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+	if options.Package == "" && options.PackagePath != "" {
+		options.Package = path.Base(options.PackagePath)
+	}
+	if options.Package == "" {
+		return nil, fmt.Errorf("package was empty")
+	}
+	if err := uniqueMethodNames(methods); err != nil {
+		return nil, err
+	}
+	var body = new(bytes.Buffer)
+	generator.generateClient(body)
+	for _, method := range methods {
+		if err := generator.generateMethod(body, method); err != nil {
+			return nil, err
+		}
+	}
+	var code = new(bytes.Buffer)
+	code.WriteString("// Code generated by toolbox codegen. DO NOT EDIT.\n\n")
+	code.WriteString(fmt.Sprintf("package %v\n\n", options.Package))
+	code.WriteString("import (\n")
+	var names = make([]string, 0, len(generator.imports))
+	for name := range generator.imports {
+		names = append(names, name)
+	}
+	isStandard := func(importPath string) bool {
+		return !strings.Contains(strings.Split(importPath, "/")[0], ".")
+	}
+	sort.Slice(names, func(i, j int) bool {
+		left, right := generator.imports[names[i]], generator.imports[names[j]]
+		if isStandard(left) != isStandard(right) {
+			return isStandard(left)
+		}
+		return left < right
+	})
+	for i, name := range names {
+		importPath := generator.imports[name]
+		if i > 0 && isStandard(generator.imports[names[i-1]]) && !isStandard(importPath) {
+			code.WriteString("\n")
+		}
+		if path.Base(importPath) == name {
+			code.WriteString(fmt.Sprintf("\t%q\n", importPath))
+		} else {
+			code.WriteString(fmt.Sprintf("\t%v %q\n", name, importPath))
+		}
+	}
+	code.WriteString(")\n\n")
+	code.Write(body.Bytes())
+	result, err := format.Source(code.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %v\n%s", err, code.Bytes())
+	}
+	if options.Dest != "" {
+		if err = ioutil.WriteFile(options.Dest, result, 0644); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+type clientGenerator struct {
+	options *ClientOptions
+	imports map[string]string //package name to import path
+}
+
+// sourceMethods returns client methods for routes with handlers resolved with toolbox.FileSetInfo
+func (g *clientGenerator) sourceMethods() ([]*clientMethod, error) {
+	fileSetInfo, err := toolbox.NewFileSetInfo(g.options.Source)
+	if err != nil {
+		return nil, err
+	}
+	var sourcePackage string
+	for _, fileInfo := range fileSetInfo.FilesInfo() {
+		sourcePackage = fileInfo.PackageName()
+		break
+	}
+	if g.options.Package == "" {
+		g.options.Package = sourcePackage
+	}
+	qualifier := ""
+	if g.options.Package != sourcePackage {
+		if g.options.SourceImport == "" {
+			return nil, fmt.Errorf("source import was empty, required for %v package handlers used in %v package", sourcePackage, g.options.Package)
+		}
+		qualifier = sourcePackage
+	}
+	var result = make([]*clientMethod, 0)
+	for _, route := range g.options.Routes {
+		function := lookupFunction(fileSetInfo, route.Handler)
+		if function == nil {
+			return nil, fmt.Errorf("failed to lookup handler: %v in %v", route.Handler, g.options.Source)
+		}
+		method := &clientMethod{name: route.Name, httpMethod: strings.ToUpper(route.HTTPMethod), URI: route.URI}
+		if method.name == "" {
+			method.name = function.Name
+		}
+		for i, name := range route.Parameters {
+			if i >= len(function.ParameterFields) {
+				return nil, fmt.Errorf("handler %v has no parameter for %v", route.Handler, name)
+			}
+			field := function.ParameterFields[i]
+			typeName := g.sourceTypeName(fileSetInfo, field, qualifier)
+			parameter := &clientParameter{name: name, typeName: typeName}
+			baseType := strings.TrimPrefix(field.TypeName, "*")
+			typeInfo := fileSetInfo.Type(baseType)
+			parameter.isObject = field.IsMap || strings.HasPrefix(field.TypeName, "map[") || (typeInfo != nil && typeInfo.IsStruct)
+			parameter.isBody = parameter.isObject || field.IsSlice || strings.HasPrefix(field.TypeName, "[]")
+			if field.IsVariant {
+				parameter.typeName = "..." + strings.TrimPrefix(typeName, "[]")
+				parameter.isBody = false
+			}
+			method.parameters = append(method.parameters, parameter)
+		}
+		for _, field := range function.ResultsFields {
+			if field.TypeName != "error" {
+				method.resultType = g.sourceTypeName(fileSetInfo, field, qualifier)
+			}
+			break
+		}
+		result = append(result, method)
+	}
+	return result, nil
+}
+
+// lookupFunction returns function or Type.Method function info
+func lookupFunction(fileSetInfo *toolbox.FileSetInfo, handler string) *toolbox.FunctionInfo {
+	if index := strings.Index(handler, "."); index != -1 {
+		typeInfo := fileSetInfo.Type(handler[:index])
+		if typeInfo == nil {
+			return nil
+		}
+		return typeInfo.Receiver(handler[index+1:])
+	}
+	for _, fileInfo := range fileSetInfo.FilesInfo() {
+		for _, function := range fileInfo.Functions() {
+			if function.Name == handler && function.ReceiverTypeName == "" {
+				return function
+			}
+		}
+	}
+	return nil
+}
+
+// sourceTypeName returns field type name, source package types are qualified if needed and used packages are imported
+func (g *clientGenerator) sourceTypeName(fileSetInfo *toolbox.FileSetInfo, field *toolbox.FieldInfo, qualifier string) string {
+	typeName := field.TypeName
+	if field.IsPointer && !strings.HasPrefix(typeName, "*") {
+		typeName = "*" + typeName
+	}
+	var result = new(bytes.Buffer)
+	var identifier = new(bytes.Buffer)
+	var qualified bool
+	flush := func() {
+		name := identifier.String()
+		identifier.Reset()
+		if name == "" {
+			return
+		}
+		if qualified {
+			result.WriteString(name)
+			return
+		}
+		if qualifier != "" && fileSetInfo.Type(name) != nil {
+			g.imports[qualifier] = g.options.SourceImport
+			result.WriteString(qualifier + ".")
+		}
+		result.WriteString(name)
+	}
+	for _, r := range typeName {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			identifier.WriteRune(r)
+			continue
+		}
+		if r == '.' {
+			pkg := identifier.String()
+			identifier.Reset()
+			g.imports[pkg] = importPath(fileSetInfo, pkg)
+			result.WriteString(pkg + ".")
+			qualified = true
+			continue
+		}
+		flush()
+		qualified = false
+		result.WriteRune(r)
+	}
+	flush()
+	return result.String()
+}
+
+// importPath returns import path of the package name used by source files
+func importPath(fileSetInfo *toolbox.FileSetInfo, name string) string {
+	for _, fileInfo := range fileSetInfo.FilesInfo() {
+		if importInfo := fileInfo.Import(name); importInfo != nil {
+			return importInfo.Path
+		}
+	}
+	return name
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// routingMethod returns client method for the service routing with handler signature resolved with reflection
+func (g *clientGenerator) routingMethod(routing *toolbox.ServiceRouting) (*clientMethod, error) {
+	handlerType := reflect.TypeOf(routing.Handler)
+	if handlerType == nil || handlerType.Kind() != reflect.Func {
+		return nil, fmt.Errorf("invalid %v %v handler type: %T", routing.HTTPMethod, routing.URI, routing.Handler)
+	}
+	method := &clientMethod{name: handlerName(routing.Handler), httpMethod: strings.ToUpper(routing.HTTPMethod), URI: routing.URI}
+	for i, name := range routing.Parameters {
+		if i >= handlerType.NumIn() {
+			return nil, fmt.Errorf("%v %v handler has no parameter for %v", routing.HTTPMethod, routing.URI, name)
+		}
+		parameterType := handlerType.In(i)
+		parameter := &clientParameter{name: name}
+		variadic := handlerType.IsVariadic() && i == handlerType.NumIn()-1
+		if variadic {
+			parameter.typeName = "..." + g.typeName(parameterType.Elem())
+		} else {
+			parameter.typeName = g.typeName(parameterType)
+		}
+		baseType := parameterType
+		for baseType.Kind() == reflect.Ptr {
+			baseType = baseType.Elem()
+		}
+		switch baseType.Kind() {
+		case reflect.Struct:
+			parameter.isBody = baseType != timeType
+			parameter.isObject = parameter.isBody
+		case reflect.Map:
+			parameter.isBody = !variadic
+			parameter.isObject = !variadic
+		case reflect.Slice, reflect.Array:
+			parameter.isBody = !variadic
+		}
+		method.parameters = append(method.parameters, parameter)
+	}
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if handlerType.NumOut() > 0 && handlerType.Out(0) != errorType {
+		method.resultType = g.typeName(handlerType.Out(0))
+	}
+	return method, nil
+}
+
+// handlerName returns handler function or method name, empty for function literal
+func handlerName(handler interface{}) string {
+	function := runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if function == nil {
+		return ""
+	}
+	name := strings.TrimSuffix(function.Name(), "-fm")
+	name = name[strings.LastIndex(name, ".")+1:]
+	if strings.HasPrefix(name, "func") || !isExported(name) {
+		return ""
+	}
+	return name
+}
+
+// typeName returns Go type name, types outside PackagePath are qualified and imported
+func (g *clientGenerator) typeName(sourceType reflect.Type) string {
+	if sourceType.Name() != "" {
+		if sourceType.PkgPath() == "" || sourceType.PkgPath() == g.options.PackagePath {
+			return sourceType.Name()
+		}
+		pkg := path.Base(sourceType.PkgPath())
+		g.imports[pkg] = sourceType.PkgPath()
+		return pkg + "." + sourceType.Name()
+	}
+	switch sourceType.Kind() {
+	case reflect.Ptr:
+		return "*" + g.typeName(sourceType.Elem())
+	case reflect.Slice:
+		return "[]" + g.typeName(sourceType.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%v", sourceType.Len(), g.typeName(sourceType.Elem()))
+	case reflect.Map:
+		return "map[" + g.typeName(sourceType.Key()) + "]" + g.typeName(sourceType.Elem())
+	case reflect.Interface:
+		if sourceType.NumMethod() == 0 {
+			return "interface{}"
+		}
+	}
+	return sourceType.String()
+}
+
+// uniqueMethodNames sets missing or duplicated method names with HTTP method and URI derived name
+func uniqueMethodNames(methods []*clientMethod) error {
+	var counts = make(map[string]int)
+	for _, method := range methods {
+		counts[method.name]++
+	}
+	var names = make(map[string]bool)
+	for _, method := range methods {
+		if method.name == "" || counts[method.name] > 1 {
+			method.name = routeMethodName(method.httpMethod, method.URI)
+		}
+		if names[method.name] {
+			return fmt.Errorf("duplicated client method %v for %v %v, set route name", method.name, method.httpMethod, method.URI)
+		}
+		names[method.name] = true
+	}
+	return nil
+}
+
+// routeMethodName returns method name composed of HTTP method and URI, i.e. GetV1UsersById
+func routeMethodName(httpMethod, URI string) string {
+	var result = strings.ToUpper(httpMethod[:1]) + strings.ToLower(httpMethod[1:])
+	for _, segment := range pathSegments(URI) {
+		prefix := ""
+		if strings.HasPrefix(segment, "{") || strings.HasPrefix(segment, "*") {
+			prefix = "By"
+			segment = strings.Split(strings.Trim(segment, "{}*"), ":")[0]
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			result += prefix + strings.ToUpper(word[:1]) + word[1:]
+			prefix = ""
+		}
+	}
+	return result
+}
+
+func pathSegments(URI string) []string {
+	URI = strings.Trim(URI, "/")
+	if URI == "" {
+		return nil
+	}
+	return strings.Split(URI, "/")
+}
+
+// pathParameterNames returns URI template parameter names: {name}, {name:type} and *name catch-all
+func pathParameterNames(URI string) map[string]bool {
+	var result = make(map[string]bool)
+	for _, segment := range pathSegments(URI) {
+		if strings.HasPrefix(segment, "*") {
+			result[segment[1:]] = true
+			continue
+		}
+		depth, start := 0, 0
+		for i, r := range segment {
+			switch r {
+			case '{':
+				if depth == 0 {
+					start = i + 1
+				}
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					result[strings.Split(segment[start:i], ":")[0]] = true
+				}
+			}
+		}
+	}
+	return result
+}
+
+var reservedIdentifiers = map[string]bool{"c": true, "result": true, "response": true, "URL": true, "err": true, "toolbox": true}
+
+// identifier returns valid Go identifier for route parameter name
+func identifier(name string) string {
+	result := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if result == "" || unicode.IsDigit(rune(result[0])) {
+		result = "_" + result
+	}
+	if token.IsKeyword(result) || reservedIdentifiers[result] {
+		result += "Param"
+	}
+	return result
+}
+
+func (g *clientGenerator) generateClient(code *bytes.Buffer) {
+	client := g.options.Client
+	code.WriteString(fmt.Sprintf("// %v represents typed HTTP client\n", client))
+	code.WriteString(fmt.Sprintf("type %v struct {\n\tBaseURL string\n\tclient  *toolbox.ToolboxHTTPClient\n}\n\n", client))
+	code.WriteString(fmt.Sprintf("// New%v creates a client calling service at baseURL\n", client))
+	code.WriteString(fmt.Sprintf("func New%v(baseURL string, options ...*toolbox.HttpOptions) (*%v, error) {\n", client, client))
+	code.WriteString("\tclient, err := toolbox.NewToolboxHTTPClient(options...)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	code.WriteString(fmt.Sprintf("\treturn &%v{BaseURL: baseURL, client: client}, nil\n}\n\n", client))
+}
+
+func (g *clientGenerator) generateMethod(code *bytes.Buffer, method *clientMethod) error {
+	pathNames := pathParameterNames(method.URI)
+	var signature = make([]string, 0)
+	var pathValues = make([]string, 0)
+	var queryValues = make([]string, 0)
+	var bodyValue = "nil"
+	var bodyName string
+	for _, parameter := range method.parameters {
+		if strings.HasPrefix(parameter.name, "@") {
+			continue
+		}
+		parameter.identifier = identifier(parameter.name)
+		signature = append(signature, parameter.identifier+" "+parameter.typeName)
+		value := fmt.Sprintf("%q: %v", parameter.name, parameter.identifier)
+		switch {
+		case pathNames[parameter.name]:
+			pathValues = append(pathValues, value)
+		case parameter.isBody && bodyValue == "nil":
+			bodyValue, bodyName = parameter.identifier, parameter.name
+		case parameter.isObject:
+			return fmt.Errorf("%v %v: %v parameter can not be sent as query parameter, %v is already the request body", method.httpMethod, method.URI, parameter.name, bodyName)
+		default:
+			queryValues = append(queryValues, value)
+		}
+	}
+	results := "error"
+	if method.resultType != "" {
+		results = "(" + method.resultType + ", error)"
+	}
+	code.WriteString(fmt.Sprintf("// %v calls %v %v\n", method.name, method.httpMethod, method.URI))
+	code.WriteString(fmt.Sprintf("func (c *%v) %v(%v) %v {\n", g.options.Client, method.name, strings.Join(signature, ", "), results))
+	if method.resultType != "" {
+		code.WriteString(fmt.Sprintf("\tvar result %v\n", method.resultType))
+		code.WriteString("\tresponse := &toolbox.ClientResponse{Value: &result}\n")
+	} else {
+		code.WriteString("\tresponse := &toolbox.ClientResponse{}\n")
+	}
+	code.WriteString(fmt.Sprintf("\tURL := toolbox.ClientURL(c.BaseURL, %q, %v, %v)\n", method.URI, parametersMap(pathValues), parametersMap(queryValues)))
+	code.WriteString(fmt.Sprintf("\terr := c.client.Request(%q, URL, %v, response, toolbox.DefaultEncoderFactory, toolbox.DefaultDecoderFactory)\n", method.httpMethod, bodyValue))
+	if method.resultType != "" {
+		code.WriteString("\treturn result, response.Check(err)\n}\n\n")
+	} else {
+		code.WriteString("\treturn response.Check(err)\n}\n\n")
+	}
+	return nil
+}
+
+func parametersMap(values []string) string {
+	if len(values) == 0 {
+		return "nil"
+	}
+	return "map[string]interface{}{" + strings.Join(values, ", ") + "}"
+}
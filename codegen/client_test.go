@@ -0,0 +1,149 @@
+package codegen_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/codegen"
+	"github.com/viant/toolbox/codegen/test/client"
+)
+
+func TestGenerateClient(t *testing.T) {
+	expected, err := ioutil.ReadFile("test/client/service_client.go")
+	if !assert.Nil(t, err) {
+		return
+	}
+	{ //handler signatures resolved with reflection
+		code, err := codegen.GenerateClient(&codegen.ClientOptions{
+			PackagePath: "github.com/viant/toolbox/codegen/test/client",
+			Client:      "ServiceClient",
+			Routings:    client.Routings(nil),
+		})
+		if assert.Nil(t, err) {
+			assert.Equal(t, string(expected), string(code), "generated code is out of date with test/client/service_client.go")
+		}
+	}
+	{ //handler signatures resolved with FileSetInfo
+		code, err := codegen.GenerateClient(&codegen.ClientOptions{
+			Source: "test/client",
+			Client: "ServiceClient",
+			Routes: []*codegen.ClientRoute{
+				{HTTPMethod: "GET", URI: "/v1/users/{id:int}", Handler: "Service.GetUser", Parameters: []string{"id", "verbose"}},
+				{HTTPMethod: "POST", URI: "/v1/users", Handler: "Service.CreateUser", Parameters: []string{"user"}},
+				{HTTPMethod: "GET", URI: "/v1/users", Handler: "Service.ListUsers", Parameters: []string{"role", "ids"}},
+				{HTTPMethod: "DELETE", URI: "/v1/files/*path", Handler: "Service.DeleteFile", Parameters: []string{"path"}},
+			},
+		})
+		if assert.Nil(t, err) {
+			assert.Equal(t, string(expected), string(code))
+		}
+	}
+	{ //handlers used from other package
+		code, err := codegen.GenerateClient(&codegen.ClientOptions{
+			Package:      "api",
+			Source:       "test/client",
+			SourceImport: "github.com/viant/toolbox/codegen/test/client",
+			Routes: []*codegen.ClientRoute{
+				{Name: "Find", HTTPMethod: "GET", URI: "/v1/users/{id:int}", Handler: "Service.GetUser", Parameters: []string{"id", "@requestID"}},
+			},
+		})
+		if assert.Nil(t, err) {
+			assert.Contains(t, string(code), "package api")
+			assert.Contains(t, string(code), `"github.com/viant/toolbox/codegen/test/client"`)
+			assert.Contains(t, string(code), "func (c *Client) Find(id int) (*client.User, error)")
+		}
+	}
+	{ //duplicated handler names use HTTP method and URI
+		service := client.NewService(nil)
+		code, err := codegen.GenerateClient(&codegen.ClientOptions{
+			Package: "api",
+			Routings: []toolbox.ServiceRouting{
+				{HTTPMethod: "GET", URI: "/v1/users/{id}", Handler: service.GetUser, Parameters: []string{"id", "verbose"}},
+				{HTTPMethod: "GET", URI: "/v2/users/{id}", Handler: service.GetUser, Parameters: []string{"id", "verbose"}},
+				{HTTPMethod: "GET", URI: "/v1/status", Handler: func() string { return "ok" }},
+			},
+		})
+		if assert.Nil(t, err) {
+			assert.Contains(t, string(code), "func (c *Client) GetV1UsersById(id int, verbose bool) (*client.User, error)")
+			assert.Contains(t, string(code), "func (c *Client) GetV2UsersById(id int, verbose bool) (*client.User, error)")
+			assert.Contains(t, string(code), "func (c *Client) GetV1Status() (string, error)")
+		}
+	}
+
+	_, err = codegen.GenerateClient(&codegen.ClientOptions{Source: "test/client"})
+	assert.NotNil(t, err)
+	_, err = codegen.GenerateClient(&codegen.ClientOptions{Source: "test/client", Routes: []*codegen.ClientRoute{{HTTPMethod: "GET", URI: "/", Handler: "Service.Missing"}}})
+	assert.NotNil(t, err)
+	_, err = codegen.GenerateClient(&codegen.ClientOptions{Package: "api", Routings: []toolbox.ServiceRouting{{HTTPMethod: "GET", URI: "/", Handler: "invalid"}}})
+	assert.NotNil(t, err)
+	_, err = codegen.GenerateClient(&codegen.ClientOptions{Package: "api", Routings: []toolbox.ServiceRouting{{HTTPMethod: "POST", URI: "/v1/users", Handler: func(user *client.User, filter map[string]string) error { return nil }, Parameters: []string{"user", "filter"}}}})
+	if assert.NotNil(t, err, "second object parameter") {
+		assert.Contains(t, err.Error(), "filter parameter can not be sent as query parameter")
+	}
+}
+
+func TestGeneratedClient(t *testing.T) {
+	created := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	service := client.NewService([]*client.User{
+		{ID: 1, Name: "Bob", Role: "admin", Created: created},
+		{ID: 2, Name: "Ann", Role: "user", Created: created},
+	}, "docs/a b.txt")
+	router := toolbox.NewServiceRouter(client.Routings(service)...)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_ = router.Route(writer, request)
+	}))
+	defer server.Close()
+
+	serviceClient, err := client.NewServiceClient(server.URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	user, err := serviceClient.GetUser(1, true)
+	if assert.Nil(t, err) && assert.NotNil(t, user) {
+		assert.Equal(t, "Bob", user.Name)
+		assert.Equal(t, "admin", user.Role)
+	}
+	user, err = serviceClient.GetUser(1, false)
+	if assert.Nil(t, err) && assert.NotNil(t, user) {
+		assert.Equal(t, "", user.Role)
+	}
+	_, err = serviceClient.GetUser(10, true)
+	if httpError, ok := err.(*toolbox.HTTPError); assert.True(t, ok, "expected *toolbox.HTTPError, but had %T", err) {
+		assert.Equal(t, http.StatusNotFound, httpError.StatusCode)
+		assert.Equal(t, "user_not_found", httpError.Code)
+		assert.Equal(t, "user 10 was not found", httpError.Message)
+	}
+
+	user, err = serviceClient.CreateUser(&client.User{ID: 3, Name: "Tom", Role: "user", Created: created.AddDate(0, 1, 0)})
+	if assert.Nil(t, err) && assert.NotNil(t, user) {
+		assert.Equal(t, 3, user.ID)
+	}
+	_, err = serviceClient.CreateUser(&client.User{ID: 4})
+	if httpError, ok := err.(*toolbox.HTTPError); assert.True(t, ok, "expected *toolbox.HTTPError, but had %T", err) {
+		assert.Equal(t, http.StatusBadRequest, httpError.StatusCode)
+		assert.Equal(t, toolbox.ValidationErrorCode, httpError.Code)
+	}
+
+	users, err := serviceClient.ListUsers("user", 1, 2, 3)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(users)) {
+		assert.Equal(t, 2, users[0].ID)
+		assert.Equal(t, 3, users[1].ID)
+	}
+	users, err = serviceClient.ListUsers("admin", 1)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(users)) {
+		assert.Equal(t, 1, users[0].ID)
+	}
+
+	assert.Nil(t, serviceClient.DeleteFile("docs/a b.txt"))
+	err = serviceClient.DeleteFile("docs/a b.txt")
+	if httpError, ok := err.(*toolbox.HTTPError); assert.True(t, ok, "expected *toolbox.HTTPError, but had %T", err) {
+		assert.Equal(t, http.StatusNotFound, httpError.StatusCode)
+		assert.Equal(t, "not_found", httpError.Code)
+	}
+}
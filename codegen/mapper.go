@@ -0,0 +1,377 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// MapperOptions represents FromMap/ToMap methods generator options
+type MapperOptions struct {
+	Source string   //source directory with go files
+	Types  []string //struct type names to generate methods for
+	KeyTag string   //map key tag, name by default, same as toolbox.NewConverter
+	Dest   string   //optional destination file
+}
+
+// GenerateMapper generates MapperKeyTag, FromMap and ToMap methods for selected struct types, generated code follows toolbox.Converter key tag,
+// default, required, converter and date layout conventions and uses the supplied converter (DefaultConverter if nil) date layout, registry, required enforcement and collect all errors mode,
+// if Dest is specified generated code is also written to that file.
+func GenerateMapper(options *MapperOptions) ([]byte, error) {
+	if len(options.Types) == 0 {
+		return nil, fmt.Errorf("types were empty")
+	}
+	if options.KeyTag == "" {
+		options.KeyTag = "name"
+	}
+	fileSetInfo, err := toolbox.NewFileSetInfo(options.Source)
+	if err != nil {
+		return nil, err
+	}
+	generator := &mapperGenerator{
+		options:   options,
+		generated: make(map[string]bool),
+		imports:   make(map[string]bool),
+	}
+	for _, typeName := range options.Types {
+		generator.generated[typeName] = true
+	}
+	var packageName string
+	var body = new(bytes.Buffer)
+	for _, typeName := range options.Types {
+		typeInfo := fileSetInfo.Type(typeName)
+		if typeInfo == nil {
+			return nil, fmt.Errorf("failed to lookup type: %v in %v", typeName, options.Source)
+		}
+		if !typeInfo.IsStruct {
+			return nil, fmt.Errorf("unsupported type: %v, expected struct", typeName)
+		}
+		packageName = typeInfo.Package
+		generator.generateKeyTag(body, typeInfo)
+		generator.generateFromMap(body, typeInfo)
+		generator.generateToMap(body, typeInfo)
+	}
+	var code = new(bytes.Buffer)
+	code.WriteString("// Code generated by toolbox codegen. DO NOT EDIT.\n\n")
+	code.WriteString(fmt.Sprintf("package %v\n\n", packageName))
+	code.WriteString("import (\n")
+	for _, pkg := range []string{"errors", "fmt", "reflect", "strings", "time", "github.com/viant/toolbox"} {
+		if generator.imports[pkg] {
+			code.WriteString(fmt.Sprintf("\t%q\n", pkg))
+		}
+	}
+	code.WriteString(")\n\n")
+	code.Write(body.Bytes())
+	result, err := format.Source(code.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %v\n%s", err, code.Bytes())
+	}
+	if options.Dest != "" {
+		if err = ioutil.WriteFile(options.Dest, result, 0644); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+type mapperGenerator struct {
+	options   *MapperOptions
+	generated map[string]bool
+	imports   map[string]bool
+}
+
+// mappedField represents a struct field with its map conversion settings
+type mappedField struct {
+	*toolbox.FieldInfo
+	key        string
+	tag        reflect.StructTag
+	typeName   string
+	isPointer  bool
+	dateLayout string
+}
+
+func (g *mapperGenerator) mappedFields(typeInfo *toolbox.TypeInfo) []*mappedField {
+	var result = make([]*mappedField, 0)
+	for _, field := range typeInfo.Fields() {
+		if !isExported(field.Name) {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(field.Tag, "`"))
+		if strings.EqualFold(tag.Get("transient"), "true") {
+			continue
+		}
+		mapped := &mappedField{FieldInfo: field, tag: tag, key: field.Name}
+		if key := strings.Split(tag.Get(g.options.KeyTag), ",")[0]; key != "" {
+			mapped.key = key
+		}
+		mapped.typeName = field.TypeName
+		mapped.isPointer = field.IsPointer
+		if strings.HasPrefix(mapped.typeName, "*") {
+			mapped.typeName = mapped.typeName[1:]
+			mapped.isPointer = true
+		}
+		if layout := tag.Get(toolbox.DateLayoutKeyword); layout != "" {
+			mapped.dateLayout = layout
+		} else if dateFormat := tag.Get(toolbox.DateFormatKeyword); dateFormat != "" {
+			mapped.dateLayout = toolbox.DateFormatToLayout(dateFormat)
+		}
+		result = append(result, mapped)
+	}
+	return result
+}
+
+func isExported(name string) bool {
+	return name != "" && strings.ToUpper(name[:1]) == name[:1]
+}
+
+var intTypes = map[string]bool{"int": true, "int8": true, "int16": true, "int32": true, "int64": true, "uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true}
+var floatTypes = map[string]bool{"float32": true, "float64": true}
+
+func (g *mapperGenerator) generateKeyTag(code *bytes.Buffer, typeInfo *toolbox.TypeInfo) {
+	code.WriteString(fmt.Sprintf("// MapperKeyTag returns map key tag used by %v FromMap and ToMap\n", typeInfo.Name))
+	code.WriteString(fmt.Sprintf("func (t %v) MapperKeyTag() string {\n\treturn %q\n}\n\n", typeInfo.Name, g.options.KeyTag))
+}
+
+func (g *mapperGenerator) generateFromMap(code *bytes.Buffer, typeInfo *toolbox.TypeInfo) {
+	g.imports["github.com/viant/toolbox"] = true
+	var fields = g.mappedFields(typeInfo)
+	var body = new(bytes.Buffer)
+	var tracked = make([]*mappedField, 0)
+	for _, field := range fields {
+		if field.IsAnonymous {
+			g.generateEmbeddedFromMap(body, field)
+			continue
+		}
+		if field.key == "-" {
+			continue
+		}
+		_, hasDefault := field.tag.Lookup("default")
+		isRequired := toolbox.AsBoolean(field.tag.Get("required"))
+		isTracked := hasDefault || isRequired
+		if isTracked {
+			tracked = append(tracked, field)
+		}
+	}
+	for _, field := range tracked {
+		body.WriteString(fmt.Sprintf("\tvar has%v bool\n", field.Name))
+	}
+	g.imports["strings"] = true
+	body.WriteString("\tfor key, value := range source {\n")
+	body.WriteString("\t\tif value == nil {\n\t\t\tcontinue\n\t\t}\n")
+	body.WriteString("\t\tswitch strings.ToLower(key) {\n")
+	for _, field := range fields {
+		if field.IsAnonymous || field.key == "-" {
+			continue
+		}
+		body.WriteString(fmt.Sprintf("\t\tcase %q:\n", strings.ToLower(field.key)))
+		for _, trackedField := range tracked {
+			if trackedField == field {
+				body.WriteString(fmt.Sprintf("\t\t\thas%v = true\n", field.Name))
+			}
+		}
+		g.generateFieldAssignment(body, field, "key", "value", "\t\t\t")
+	}
+	body.WriteString("\t\t}\n\t}\n")
+	for _, field := range tracked {
+		if defaultValue, ok := field.tag.Lookup("default"); ok {
+			body.WriteString(fmt.Sprintf("\tif !has%v {\n", field.Name))
+			body.WriteString(fmt.Sprintf("\t\tvar value interface{} = %q\n", defaultValue))
+			g.generateFieldAssignment(body, field, fmt.Sprintf("%q", field.key), "value", "\t\t")
+			body.WriteString("\t}\n")
+			continue
+		}
+		g.imports["errors"] = true
+		g.imports["reflect"] = true
+		body.WriteString(fmt.Sprintf("\tif !has%v && converter.EnforceRequired {\n", field.Name))
+		body.WriteString(fmt.Sprintf("\t\terr := toolbox.WithFieldKeyPath(%q, %q, toolbox.NewConversionError(nil, reflect.TypeOf(t.%v), errors.New(\"required field is missing\")))\n", field.Name, field.key, field.Name))
+		body.WriteString("\t\tif !converter.CollectError(&collected, err) {\n\t\t\treturn err\n\t\t}\n")
+		body.WriteString("\t}\n")
+	}
+
+	code.WriteString(fmt.Sprintf("// FromMap populates %v from a map, it implements toolbox.FromMapper\n", typeInfo.Name))
+	code.WriteString(fmt.Sprintf("func (t *%v) FromMap(source map[string]interface{}, converter *toolbox.Converter) error {\n", typeInfo.Name))
+	code.WriteString("\tif converter == nil {\n\t\tconverter = toolbox.DefaultConverter\n\t}\n")
+	code.WriteString(fmt.Sprintf("\t*t = %v{}\n", typeInfo.Name))
+	collects := strings.Contains(body.String(), "&collected")
+	if collects {
+		code.WriteString("\tvar collected toolbox.ConversionErrors\n")
+	}
+	code.Write(body.Bytes())
+	if collects {
+		code.WriteString("\tif len(collected) > 0 {\n\t\treturn collected.SortByPath()\n\t}\n")
+	}
+	code.WriteString("\treturn nil\n}\n\n")
+}
+
+func (g *mapperGenerator) generateEmbeddedFromMap(code *bytes.Buffer, field *mappedField) {
+	target := "&t." + field.Name
+	if field.isPointer {
+		code.WriteString(fmt.Sprintf("\tif t.%v == nil {\n\t\tt.%v = &%v{}\n\t}\n", field.Name, field.Name, field.typeName))
+		target = "t." + field.Name
+	}
+	call := fmt.Sprintf("converter.AssignConverted(%v, source)", target)
+	if g.generated[field.typeName] {
+		call = fmt.Sprintf("t.%v.FromMap(source, converter)", field.Name)
+	}
+	code.WriteString(fmt.Sprintf("\tif err := %v; err != nil && !converter.CollectError(&collected, err) {\n\t\treturn err\n\t}\n", call))
+}
+
+func (g *mapperGenerator) conversionError(field *mappedField, key, value string) string {
+	g.imports["reflect"] = true
+	return fmt.Sprintf("toolbox.WithFieldKeyPath(%q, %v, toolbox.NewConversionError(%v, reflect.TypeOf(t.%v), err))", field.Name, key, value, field.Name)
+}
+
+func (g *mapperGenerator) generateFieldAssignment(code *bytes.Buffer, field *mappedField, key, value, indent string) {
+	write := func(format string, args ...interface{}) {
+		code.WriteString(indent + fmt.Sprintf(format, args...) + "\n")
+	}
+	//fail writes error handling of err variable, the error is returned unless converter collects all errors
+	fail := func(expression string) {
+		write("\terr = %v", expression)
+		write("\tif !converter.CollectError(&collected, err) {")
+		write("\t\treturn err")
+		write("\t}")
+	}
+	assign := func(expression string) {
+		if field.isPointer {
+			write("\tconverted := %v", expression)
+			write("\tt.%v = &converted", field.Name)
+			return
+		}
+		write("\tt.%v = %v", field.Name, expression)
+	}
+	if converterName := field.tag.Get("converter"); converterName != "" {
+		g.imports["fmt"] = true
+		write("if convert, ok := converter.GetNamedConverter(%q); !ok {", converterName)
+		write("\terr := toolbox.WithFieldKeyPath(%q, %v, fmt.Errorf(\"unknown converter: %v\"))", field.Name, key, converterName)
+		write("\tif !converter.CollectError(&collected, err) {")
+		write("\t\treturn err")
+		write("\t}")
+		write("} else if err := convert(&t.%v, %v); err != nil {", field.Name, value)
+		fail(g.conversionError(field, key, value))
+		write("}")
+		return
+	}
+	dateLayout := "converter.DateLayout"
+	if field.dateLayout != "" {
+		dateLayout = fmt.Sprintf("%q", field.dateLayout)
+	}
+	switch {
+	case field.typeName == "string" && !field.IsSlice && !field.IsMap:
+		if field.isPointer {
+			write("converted := toolbox.AsString(%v)", value)
+			write("t.%v = &converted", field.Name)
+		} else {
+			write("t.%v = toolbox.AsString(%v)", field.Name, value)
+		}
+	case intTypes[field.typeName] && !field.IsSlice && !field.IsMap:
+		write("if intValue, err := toolbox.ToInt(%v); err != nil {", value)
+		fail(g.conversionError(field, key, value))
+		write("} else {")
+		assign(fmt.Sprintf("%v(intValue)", field.typeName))
+		write("}")
+	case floatTypes[field.typeName] && !field.IsSlice && !field.IsMap:
+		write("if floatValue, err := toolbox.ToFloat(%v); err != nil {", value)
+		fail(g.conversionError(field, key, value))
+		write("} else {")
+		assign(fmt.Sprintf("%v(floatValue)", field.typeName))
+		write("}")
+	case field.typeName == "bool" && !field.IsSlice && !field.IsMap:
+		write("if boolValue, err := toolbox.ToBoolean(%v); err != nil {", value)
+		fail(g.conversionError(field, key, value))
+		write("} else {")
+		assign("boolValue")
+		write("}")
+	case field.typeName == "time.Time" && !field.IsSlice && !field.IsMap:
+		write("if timeValue, err := toolbox.ToTime(%v, %v); err != nil {", value, dateLayout)
+		fail(g.conversionError(field, key, value))
+		write("} else if timeValue != nil {")
+		if field.isPointer {
+			write("\tt.%v = timeValue", field.Name)
+		} else {
+			write("\tt.%v = *timeValue", field.Name)
+		}
+		write("}")
+	default:
+		fieldConverter := "converter"
+		if field.dateLayout != "" {
+			write("fieldConverter := *converter")
+			write("fieldConverter.DateLayout = %v", dateLayout)
+			fieldConverter = "fieldConverter"
+		}
+		write("if err := %v.AssignConverted(&t.%v, %v); err != nil {", fieldConverter, field.Name, value)
+		fail(fmt.Sprintf("toolbox.WithFieldKeyPath(%q, %v, err)", field.Name, key))
+		write("}")
+	}
+}
+
+func (g *mapperGenerator) generateToMap(code *bytes.Buffer, typeInfo *toolbox.TypeInfo) {
+	var fields = g.mappedFields(typeInfo)
+	var body = new(bytes.Buffer)
+	//usesDateLayout is set when time field without dateLayout tag is formatted with converter date layout
+	var usesDateLayout bool
+	body.WriteString("\tvar result = make(map[string]interface{})\n")
+	for _, field := range fields {
+		if !field.IsAnonymous {
+			continue
+		}
+		if field.isPointer {
+			body.WriteString(fmt.Sprintf("\tif t.%v != nil {\n", field.Name))
+		}
+		if g.generated[field.typeName] {
+			body.WriteString(fmt.Sprintf("\tfor key, value := range t.%v.ToMap(converter) {\n\t\tresult[key] = value\n\t}\n", field.Name))
+		} else {
+			body.WriteString(fmt.Sprintf("\tif embedded, err := converter.AsMapValue(t.%v); err == nil {\n", field.Name))
+			body.WriteString("\t\tif embeddedMap, ok := embedded.(map[string]interface{}); ok {\n")
+			body.WriteString("\t\t\tfor key, value := range embeddedMap {\n\t\t\t\tresult[key] = value\n\t\t\t}\n")
+			body.WriteString("\t\t}\n\t}\n")
+		}
+		if field.isPointer {
+			body.WriteString("\t}\n")
+		}
+	}
+	for _, field := range fields {
+		if field.IsAnonymous || field.key == "-" {
+			continue
+		}
+		isPrimitive := !field.IsSlice && !field.IsMap && (field.typeName == "string" || field.typeName == "bool" || intTypes[field.typeName] || floatTypes[field.typeName])
+		switch {
+		case isPrimitive:
+			body.WriteString(fmt.Sprintf("\tresult[%q] = t.%v\n", field.key, field.Name))
+		case field.typeName == "time.Time" && !field.IsSlice && !field.IsMap:
+			dateLayout := "dateLayout"
+			if field.dateLayout != "" {
+				dateLayout = fmt.Sprintf("%q", field.dateLayout)
+			} else {
+				usesDateLayout = true
+			}
+			if field.isPointer {
+				body.WriteString(fmt.Sprintf("\tif t.%v != nil {\n\t\tresult[%q] = t.%v.Format(%v)\n\t}\n", field.Name, field.key, field.Name, dateLayout))
+			} else {
+				body.WriteString(fmt.Sprintf("\tresult[%q] = t.%v.Format(%v)\n", field.key, field.Name, dateLayout))
+			}
+		case g.generated[field.typeName] && !field.IsSlice && !field.IsMap:
+			if field.isPointer {
+				body.WriteString(fmt.Sprintf("\tif t.%v != nil {\n\t\tresult[%q] = t.%v.ToMap(converter)\n\t}\n", field.Name, field.key, field.Name))
+			} else {
+				body.WriteString(fmt.Sprintf("\tresult[%q] = t.%v.ToMap(converter)\n", field.key, field.Name))
+			}
+		default:
+			body.WriteString(fmt.Sprintf("\tif value, err := converter.AsMapValue(t.%v); err == nil {\n\t\tresult[%q] = value\n\t}\n", field.Name, field.key))
+		}
+	}
+	code.WriteString(fmt.Sprintf("// ToMap returns map representation of %v, it implements toolbox.ToMapper\n", typeInfo.Name))
+	code.WriteString(fmt.Sprintf("func (t %v) ToMap(converter *toolbox.Converter) map[string]interface{} {\n", typeInfo.Name))
+	code.WriteString("\tif converter == nil {\n\t\tconverter = toolbox.DefaultConverter\n\t}\n")
+	if usesDateLayout {
+		g.imports["time"] = true
+		code.WriteString("\tdateLayout := converter.DateLayout\n\tif dateLayout == \"\" {\n\t\tdateLayout = time.RFC3339\n\t}\n")
+	}
+	code.Write(body.Bytes())
+	code.WriteString("\treturn result\n}\n\n")
+}
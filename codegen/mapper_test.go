@@ -0,0 +1,218 @@
+package codegen_test
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/codegen"
+	"github.com/viant/toolbox/codegen/test/mapper"
+)
+
+func TestGenerateMapper(t *testing.T) {
+	code, err := codegen.GenerateMapper(&codegen.MapperOptions{
+		Source: "test/mapper",
+		Types:  []string{"Audit", "Item", "Order"},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	expected, err := ioutil.ReadFile("test/mapper/model_mapper.go")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, string(expected), string(code), "generated code is out of date with test/mapper/model_mapper.go")
+
+	_, err = codegen.GenerateMapper(&codegen.MapperOptions{Source: "test/mapper", Types: []string{"Missing"}})
+	assert.NotNil(t, err)
+	_, err = codegen.GenerateMapper(&codegen.MapperOptions{Source: "test/mapper"})
+	assert.NotNil(t, err)
+}
+
+func TestGeneratedMapper(t *testing.T) {
+	var useCases = []struct {
+		description string
+		source      map[string]interface{}
+		expect      func(t *testing.T, order *mapper.Order)
+		hasError    bool
+		errorPath   string
+	}{
+		{
+			description: "all fields",
+			source: map[string]interface{}{
+				"createdBy": "bob",
+				"ID":        "12",
+				"status":    "paid",
+				"paid":      "true",
+				"discount":  2.5,
+				"created":   "2019-03-01",
+				"items": []interface{}{
+					map[string]interface{}{"name": "a", "price": "1.5"},
+				},
+				"attrs": map[string]interface{}{"k": "v"},
+				"main":  map[string]interface{}{"name": "m", "price": 3},
+				"-":     "ignored",
+				"cache": "ignored",
+			},
+			expect: func(t *testing.T, order *mapper.Order) {
+				assert.Equal(t, "bob", order.CreatedBy)
+				assert.Equal(t, 12, order.ID)
+				assert.Equal(t, "paid", order.Status)
+				assert.True(t, order.Paid)
+				assert.EqualValues(t, 2.5, *order.Discount)
+				assert.Equal(t, "2019-03-01", order.Created.Format("2006-01-02"))
+				assert.Nil(t, order.Shipped)
+				assert.Equal(t, []*mapper.Item{{Name: "a", Price: 1.5}}, order.Items)
+				assert.Equal(t, map[string]string{"k": "v"}, order.Attrs)
+				assert.Equal(t, &mapper.Item{Name: "m", Price: 3}, order.Main)
+				assert.Equal(t, "", order.Cache)
+			},
+		},
+		{
+			description: "default value",
+			source:      map[string]interface{}{"id": 1},
+			expect: func(t *testing.T, order *mapper.Order) {
+				assert.Equal(t, "new", order.Status)
+			},
+		},
+		{
+			description: "required field",
+			source:      map[string]interface{}{"status": "new"},
+			hasError:    true,
+			errorPath:   "/id",
+		},
+		{
+			description: "nested conversion error",
+			source: map[string]interface{}{
+				"id":    1,
+				"items": []interface{}{map[string]interface{}{"name": "a", "price": "abc"}},
+			},
+			hasError:  true,
+			errorPath: "/items/0/price",
+		},
+	}
+
+	converter := toolbox.NewConverter("", "name")
+	converter.EnforceRequired = true
+	for _, useCase := range useCases {
+		order := &mapper.Order{Cache: "stale", Items: []*mapper.Item{{Name: "stale"}}}
+		err := order.FromMap(useCase.source, converter)
+		if useCase.hasError {
+			if !assert.NotNil(t, err, useCase.description) {
+				continue
+			}
+			errors, ok := toolbox.AsConversionErrors(err)
+			if assert.True(t, ok, useCase.description) {
+				assert.Equal(t, useCase.errorPath, errors[0].Path, useCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		useCase.expect(t, order)
+	}
+
+	order := &mapper.Order{}
+	assert.Nil(t, order.FromMap(map[string]interface{}{"status": "new", "shipped": "2019-03-02T10:00:00Z"}, nil), "required is not enforced by default")
+	if assert.NotNil(t, order.Shipped) {
+		assert.Equal(t, map[string]interface{}{"name": "a", "price": 1.5, "code": ""}, mapper.Item{Name: "a", Price: 1.5}.ToMap(nil))
+		assert.Equal(t, "2019-03-02T10:00:00Z", order.ToMap(nil)["shipped"])
+	}
+}
+
+func TestGeneratedMapper_Converter(t *testing.T) {
+	converter := toolbox.NewConverter("", "name")
+	converter.RegisterNamedConverter("upper", func(target, source interface{}) error {
+		*(target.(*string)) = strings.ToUpper(toolbox.AsString(source))
+		return nil
+	})
+	shipped := time.Date(2019, 3, 2, 10, 0, 0, 0, time.UTC)
+	order := mapper.Order{
+		Audit:   mapper.Audit{CreatedBy: "bob"},
+		ID:      3,
+		Status:  "new",
+		Created: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC),
+		Shipped: &shipped,
+		Items:   []*mapper.Item{{Name: "a", Price: 1.5}},
+		Main:    &mapper.Item{Name: "m"},
+	}
+	var aMap = make(map[string]interface{})
+	if !assert.Nil(t, converter.AssignConverted(&aMap, order)) {
+		return
+	}
+	assert.Equal(t, "bob", aMap["createdBy"])
+	assert.Equal(t, 3, aMap["id"])
+	assert.Equal(t, "2019-03-01", aMap["created"])
+	assert.Equal(t, "2019-03-02T10:00:00Z", aMap["shipped"])
+	assert.Equal(t, []map[string]interface{}{{"name": "a", "price": 1.5, "code": ""}}, aMap["items"])
+	assert.Equal(t, map[string]interface{}{"name": "m", "price": 0.0, "code": ""}, aMap["main"])
+
+	var cloned = &mapper.Order{}
+	if !assert.Nil(t, converter.AssignConverted(cloned, aMap)) {
+		return
+	}
+	assert.Equal(t, order.ID, cloned.ID)
+	assert.Equal(t, order.Created, cloned.Created)
+	assert.Equal(t, order.Items, cloned.Items)
+	assert.Equal(t, order.Shipped.Unix(), cloned.Shipped.Unix())
+
+	var orders []*mapper.Order
+	if assert.Nil(t, converter.AssignConverted(&orders, []interface{}{map[string]interface{}{"id": 7}})) {
+		assert.Equal(t, 7, orders[0].ID)
+		assert.Equal(t, "new", orders[0].Status)
+	}
+}
+
+func TestGeneratedMapper_ConverterOptions(t *testing.T) {
+	converter := toolbox.NewConverter("2006/01/02", "name")
+	converter.CollectAllErrors = true
+	converter.EnforceRequired = true
+	converter.RegisterNamedConverter("upper", func(target, source interface{}) error {
+		*(target.(*string)) = strings.ToUpper(toolbox.AsString(source))
+		return nil
+	})
+
+	{ //collect all errors and converter scoped named converter
+		order := &mapper.Order{}
+		err := converter.AssignConverted(order, map[string]interface{}{
+			"id":      "abc",
+			"paid":    "maybe",
+			"shipped": "2019/03/02",
+			"items": []interface{}{
+				map[string]interface{}{"name": "a", "code": "ab"},
+				map[string]interface{}{"price": "abc"},
+			},
+		})
+		errors, ok := toolbox.AsConversionErrors(err)
+		if assert.True(t, ok, "expected conversion errors, but had %v", err) {
+			var paths = make([]string, 0)
+			for _, conversionError := range errors {
+				paths = append(paths, conversionError.Path)
+			}
+			assert.Equal(t, []string{"/id", "/items/1/name", "/items/1/price", "/paid"}, paths)
+		}
+		if assert.True(t, len(order.Items) > 0) {
+			assert.Equal(t, "AB", order.Items[0].Code)
+		}
+		if assert.NotNil(t, order.Shipped) {
+			assert.Equal(t, time.Date(2019, 3, 2, 0, 0, 0, 0, time.UTC), *order.Shipped)
+			assert.Equal(t, "2019/03/02", order.ToMap(converter)["shipped"])
+		}
+	}
+
+	{ //custom converter registered for generated type takes precedence
+		local := toolbox.NewConverter("", "name")
+		local.RegisterConverter(reflect.TypeOf(&mapper.Item{}), reflect.TypeOf(map[string]interface{}{}), func(target, source interface{}) error {
+			target.(*mapper.Item).Name = "custom"
+			return nil
+		})
+		item := &mapper.Item{}
+		assert.Nil(t, local.AssignConverted(item, map[string]interface{}{"name": "a"}))
+		assert.Equal(t, "custom", item.Name)
+	}
+}
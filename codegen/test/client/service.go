@@ -0,0 +1,91 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+// User represents a test user
+type User struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name" required:"true"`
+	Role    string    `json:"role,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Service represents a test user service
+type Service struct {
+	users map[int]*User
+	files map[string]bool
+}
+
+// GetUser returns user for id
+func (s *Service) GetUser(id int, verbose bool) (*User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, toolbox.NewHTTPError(http.StatusNotFound, "user_not_found", fmt.Sprintf("user %v was not found", id))
+	}
+	if !verbose {
+		return &User{ID: user.ID, Name: user.Name}, nil
+	}
+	return user, nil
+}
+
+// CreateUser stores a user
+func (s *Service) CreateUser(user *User) *User {
+	s.users[user.ID] = user
+	return user
+}
+
+// ListUsers returns users matching role and ids
+func (s *Service) ListUsers(role string, ids ...int) []*User {
+	var result = make([]*User, 0)
+	for _, user := range s.users {
+		if user.Role != role {
+			continue
+		}
+		if len(ids) > 0 && !toolbox.HasSliceAnyElements(ids, user.ID) {
+			continue
+		}
+		result = append(result, user)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// DeleteFile removes a file
+func (s *Service) DeleteFile(path string) error {
+	if !s.files[path] {
+		return toolbox.NewHTTPError(http.StatusNotFound, "", fmt.Sprintf("file %v was not found", path))
+	}
+	delete(s.files, path)
+	return nil
+}
+
+// NewService creates a test service
+func NewService(users []*User, files ...string) *Service {
+	var result = &Service{users: make(map[int]*User), files: make(map[string]bool)}
+	for _, user := range users {
+		result.users[user.ID] = user
+	}
+	for _, file := range files {
+		result.files[file] = true
+	}
+	return result
+}
+
+// Routings returns service routings
+func Routings(service *Service) []toolbox.ServiceRouting {
+	return []toolbox.ServiceRouting{
+		{HTTPMethod: "GET", URI: "/v1/users/{id:int}", Handler: service.GetUser, Parameters: []string{"id", "verbose"}},
+		{HTTPMethod: "POST", URI: "/v1/users", Handler: service.CreateUser, Parameters: []string{"user"}, Validate: true},
+		{HTTPMethod: "GET", URI: "/v1/users", Handler: service.ListUsers, Parameters: []string{"role", "ids"}},
+		{HTTPMethod: "DELETE", URI: "/v1/files/*path", Handler: service.DeleteFile, Parameters: []string{"path"}},
+	}
+}
@@ -0,0 +1,57 @@
+// Code generated by toolbox codegen. DO NOT EDIT.
+
+package client
+
+import (
+	"github.com/viant/toolbox"
+)
+
+// ServiceClient represents typed HTTP client
+type ServiceClient struct {
+	BaseURL string
+	client  *toolbox.ToolboxHTTPClient
+}
+
+// NewServiceClient creates a client calling service at baseURL
+func NewServiceClient(baseURL string, options ...*toolbox.HttpOptions) (*ServiceClient, error) {
+	client, err := toolbox.NewToolboxHTTPClient(options...)
+	if err != nil {
+		return nil, err
+	}
+	return &ServiceClient{BaseURL: baseURL, client: client}, nil
+}
+
+// GetUser calls GET /v1/users/{id:int}
+func (c *ServiceClient) GetUser(id int, verbose bool) (*User, error) {
+	var result *User
+	response := &toolbox.ClientResponse{Value: &result}
+	URL := toolbox.ClientURL(c.BaseURL, "/v1/users/{id:int}", map[string]interface{}{"id": id}, map[string]interface{}{"verbose": verbose})
+	err := c.client.Request("GET", URL, nil, response, toolbox.DefaultEncoderFactory, toolbox.DefaultDecoderFactory)
+	return result, response.Check(err)
+}
+
+// CreateUser calls POST /v1/users
+func (c *ServiceClient) CreateUser(user *User) (*User, error) {
+	var result *User
+	response := &toolbox.ClientResponse{Value: &result}
+	URL := toolbox.ClientURL(c.BaseURL, "/v1/users", nil, nil)
+	err := c.client.Request("POST", URL, user, response, toolbox.DefaultEncoderFactory, toolbox.DefaultDecoderFactory)
+	return result, response.Check(err)
+}
+
+// ListUsers calls GET /v1/users
+func (c *ServiceClient) ListUsers(role string, ids ...int) ([]*User, error) {
+	var result []*User
+	response := &toolbox.ClientResponse{Value: &result}
+	URL := toolbox.ClientURL(c.BaseURL, "/v1/users", nil, map[string]interface{}{"role": role, "ids": ids})
+	err := c.client.Request("GET", URL, nil, response, toolbox.DefaultEncoderFactory, toolbox.DefaultDecoderFactory)
+	return result, response.Check(err)
+}
+
+// DeleteFile calls DELETE /v1/files/*path
+func (c *ServiceClient) DeleteFile(path string) error {
+	response := &toolbox.ClientResponse{}
+	URL := toolbox.ClientURL(c.BaseURL, "/v1/files/*path", map[string]interface{}{"path": path}, nil)
+	err := c.client.Request("DELETE", URL, nil, response, toolbox.DefaultEncoderFactory, toolbox.DefaultDecoderFactory)
+	return response.Check(err)
+}
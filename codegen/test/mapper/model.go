@@ -0,0 +1,32 @@
+package mapper
+
+import "time"
+
+// Audit represents an embedded audit info
+type Audit struct {
+	CreatedBy string `name:"createdBy"`
+}
+
+// Item represents an order item
+type Item struct {
+	Name  string  `name:"name" required:"true"`
+	Price float64 `name:"price"`
+	Code  string  `name:"code" converter:"upper"`
+}
+
+// Order represents an order
+type Order struct {
+	Audit
+	ID       int        `name:"id" required:"true"`
+	Status   string     `name:"status" default:"new"`
+	Paid     bool       `name:"paid"`
+	Discount *float64   `name:"discount"`
+	Created  time.Time  `name:"created" dateLayout:"2006-01-02"`
+	Shipped  *time.Time `name:"shipped"`
+	Items    []*Item    `name:"items"`
+	Attrs    map[string]string
+	Main     *Item  `name:"main"`
+	Ignored  string `name:"-"`
+	Cache    string `transient:"true"`
+	internal string
+}
@@ -0,0 +1,257 @@
+// Code generated by toolbox codegen. DO NOT EDIT.
+
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/toolbox"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// MapperKeyTag returns map key tag used by Audit FromMap and ToMap
+func (t Audit) MapperKeyTag() string {
+	return "name"
+}
+
+// FromMap populates Audit from a map, it implements toolbox.FromMapper
+func (t *Audit) FromMap(source map[string]interface{}, converter *toolbox.Converter) error {
+	if converter == nil {
+		converter = toolbox.DefaultConverter
+	}
+	*t = Audit{}
+	for key, value := range source {
+		if value == nil {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "createdby":
+			t.CreatedBy = toolbox.AsString(value)
+		}
+	}
+	return nil
+}
+
+// ToMap returns map representation of Audit, it implements toolbox.ToMapper
+func (t Audit) ToMap(converter *toolbox.Converter) map[string]interface{} {
+	if converter == nil {
+		converter = toolbox.DefaultConverter
+	}
+	var result = make(map[string]interface{})
+	result["createdBy"] = t.CreatedBy
+	return result
+}
+
+// MapperKeyTag returns map key tag used by Item FromMap and ToMap
+func (t Item) MapperKeyTag() string {
+	return "name"
+}
+
+// FromMap populates Item from a map, it implements toolbox.FromMapper
+func (t *Item) FromMap(source map[string]interface{}, converter *toolbox.Converter) error {
+	if converter == nil {
+		converter = toolbox.DefaultConverter
+	}
+	*t = Item{}
+	var collected toolbox.ConversionErrors
+	var hasName bool
+	for key, value := range source {
+		if value == nil {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "name":
+			hasName = true
+			t.Name = toolbox.AsString(value)
+		case "price":
+			if floatValue, err := toolbox.ToFloat(value); err != nil {
+				err = toolbox.WithFieldKeyPath("Price", key, toolbox.NewConversionError(value, reflect.TypeOf(t.Price), err))
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			} else {
+				t.Price = float64(floatValue)
+			}
+		case "code":
+			if convert, ok := converter.GetNamedConverter("upper"); !ok {
+				err := toolbox.WithFieldKeyPath("Code", key, fmt.Errorf("unknown converter: upper"))
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			} else if err := convert(&t.Code, value); err != nil {
+				err = toolbox.WithFieldKeyPath("Code", key, toolbox.NewConversionError(value, reflect.TypeOf(t.Code), err))
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			}
+		}
+	}
+	if !hasName && converter.EnforceRequired {
+		err := toolbox.WithFieldKeyPath("Name", "name", toolbox.NewConversionError(nil, reflect.TypeOf(t.Name), errors.New("required field is missing")))
+		if !converter.CollectError(&collected, err) {
+			return err
+		}
+	}
+	if len(collected) > 0 {
+		return collected.SortByPath()
+	}
+	return nil
+}
+
+// ToMap returns map representation of Item, it implements toolbox.ToMapper
+func (t Item) ToMap(converter *toolbox.Converter) map[string]interface{} {
+	if converter == nil {
+		converter = toolbox.DefaultConverter
+	}
+	var result = make(map[string]interface{})
+	result["name"] = t.Name
+	result["price"] = t.Price
+	result["code"] = t.Code
+	return result
+}
+
+// MapperKeyTag returns map key tag used by Order FromMap and ToMap
+func (t Order) MapperKeyTag() string {
+	return "name"
+}
+
+// FromMap populates Order from a map, it implements toolbox.FromMapper
+func (t *Order) FromMap(source map[string]interface{}, converter *toolbox.Converter) error {
+	if converter == nil {
+		converter = toolbox.DefaultConverter
+	}
+	*t = Order{}
+	var collected toolbox.ConversionErrors
+	if err := t.Audit.FromMap(source, converter); err != nil && !converter.CollectError(&collected, err) {
+		return err
+	}
+	var hasID bool
+	var hasStatus bool
+	for key, value := range source {
+		if value == nil {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "id":
+			hasID = true
+			if intValue, err := toolbox.ToInt(value); err != nil {
+				err = toolbox.WithFieldKeyPath("ID", key, toolbox.NewConversionError(value, reflect.TypeOf(t.ID), err))
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			} else {
+				t.ID = int(intValue)
+			}
+		case "status":
+			hasStatus = true
+			t.Status = toolbox.AsString(value)
+		case "paid":
+			if boolValue, err := toolbox.ToBoolean(value); err != nil {
+				err = toolbox.WithFieldKeyPath("Paid", key, toolbox.NewConversionError(value, reflect.TypeOf(t.Paid), err))
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			} else {
+				t.Paid = boolValue
+			}
+		case "discount":
+			if floatValue, err := toolbox.ToFloat(value); err != nil {
+				err = toolbox.WithFieldKeyPath("Discount", key, toolbox.NewConversionError(value, reflect.TypeOf(t.Discount), err))
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			} else {
+				converted := float64(floatValue)
+				t.Discount = &converted
+			}
+		case "created":
+			if timeValue, err := toolbox.ToTime(value, "2006-01-02"); err != nil {
+				err = toolbox.WithFieldKeyPath("Created", key, toolbox.NewConversionError(value, reflect.TypeOf(t.Created), err))
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			} else if timeValue != nil {
+				t.Created = *timeValue
+			}
+		case "shipped":
+			if timeValue, err := toolbox.ToTime(value, converter.DateLayout); err != nil {
+				err = toolbox.WithFieldKeyPath("Shipped", key, toolbox.NewConversionError(value, reflect.TypeOf(t.Shipped), err))
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			} else if timeValue != nil {
+				t.Shipped = timeValue
+			}
+		case "items":
+			if err := converter.AssignConverted(&t.Items, value); err != nil {
+				err = toolbox.WithFieldKeyPath("Items", key, err)
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			}
+		case "attrs":
+			if err := converter.AssignConverted(&t.Attrs, value); err != nil {
+				err = toolbox.WithFieldKeyPath("Attrs", key, err)
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			}
+		case "main":
+			if err := converter.AssignConverted(&t.Main, value); err != nil {
+				err = toolbox.WithFieldKeyPath("Main", key, err)
+				if !converter.CollectError(&collected, err) {
+					return err
+				}
+			}
+		}
+	}
+	if !hasID && converter.EnforceRequired {
+		err := toolbox.WithFieldKeyPath("ID", "id", toolbox.NewConversionError(nil, reflect.TypeOf(t.ID), errors.New("required field is missing")))
+		if !converter.CollectError(&collected, err) {
+			return err
+		}
+	}
+	if !hasStatus {
+		var value interface{} = "new"
+		t.Status = toolbox.AsString(value)
+	}
+	if len(collected) > 0 {
+		return collected.SortByPath()
+	}
+	return nil
+}
+
+// ToMap returns map representation of Order, it implements toolbox.ToMapper
+func (t Order) ToMap(converter *toolbox.Converter) map[string]interface{} {
+	if converter == nil {
+		converter = toolbox.DefaultConverter
+	}
+	dateLayout := converter.DateLayout
+	if dateLayout == "" {
+		dateLayout = time.RFC3339
+	}
+	var result = make(map[string]interface{})
+	for key, value := range t.Audit.ToMap(converter) {
+		result[key] = value
+	}
+	result["id"] = t.ID
+	result["status"] = t.Status
+	result["paid"] = t.Paid
+	result["discount"] = t.Discount
+	result["created"] = t.Created.Format("2006-01-02")
+	if t.Shipped != nil {
+		result["shipped"] = t.Shipped.Format(dateLayout)
+	}
+	if value, err := converter.AsMapValue(t.Items); err == nil {
+		result["items"] = value
+	}
+	if value, err := converter.AsMapValue(t.Attrs); err == nil {
+		result["Attrs"] = value
+	}
+	if t.Main != nil {
+		result["main"] = t.Main.ToMap(converter)
+	}
+	return result
+}
@@ -1,7 +1,10 @@
 package toolbox
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"runtime"
 	"sync"
 )
 
@@ -173,3 +176,92 @@ func ProcessSliceWithIndexAsync(slice interface{}, handler func(index int, item
 	}
 	wg.Wait()
 }
+
+//ProcessSliceAsyncWithLimit iterates over any slice, it calls handler with each element asynchronously with at most concurrency handlers running at a time.
+//The first handler error cancels the context passed to remaining handlers, stops scheduling remaining elements and is returned.
+//If concurrency is not positive, runtime.NumCPU() is used.
+func ProcessSliceAsyncWithLimit(ctx context.Context, slice interface{}, concurrency int, handler func(ctx context.Context, item interface{}) error) error {
+	return ProcessSliceWithIndexAsyncWithLimit(ctx, slice, concurrency, func(ctx context.Context, index int, item interface{}) error {
+		return handler(ctx, item)
+	})
+}
+
+//ProcessSliceWithIndexAsyncWithLimit iterates over any slice, it calls handler with every index and item asynchronously with at most concurrency handlers running at a time.
+//The first handler error cancels the context passed to remaining handlers, stops scheduling remaining elements and is returned.
+//If concurrency is not positive, runtime.NumCPU() is used.
+func ProcessSliceWithIndexAsyncWithLimit(ctx context.Context, slice interface{}, concurrency int, handler func(ctx context.Context, index int, item interface{}) error) error {
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	limiter, limiterCtx := NewBatchLimiterWithContext(ctx, concurrency)
+	ProcessSliceWithIndex(slice, func(index int, item interface{}) bool {
+		if limiterCtx.Err() != nil {
+			return false
+		}
+		limiter.Go(limiterCtx, func(ctx context.Context) error {
+			return handler(ctx, index, item)
+		})
+		return true
+	})
+	if err := limiter.Wait(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+//IndexSliceAsyncWithLimit reads passed in slice and applies function that takes a slice item as argument to return a key value, with at most concurrency key functions running at a time.
+//Key function can optionally return an error as the second result, the first error cancels remaining work and is returned.
+func IndexSliceAsyncWithLimit(ctx context.Context, slice, resultingMap, keyFunction interface{}, concurrency int) error {
+	var lock = sync.Mutex{}
+	mapValue := DiscoverValueByKind(resultingMap, reflect.Map)
+	return ProcessSliceAsyncWithLimit(ctx, slice, concurrency, func(ctx context.Context, item interface{}) error {
+		key, err := callFunctionWithError(keyFunction, item)
+		if err != nil {
+			return err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		mapValue.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(item))
+		return nil
+	})
+}
+
+//SliceToMapAsyncWithLimit reads passed in slice to to apply the key and value function for each item, with at most concurrency items processed at a time. Result of these calls is placed in the resulting map.
+//Key and value function can optionally return an error as the second result, the first error cancels remaining work and is returned.
+func SliceToMapAsyncWithLimit(ctx context.Context, sourceSlice, targetMap, keyFunction, valueFunction interface{}, concurrency int) error {
+	var lock = sync.Mutex{}
+	mapValue := DiscoverValueByKind(targetMap, reflect.Map)
+	return ProcessSliceAsyncWithLimit(ctx, sourceSlice, concurrency, func(ctx context.Context, item interface{}) error {
+		key, err := callFunctionWithError(keyFunction, item)
+		if err != nil {
+			return err
+		}
+		value, err := callFunctionWithError(valueFunction, item)
+		if err != nil {
+			return err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		mapValue.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+		return nil
+	})
+}
+
+//callFunctionWithError calls a function returning either a value or a value and an error
+func callFunctionWithError(function interface{}, parameters ...interface{}) (interface{}, error) {
+	result := CallFunction(function, parameters...)
+	switch len(result) {
+	case 1:
+		return result[0], nil
+	case 2:
+		if result[1] != nil {
+			err, ok := result[1].(error)
+			if !ok {
+				return nil, fmt.Errorf("expected error as second result, but had: %T", result[1])
+			}
+			return nil, err
+		}
+		return result[0], nil
+	}
+	return nil, fmt.Errorf("expected function returning value or (value, error), but had: %T", function)
+}
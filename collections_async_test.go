@@ -1,10 +1,15 @@
 package toolbox
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestIndexSliceAsync(t *testing.T) {
@@ -93,6 +98,84 @@ func TestProcessSliceAsync(t *testing.T) {
 	}
 }
 
+func TestProcessSliceAsyncWithLimit(t *testing.T) {
+	{ //bounded concurrency
+		var aSlice = make([]int, 100)
+		var running, maxRunning, count int32
+		err := ProcessSliceAsyncWithLimit(context.Background(), aSlice, 4, func(ctx context.Context, item interface{}) error {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&count, 1)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.EqualValues(t, 100, count)
+		assert.True(t, maxRunning <= 4)
+	}
+	{ //first error cancels remaining work
+		var aSlice = make([]int, 100)
+		var count int32
+		err := ProcessSliceWithIndexAsyncWithLimit(context.Background(), aSlice, 2, func(ctx context.Context, index int, item interface{}) error {
+			atomic.AddInt32(&count, 1)
+			if index == 3 {
+				return fmt.Errorf("failed at %v", index)
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+		assert.EqualValues(t, "failed at 3", err.Error())
+		assert.True(t, count < 100)
+	}
+	{ //cancelled context
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := ProcessSliceAsyncWithLimit(ctx, []string{"a", "b"}, 2, func(ctx context.Context, item interface{}) error {
+			return nil
+		})
+		assert.Equal(t, context.Canceled, err)
+	}
+}
+
+func TestSliceToMapAsyncWithLimit(t *testing.T) {
+	{
+		type Foo struct {
+			id   int
+			name string
+		}
+		var fooCollection = []Foo{{1, "A"}, {2, "B"}, {3, "C"}}
+		var indexedMap = make(map[int]Foo)
+		err := IndexSliceAsyncWithLimit(context.Background(), fooCollection, indexedMap, func(foo Foo) int {
+			return foo.id
+		}, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, "C", indexedMap[3].name)
+
+		err = IndexSliceAsyncWithLimit(context.Background(), fooCollection, indexedMap, func(foo Foo) (int, error) {
+			if foo.id == 2 {
+				return 0, errors.New("invalid id")
+			}
+			return foo.id, nil
+		}, 2)
+		assert.EqualValues(t, "invalid id", err.Error())
+	}
+	{
+		aSlice := []string{"a", "c"}
+		aMap := make(map[string]int)
+		err := SliceToMapAsyncWithLimit(context.Background(), aSlice, aMap, CopyStringValueProvider, func(s string) int {
+			return len(s)
+		}, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int{"a": 1, "c": 1}, aMap)
+	}
+}
+
 func TestProcessSliceWithIndexAsync(t *testing.T) {
 	{
 		aSlice := []interface{}{
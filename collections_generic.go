@@ -0,0 +1,132 @@
+package toolbox
+
+// Each iterates over a typed slice, it calls handler with each element unless handler returns false.
+// It is a type safe counterpart of ProcessSlice.
+func Each[T any](slice []T, handler func(item T) bool) {
+	for _, item := range slice {
+		if !handler(item) {
+			break
+		}
+	}
+}
+
+// EachWithIndex iterates over a typed slice, it calls handler with every index and item unless handler returns false.
+// It is a type safe counterpart of ProcessSliceWithIndex.
+func EachWithIndex[T any](slice []T, handler func(index int, item T) bool) {
+	for i, item := range slice {
+		if !handler(i, item) {
+			break
+		}
+	}
+}
+
+// Filter returns elements of a slice for which predicate returns true.
+// It is a type safe counterpart of FilterSliceElements.
+func Filter[T any](slice []T, predicate func(item T) bool) []T {
+	var result = make([]T, 0)
+	for _, item := range slice {
+		if predicate(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Map returns a slice of transformed elements, transformer take as argument item of source slice and return value of target slice.
+// It is a type safe counterpart of TransformSlice.
+func Map[T, R any](slice []T, transformer func(item T) R) []R {
+	var result = make([]R, 0, len(slice))
+	for _, item := range slice {
+		result = append(result, transformer(item))
+	}
+	return result
+}
+
+// Index returns a map of slice elements keyed by value returned by key function, the last element with the same key wins.
+// It is a type safe counterpart of IndexSlice.
+func Index[T any, K comparable](slice []T, keyFunction func(item T) K) map[K]T {
+	var result = make(map[K]T, len(slice))
+	for _, item := range slice {
+		result[keyFunction(item)] = item
+	}
+	return result
+}
+
+// ToMapOf returns a map built by applying the key and value function to each element of a slice.
+// It is a type safe counterpart of SliceToMap.
+func ToMapOf[T any, K comparable, V any](slice []T, keyFunction func(item T) K, valueFunction func(item T) V) map[K]V {
+	var result = make(map[K]V, len(slice))
+	for _, item := range slice {
+		result[keyFunction(item)] = valueFunction(item)
+	}
+	return result
+}
+
+// GroupBy returns slice elements grouped by value returned by key function, element order within a group is preserved.
+// It is a type safe counterpart of GroupSliceElements.
+func GroupBy[T any, K comparable](slice []T, keyFunction func(item T) K) map[K][]T {
+	var result = make(map[K][]T)
+	for _, item := range slice {
+		key := keyFunction(item)
+		result[key] = append(result[key], item)
+	}
+	return result
+}
+
+// GroupValuesBy returns values returned by value function grouped by value returned by key function.
+// It is a type safe counterpart of SliceToMultimap.
+func GroupValuesBy[T any, K comparable, V any](slice []T, keyFunction func(item T) K, valueFunction func(item T) V) map[K][]V {
+	var result = make(map[K][]V)
+	for _, item := range slice {
+		key := keyFunction(item)
+		result[key] = append(result[key], valueFunction(item))
+	}
+	return result
+}
+
+// ContainsAny checks if slice has any of passed in elements. This function iterates through elements till it finds the first match.
+// It is a type safe counterpart of HasSliceAnyElements.
+func ContainsAny[T comparable](slice []T, elements ...T) bool {
+	for _, item := range slice {
+		for _, element := range elements {
+			if item == element {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Intersection returns elements of b that are also present in a, preserving b order.
+// It is a type safe counterpart of Intersect.
+func Intersection[T comparable](a, b []T) []T {
+	var aItems = make(map[T]bool, len(a))
+	for _, item := range a {
+		aItems[item] = true
+	}
+	var result = make([]T, 0)
+	for _, item := range b {
+		if aItems[item] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Reverse reverses a typed slice in place.
+// It is a type safe counterpart of ReverseSlice.
+func Reverse[T any](slice []T) {
+	for i, j := 0, len(slice)-1; i < j; i, j = i+1, j-1 {
+		slice[i], slice[j] = slice[j], slice[i]
+	}
+}
+
+// Keys returns keys of a map.
+// It is a type safe counterpart of MapKeysToSlice.
+func Keys[K comparable, V any](aMap map[K]V) []K {
+	var result = make([]K, 0, len(aMap))
+	for key := range aMap {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package toolbox_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+type genericFoo struct {
+	ID    int
+	Name  string
+	Group string
+}
+
+var genericFoos = []genericFoo{
+	{1, "A", "x"},
+	{2, "B", "y"},
+	{3, "C", "x"},
+}
+
+func TestEach(t *testing.T) {
+	{
+		var count = 0
+		toolbox.Each([]string{"abc", "def", "cyz", "adc"}, func(item string) bool {
+			count++
+			return true
+		})
+		assert.Equal(t, 4, count)
+	}
+	{
+		var count = 0
+		toolbox.Each([]string{"abc", "def", "cyz", "adc"}, func(item string) bool {
+			count++
+			return count < 2
+		})
+		assert.Equal(t, 2, count)
+	}
+	{
+		var indexes = make([]int, 0)
+		toolbox.EachWithIndex([]int{10, 20, 30}, func(index int, item int) bool {
+			indexes = append(indexes, index)
+			return item < 20
+		})
+		assert.Equal(t, []int{0, 1}, indexes)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	source := []string{"abc", "def", "cyz", "adc"}
+	filtered := toolbox.Filter(source, func(item string) bool {
+		return strings.HasPrefix(item, "a")
+	})
+	assert.Equal(t, []string{"abc", "adc"}, filtered)
+	assert.Equal(t, []string{}, toolbox.Filter(nil, func(item string) bool { return true }))
+}
+
+func TestMap(t *testing.T) {
+	names := toolbox.Map(genericFoos, func(foo genericFoo) string {
+		return foo.Name
+	})
+	assert.Equal(t, []string{"A", "B", "C"}, names)
+}
+
+func TestIndex(t *testing.T) {
+	indexed := toolbox.Index(genericFoos, func(foo genericFoo) int {
+		return foo.ID
+	})
+	assert.Equal(t, 3, len(indexed))
+	assert.Equal(t, "B", indexed[2].Name)
+
+	aMap := toolbox.ToMapOf(genericFoos, func(foo genericFoo) string {
+		return foo.Name
+	}, func(foo genericFoo) int {
+		return foo.ID
+	})
+	assert.Equal(t, map[string]int{"A": 1, "B": 2, "C": 3}, aMap)
+}
+
+func TestGroupBy(t *testing.T) {
+	grouped := toolbox.GroupBy(genericFoos, func(foo genericFoo) string {
+		return foo.Group
+	})
+	assert.Equal(t, 2, len(grouped))
+	assert.Equal(t, []genericFoo{{1, "A", "x"}, {3, "C", "x"}}, grouped["x"])
+
+	multimap := toolbox.GroupValuesBy(genericFoos, func(foo genericFoo) string {
+		return foo.Group
+	}, func(foo genericFoo) int {
+		return foo.ID
+	})
+	assert.Equal(t, map[string][]int{"x": {1, 3}, "y": {2}}, multimap)
+}
+
+func TestContainsAny(t *testing.T) {
+	assert.True(t, toolbox.ContainsAny([]string{"abc", "def", "cyz"}, "cyz"))
+	assert.False(t, toolbox.ContainsAny([]string{"abc", "def", "cyz"}, "xyz", "123"))
+}
+
+func TestIntersectionOf(t *testing.T) {
+	assert.Equal(t, []int{2, 4}, toolbox.Intersection([]int{1, 2, 3, 4}, []int{2, 4, 6}))
+	assert.Equal(t, []string{}, toolbox.Intersection([]string{"a"}, []string{"b"}))
+}
+
+func TestReverse(t *testing.T) {
+	aSlice := []string{"abc", "def", "cyz", "adc", "z"}
+	toolbox.Reverse(aSlice)
+	assert.Equal(t, []string{"z", "adc", "cyz", "def", "abc"}, aSlice)
+
+	keys := toolbox.Keys(map[string]int{"b": 1, "a": 2})
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+}
+
+var benchmarkFoos = func() []genericFoo {
+	var result = make([]genericFoo, 1000)
+	for i := range result {
+		result[i] = genericFoo{ID: i, Name: "name", Group: []string{"x", "y", "z"}[i%3]}
+	}
+	return result
+}()
+
+func BenchmarkFilterSliceElements(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var target = make([]genericFoo, 0)
+		toolbox.FilterSliceElements(benchmarkFoos, func(foo genericFoo) bool {
+			return foo.ID%2 == 0
+		}, &target)
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		toolbox.Filter(benchmarkFoos, func(foo genericFoo) bool {
+			return foo.ID%2 == 0
+		})
+	}
+}
+
+func BenchmarkTransformSlice(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var target = make([]int, 0)
+		toolbox.TransformSlice(benchmarkFoos, &target, func(foo genericFoo) int {
+			return foo.ID
+		})
+	}
+}
+
+func BenchmarkMap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		toolbox.Map(benchmarkFoos, func(foo genericFoo) int {
+			return foo.ID
+		})
+	}
+}
+
+func BenchmarkIndexSlice(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var target = make(map[int]genericFoo)
+		toolbox.IndexSlice(benchmarkFoos, target, func(foo genericFoo) int {
+			return foo.ID
+		})
+	}
+}
+
+func BenchmarkIndex(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		toolbox.Index(benchmarkFoos, func(foo genericFoo) int {
+			return foo.ID
+		})
+	}
+}
+
+func BenchmarkGroupSliceElements(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var target = make(map[string][]genericFoo)
+		toolbox.GroupSliceElements(benchmarkFoos, target, func(foo genericFoo) string {
+			return foo.Group
+		})
+	}
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		toolbox.GroupBy(benchmarkFoos, func(foo genericFoo) string {
+			return foo.Group
+		})
+	}
+}
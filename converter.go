@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unsafe"
 )
 
 // DefaultDateLayout is set to 2006-01-02 15:04:05.000
@@ -453,10 +455,44 @@ func ParseTime(input, layout string) (time.Time, error) {
 	return time.Parse(layout, input)
 }
 
+// FromMapper represents a type that can populate itself from a map without reflection, i.e. with generated code;
+// Converter.AssignConverted uses it when the converter MappedKeyTag matches MapperKeyTag and no custom converter is registered for the type
+type FromMapper interface {
+	MapperKeyTag() string
+	FromMap(source map[string]interface{}, converter *Converter) error
+}
+
+// ToMapper represents a type that can return its map representation without reflection, i.e. with generated code;
+// Converter.AssignConverted uses it when the converter MappedKeyTag matches MapperKeyTag
+type ToMapper interface {
+	MapperKeyTag() string
+	ToMap(converter *Converter) map[string]interface{}
+}
+
 // Converter represets data converter, it converts incompatibe data structure, like map and struct, string and time, *string to string, etc.
 type Converter struct {
-	DateLayout   string
-	MappedKeyTag string
+	DateLayout       string
+	MappedKeyTag     string
+	CollectAllErrors bool //when set, conversion continues after a field error and returns ConversionErrors with every failing field
+	EnforceRequired  bool //when set, struct field tagged with required:"true" missing in source map fails conversion
+	registry         unsafe.Pointer //*converterRegistry, accessed atomically as Converter can be created without NewConverter
+}
+
+// CollectError collects supplied error and returns true if conversion can continue, it is used by generated FromMap methods
+func (c *Converter) CollectError(collected *ConversionErrors, err error) bool {
+	if !c.CollectAllErrors {
+		return false
+	}
+	*collected = appendConversionError(*collected, err)
+	return true
+}
+
+// asConversionError wraps supplied error with conversion error unless it already carries conversion path
+func asConversionError(source interface{}, target reflect.Type, err error) error {
+	if isConversionError(err) {
+		return err
+	}
+	return NewConversionError(source, target, err)
 }
 
 func (c *Converter) assignConvertedMap(target, source interface{}, targetIndirectValue reflect.Value, targetIndirectPointerType reflect.Type) error {
@@ -467,6 +503,7 @@ func (c *Converter) assignConvertedMap(target, source interface{}, targetIndirec
 	newMap := mapPointer.Elem()
 	newMap.Set(reflect.MakeMap(mapType))
 	var err error
+	var collected ConversionErrors
 	err = ProcessMap(source, func(key, value interface{}) bool {
 		if value == nil {
 			return true
@@ -475,7 +512,11 @@ func (c *Converter) assignConvertedMap(target, source interface{}, targetIndirec
 		targetMapValuePointer := reflect.New(mapValueType)
 		err = c.AssignConverted(targetMapValuePointer.Interface(), value)
 		if err != nil {
-			err = fmt.Errorf("failed to assigned converted map value %v to %v due to %v", source, target, err)
+			err = withKeyPath(key, asConversionError(value, mapValueType, err))
+			if c.CollectError(&collected, err) {
+				err = nil
+				return true
+			}
 			return false
 		}
 
@@ -497,6 +538,11 @@ func (c *Converter) assignConvertedMap(target, source interface{}, targetIndirec
 			var compatibleValue = reflect.New(newMap.Type().Elem())
 			err = c.AssignConverted(compatibleValue.Interface(), elementValue.Interface())
 			if err != nil {
+				err = withKeyPath(key, asConversionError(value, newMap.Type().Elem(), err))
+				if c.CollectError(&collected, err) {
+					err = nil
+					return true
+				}
 				return false
 			}
 			elementValue = compatibleValue.Elem()
@@ -516,6 +562,9 @@ func (c *Converter) assignConvertedMap(target, source interface{}, targetIndirec
 	} else {
 		targetIndirectValue.Set(newMap)
 	}
+	if len(collected) > 0 {
+		return collected.SortByPath()
+	}
 	return err
 
 }
@@ -526,7 +575,8 @@ func (c *Converter) assignConvertedSlice(target, source interface{}, targetIndir
 	slice := slicePointer.Elem()
 	componentType := DiscoverComponentType(target)
 	var err error
-	ProcessSlice(source, func(item interface{}) bool {
+	var collected ConversionErrors
+	ProcessSliceWithIndex(source, func(index int, item interface{}) bool {
 		var targetComponentPointer = reflect.New(componentType)
 		if componentType.Kind() == reflect.Map {
 			targetComponent := reflect.MakeMap(componentType)
@@ -534,7 +584,11 @@ func (c *Converter) assignConvertedSlice(target, source interface{}, targetIndir
 		}
 		err = c.AssignConverted(targetComponentPointer.Interface(), item)
 		if err != nil {
-			err = fmt.Errorf("failed to convert slice item from %T to %T, values: from %v to %v, due to %v", item, targetComponentPointer.Interface(), item, targetComponentPointer.Interface(), err)
+			err = withIndexPath(index, asConversionError(item, componentType, err))
+			if c.CollectError(&collected, err) {
+				err = nil
+				return true
+			}
 			return false
 		}
 		slice.Set(reflect.Append(slice, targetComponentPointer.Elem()))
@@ -546,6 +600,9 @@ func (c *Converter) assignConvertedSlice(target, source interface{}, targetIndir
 	} else {
 		targetIndirectValue.Set(slice)
 	}
+	if err == nil && len(collected) > 0 {
+		return collected.SortByPath()
+	}
 	return err
 }
 
@@ -554,15 +611,20 @@ func (c *Converter) assignConvertedStruct(target interface{}, inputMap map[strin
 	newStruct := newStructPointer.Elem()
 	fieldsMapping := NewFieldSettingByKey(newStructPointer.Interface(), c.MappedKeyTag)
 
-	var defaultValueMap = make(map[string]interface{})
+	var defaultValueMap = make(map[string]map[string]string)
+	var requiredFields = make(map[string]bool)
 
 	var anonymousValueMap map[string]reflect.Value
 	var anonymousFields map[string]reflect.Value
+	var collected ConversionErrors
 
 	for _, value := range fieldsMapping {
-		if defaultValue, ok := value[defaultKey]; ok {
-			var fieldName = value[fieldNameKey]
-			defaultValueMap[fieldName] = defaultValue
+		var fieldName = value[fieldNameKey]
+		if _, ok := value[defaultKey]; ok {
+			defaultValueMap[fieldName] = value
+		}
+		if c.EnforceRequired && AsBoolean(value[requiredKey]) {
+			requiredFields[fieldName] = true
 		}
 		if index, ok := value[fieldIndexKey]; ok {
 			if len(anonymousValueMap) == 0 {
@@ -611,36 +673,43 @@ func (c *Converter) assignConvertedStruct(target interface{}, inputMap map[strin
 			if _, has := defaultValueMap[fieldName]; has {
 				delete(defaultValueMap, fieldName)
 			}
-			previousLayout := c.DateLayout
-			if HasTimeLayout(mapping) {
-				c.DateLayout = GetTimeLayout(mapping)
-				c.DateLayout = previousLayout
+			if value == nil {
+				continue
 			}
-
-			if (!field.CanAddr()) && field.Kind() == reflect.Ptr {
-				if err := c.AssignConverted(field.Interface(), value); err != nil {
-					return fmt.Errorf("failed to convert %v to %v due to %v", value, field, err)
+			delete(requiredFields, fieldName)
+			if err := c.assignConvertedField(field, value, mapping); err != nil {
+				if err = withFieldKeyPath(fieldName, key, err); !c.CollectError(&collected, err) {
+					return err
 				}
+			}
+		}
+	}
 
-			} else {
-				if value == nil {
-					continue
-				}
-				if err := c.AssignConverted(field.Addr().Interface(), value); err != nil {
-					return fmt.Errorf("failed to convert %v to %v due to %v", value, field, err)
-				}
+	if len(requiredFields) > 0 {
+		var missing = make([]string, 0, len(requiredFields))
+		for fieldName := range requiredFields {
+			if _, has := defaultValueMap[fieldName]; !has {
+				missing = append(missing, fieldName)
 			}
-			if HasTimeLayout(mapping) {
-				c.DateLayout = previousLayout
+		}
+		sort.Strings(missing)
+		for _, fieldName := range missing {
+			field, _ := newStruct.Type().FieldByName(fieldName)
+			err := withFieldKeyPath(fieldName, c.mappedKey(field), NewConversionError(nil, field.Type, fmt.Errorf("required field is missing")))
+			if !c.CollectError(&collected, err) {
+				return err
 			}
 		}
 	}
 
-	for fieldName, value := range defaultValueMap {
+	for fieldName, mapping := range defaultValueMap {
 		field := newStruct.FieldByName(fieldName)
-		err := c.AssignConverted(field.Addr().Interface(), value)
-		if err != nil {
-			return fmt.Errorf("failed to assign default value %v to %v due to %v", value, field, err)
+		value := mapping[defaultKey]
+		if err := c.assignConvertedField(field, value, mapping); err != nil {
+			structField, _ := newStruct.Type().FieldByName(fieldName)
+			if err = withFieldKeyPath(fieldName, c.mappedKey(structField), err); !c.CollectError(&collected, err) {
+				return err
+			}
 		}
 	}
 
@@ -649,28 +718,48 @@ func (c *Converter) assignConvertedStruct(target interface{}, inputMap map[strin
 	} else {
 		targetIndirectValue.Set(newStruct)
 	}
+	if len(collected) > 0 {
+		return collected.SortByPath()
+	}
 	return nil
 }
 
-// customConverter map of target, source type with converter
-var customConverter = make(map[reflect.Type]map[reflect.Type]func(target, source interface{}) error)
-
-// RegisterConverter register custom converter for supplied target, source type
-func RegisterConverter(target, source reflect.Type, converter func(target, source interface{}) error) {
-	if _, ok := customConverter[target]; !ok {
-		customConverter[target] = make(map[reflect.Type]func(target, source interface{}) error)
+// mappedKey returns source map key of a struct field, field name is used if the field has no key tag
+func (c *Converter) mappedKey(field reflect.StructField) string {
+	if key := getTagValues(field, c.MappedKeyTag); key != "" {
+		return key
 	}
-	customConverter[target][source] = converter
+	return field.Name
 }
 
-// GetConverter returns register converter for supplied target and source type
-func GetConverter(target, source interface{}) (func(target, source interface{}) error, bool) {
-	sourceConverters, ok := customConverter[reflect.TypeOf(target)]
-	if !ok {
-		return nil, false
+// assignConvertedField assigns converted value to a struct field, it applies field dateLayout and converter tag directives
+func (c *Converter) assignConvertedField(field reflect.Value, value interface{}, mapping map[string]string) error {
+	converter := c
+	if HasTimeLayout(mapping) {
+		fieldConverter := *c
+		fieldConverter.DateLayout = GetTimeLayout(mapping)
+		converter = &fieldConverter
+	}
+	var fieldPointer interface{}
+	if (!field.CanAddr()) && field.Kind() == reflect.Ptr {
+		fieldPointer = field.Interface()
+	} else {
+		fieldPointer = field.Addr().Interface()
+	}
+	if name, ok := mapping[converterKey]; ok {
+		convert, ok := converter.GetNamedConverter(name)
+		if !ok {
+			return fmt.Errorf("unknown converter: %v", name)
+		}
+		if err := convert(fieldPointer, value); err != nil {
+			return asConversionError(value, field.Type(), err)
+		}
+		return nil
+	}
+	if err := converter.AssignConverted(fieldPointer, value); err != nil {
+		return asConversionError(value, field.Type(), err)
 	}
-	converter, ok := sourceConverters[reflect.TypeOf(source)]
-	return converter, ok
+	return nil
 }
 
 // AssignConverted assign to the target source, target needs to be pointer, input has to be convertible or compatible type
@@ -681,6 +770,18 @@ func (c *Converter) AssignConverted(target, source interface{}) error {
 	if source == nil {
 		return nil
 	}
+	if fromMapper, ok := target.(FromMapper); ok && IsMap(source) && fromMapper.MapperKeyTag() == c.MappedKeyTag {
+		if converter, ok := c.GetConverter(target, source); ok {
+			return converter(target, source)
+		}
+		return fromMapper.FromMap(AsMap(source), c)
+	}
+	if toMapper, ok := source.(ToMapper); ok && toMapper.MapperKeyTag() == c.MappedKeyTag {
+		if targetMap, ok := target.(*map[string]interface{}); ok {
+			*targetMap = toMapper.ToMap(c)
+			return nil
+		}
+	}
 	switch targetValuePointer := target.(type) {
 	case *string:
 		switch sourceValue := source.(type) {
@@ -976,21 +1077,21 @@ func (c *Converter) AssignConverted(target, source interface{}) error {
 		*targetValuePointer = timeValue
 		return nil
 	case *interface{}:
-		if converter, ok := GetConverter(target, source); ok {
+		if converter, ok := c.GetConverter(target, source); ok {
 			return converter(target, source)
 		}
 		(*targetValuePointer) = source
 		return nil
 
 	case **interface{}:
-		if converter, ok := GetConverter(target, source); ok {
+		if converter, ok := c.GetConverter(target, source); ok {
 			return converter(target, source)
 		}
 		(*targetValuePointer) = &source
 		return nil
 
 	default:
-		if converter, ok := GetConverter(target, source); ok {
+		if converter, ok := c.GetConverter(target, source); ok {
 			return converter(target, source)
 		}
 	}
@@ -1078,11 +1179,19 @@ func (c *Converter) AssignConverted(target, source interface{}) error {
 		if err != nil {
 			return fmt.Errorf("unable transfer to %T,  source should be a map but was %T(%v)", target, source, source)
 		}
-		if err = c.assignConvertedStruct(target, inputMap, structPointer.Elem(), targetIndirectPointerType); err != nil {
+		if fromMapper, ok := structPointer.Interface().(FromMapper); ok && fromMapper.MapperKeyTag() == c.MappedKeyTag {
+			if err = fromMapper.FromMap(inputMap, c); err != nil && !c.CollectAllErrors {
+				return err
+			}
+			targetIndirectValue.Set(structPointer)
+			return err
+		}
+		err = c.assignConvertedStruct(target, inputMap, structPointer.Elem(), targetIndirectPointerType)
+		if err != nil && !c.CollectAllErrors {
 			return err
 		}
 		targetIndirectValue.Set(structPointer)
-		return nil
+		return err
 
 	}
 
@@ -1275,40 +1384,10 @@ func (c *Converter) assignConvertedMapFromStruct(source, target interface{}, sou
 		if value == nil {
 			return nil
 		}
-		if timeVal := tryExtractTime(value); timeVal != nil {
-			value = timeVal.Format(time.RFC3339)
-		}
-		var fieldTarget interface{}
-		if IsStruct(value) {
-			aMap := make(map[string]interface{})
-			if err := c.AssignConverted(&aMap, value); err != nil {
-				return err
-			}
-			fieldTarget = aMap
-
-		} else if IsSlice(value) {
-			var componentType = DereferenceType(DiscoverComponentType(value))
-			if componentType.Kind() == reflect.Struct {
-				var slice = make([]map[string]interface{}, 0)
-				if err := c.AssignConverted(&slice, value); err != nil {
-					return err
-				}
-				fieldTarget = slice
-			} else {
-				if _, isByteArray := value.([]byte); isByteArray {
-					fieldTarget = value
-				} else {
-					var slice = make([]interface{}, 0)
-					if err := c.AssignConverted(&slice, value); err != nil {
-						return err
-					}
-					fieldTarget = slice
-				}
-			}
-		} else if err := c.AssignConverted(&fieldTarget, value); err != nil {
+		fieldTarget, err := c.AsMapValue(value)
+		if err != nil {
 			return err
 		}
-
 		fieldName := fieldType.Name
 		keyTag := strings.Trim(fieldType.Tag.Get(c.MappedKeyTag), `"`)
 
@@ -1324,6 +1403,44 @@ func (c *Converter) assignConvertedMapFromStruct(source, target interface{}, sou
 	})
 }
 
+// AsMapValue converts a struct field value into its map representation: time is formatted with RFC3339, struct is converted to a map, slice to a slice of maps or interfaces
+func (c *Converter) AsMapValue(value interface{}) (interface{}, error) {
+	if timeVal := tryExtractTime(value); timeVal != nil {
+		value = timeVal.Format(time.RFC3339)
+	}
+	var fieldTarget interface{}
+	if IsStruct(value) {
+		aMap := make(map[string]interface{})
+		if err := c.AssignConverted(&aMap, value); err != nil {
+			return nil, err
+		}
+		fieldTarget = aMap
+
+	} else if IsSlice(value) {
+		var componentType = DereferenceType(DiscoverComponentType(value))
+		if componentType.Kind() == reflect.Struct {
+			var slice = make([]map[string]interface{}, 0)
+			if err := c.AssignConverted(&slice, value); err != nil {
+				return nil, err
+			}
+			fieldTarget = slice
+		} else {
+			if _, isByteArray := value.([]byte); isByteArray {
+				fieldTarget = value
+			} else {
+				var slice = make([]interface{}, 0)
+				if err := c.AssignConverted(&slice, value); err != nil {
+					return nil, err
+				}
+				fieldTarget = slice
+			}
+		}
+	} else if err := c.AssignConverted(&fieldTarget, value); err != nil {
+		return nil, err
+	}
+	return fieldTarget, nil
+}
+
 func tryExtractTime(value interface{}) *time.Time {
 
 	if timeVal, ok := value.(time.Time); ok {
@@ -1364,7 +1481,7 @@ func tryExtractTime(value interface{}) *time.Time {
 
 // NewColumnConverter create a new converter, that has ability to convert map to struct using column mapping
 func NewColumnConverter(dateLayout string) *Converter {
-	return &Converter{dateLayout, "column"}
+	return &Converter{DateLayout: dateLayout, MappedKeyTag: "column", registry: unsafe.Pointer(newConverterRegistry())}
 }
 
 // NewConverter create a new converter, that has ability to convert map to struct, it uses keytag to identify source and dest of fields/keys
@@ -1372,7 +1489,7 @@ func NewConverter(dateLayout, keyTag string) *Converter {
 	if keyTag == "" {
 		keyTag = "name"
 	}
-	return &Converter{dateLayout, keyTag}
+	return &Converter{DateLayout: dateLayout, MappedKeyTag: keyTag, registry: unsafe.Pointer(newConverterRegistry())}
 }
 
 // DefaultConverter represents a default data structure converter
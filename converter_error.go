@@ -0,0 +1,165 @@
+package toolbox
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConversionError represents a conversion error of a nested field
+type ConversionError struct {
+	Path   string       //JSON pointer like path to the failing source value using source map keys, i.e. /items/3/price
+	Field  string       //Go style path to the failing field, i.e. Items[3].Price
+	Source interface{}  //source value
+	Target reflect.Type //target type
+	Err    error        //underlying error
+}
+
+// Error returns an error message prefixed with the field path
+func (e *ConversionError) Error() string {
+	var message string
+	if e.Source == nil || e.Target == nil {
+		message = e.Err.Error()
+	} else {
+		message = fmt.Sprintf("failed to convert %v to %v due to %v", e.Source, e.Target, e.Err)
+	}
+	if e.Field == "" {
+		return message
+	}
+	return e.Field + ": " + message
+}
+
+// Unwrap returns underlying error
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// ConversionErrors represents all conversion errors collected with Converter.CollectAllErrors
+type ConversionErrors []*ConversionError
+
+// Error returns all errors messages
+func (e ConversionErrors) Error() string {
+	var messages = make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// NewConversionError creates a conversion error
+func NewConversionError(source interface{}, target reflect.Type, err error) *ConversionError {
+	return &ConversionError{Source: source, Target: target, Err: err}
+}
+
+// AsConversionErrors returns conversion errors if supplied error is *ConversionError or ConversionErrors
+func AsConversionErrors(err error) (ConversionErrors, bool) {
+	switch actual := err.(type) {
+	case *ConversionError:
+		return ConversionErrors{actual}, true
+	case ConversionErrors:
+		return actual, true
+	}
+	return nil, false
+}
+
+// isConversionError returns true if supplied error already carries conversion path
+func isConversionError(err error) bool {
+	_, ok := AsConversionErrors(err)
+	return ok
+}
+
+// WithFieldPath prefixes a path of supplied conversion error with a struct field name, non conversion errors are wrapped with ConversionError
+func WithFieldPath(fieldName string, err error) error {
+	return withFieldPath(fieldName, err)
+}
+
+// WithFieldKeyPath prefixes a path of supplied conversion error with a struct field name and its source map key
+func WithFieldKeyPath(fieldName, key string, err error) error {
+	return withFieldKeyPath(fieldName, key, err)
+}
+
+// withFieldPath prefixes a path of supplied error with a struct field name
+func withFieldPath(fieldName string, err error) error {
+	return withPath(fieldName, fieldName, err)
+}
+
+// withFieldKeyPath prefixes a field path of supplied error with a struct field name and a path with the field source map key
+func withFieldKeyPath(fieldName, key string, err error) error {
+	return withPath(fieldName, key, err)
+}
+
+// SortByPath sorts errors by path, numeric path segments are compared numerically
+func (e ConversionErrors) SortByPath() ConversionErrors {
+	sort.SliceStable(e, func(i, j int) bool {
+		return lessPath(e[i].Path, e[j].Path)
+	})
+	return e
+}
+
+func lessPath(left, right string) bool {
+	leftSegments, rightSegments := strings.Split(left, "/"), strings.Split(right, "/")
+	for i := 0; i < len(leftSegments) && i < len(rightSegments); i++ {
+		if leftSegments[i] == rightSegments[i] {
+			continue
+		}
+		leftIndex, leftErr := strconv.Atoi(leftSegments[i])
+		rightIndex, rightErr := strconv.Atoi(rightSegments[i])
+		if leftErr == nil && rightErr == nil {
+			return leftIndex < rightIndex
+		}
+		return leftSegments[i] < rightSegments[i]
+	}
+	return len(leftSegments) < len(rightSegments)
+}
+
+// withIndexPath prefixes a path of supplied error with a slice index
+func withIndexPath(index int, err error) error {
+	return withPath(fmt.Sprintf("[%d]", index), AsString(index), err)
+}
+
+// withKeyPath prefixes a path of supplied error with a map key
+func withKeyPath(key interface{}, err error) error {
+	return withPath(fmt.Sprintf("[%v]", key), AsString(key), err)
+}
+
+func withPath(fieldSegment, pointerSegment string, err error) error {
+	pointerSegment = "/" + strings.Replace(strings.Replace(pointerSegment, "~", "~0", -1), "/", "~1", -1)
+	switch actual := err.(type) {
+	case *ConversionError:
+		return prefixConversionError(actual, fieldSegment, pointerSegment)
+	case ConversionErrors:
+		var result = make(ConversionErrors, len(actual))
+		for i, item := range actual {
+			result[i] = prefixConversionError(item, fieldSegment, pointerSegment)
+		}
+		return result
+	}
+	return &ConversionError{Field: fieldSegment, Path: pointerSegment, Err: err}
+}
+
+func prefixConversionError(err *ConversionError, fieldSegment, pointerSegment string) *ConversionError {
+	var result = *err
+	result.Field = joinFieldPath(fieldSegment, err.Field)
+	result.Path = pointerSegment + err.Path
+	return &result
+}
+
+func joinFieldPath(parent, child string) string {
+	if child == "" {
+		return parent
+	}
+	if strings.HasPrefix(child, "[") {
+		return parent + child
+	}
+	return parent + "." + child
+}
+
+// appendConversionError appends supplied error to collected errors
+func appendConversionError(errors ConversionErrors, err error) ConversionErrors {
+	if collected, ok := AsConversionErrors(err); ok {
+		return append(errors, collected...)
+	}
+	return append(errors, &ConversionError{Err: err})
+}
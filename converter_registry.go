@@ -0,0 +1,121 @@
+package toolbox
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// converterRegistry represents custom converters registry
+type converterRegistry struct {
+	mux   sync.RWMutex
+	types map[reflect.Type]map[reflect.Type]func(target, source interface{}) error
+	named map[string]func(target, source interface{}) error
+}
+
+func (r *converterRegistry) register(target, source reflect.Type, converter func(target, source interface{}) error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if _, ok := r.types[target]; !ok {
+		r.types[target] = make(map[reflect.Type]func(target, source interface{}) error)
+	}
+	r.types[target][source] = converter
+}
+
+func (r *converterRegistry) registerNamed(name string, converter func(target, source interface{}) error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.named[name] = converter
+}
+
+func (r *converterRegistry) lookup(target, source reflect.Type) (func(target, source interface{}) error, bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	sourceConverters, ok := r.types[target]
+	if !ok {
+		return nil, false
+	}
+	converter, ok := sourceConverters[source]
+	return converter, ok
+}
+
+func (r *converterRegistry) lookupNamed(name string) (func(target, source interface{}) error, bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	converter, ok := r.named[name]
+	return converter, ok
+}
+
+func newConverterRegistry() *converterRegistry {
+	return &converterRegistry{
+		types: make(map[reflect.Type]map[reflect.Type]func(target, source interface{}) error),
+		named: make(map[string]func(target, source interface{}) error),
+	}
+}
+
+// customConverter global registry of target, source type with converter, used as a fallback by any Converter
+var customConverter = newConverterRegistry()
+
+// RegisterConverter register custom converter for supplied target, source type
+func RegisterConverter(target, source reflect.Type, converter func(target, source interface{}) error) {
+	customConverter.register(target, source, converter)
+}
+
+// GetConverter returns register converter for supplied target and source type
+func GetConverter(target, source interface{}) (func(target, source interface{}) error, bool) {
+	return customConverter.lookup(reflect.TypeOf(target), reflect.TypeOf(source))
+}
+
+// RegisterNamedConverter register custom converter under supplied name, it can be referenced by a struct field with converter tag, i.e. `converter:"name"`
+func RegisterNamedConverter(name string, converter func(target, source interface{}) error) {
+	customConverter.registerNamed(name, converter)
+}
+
+// GetNamedConverter returns converter registered under supplied name
+func GetNamedConverter(name string) (func(target, source interface{}) error, bool) {
+	return customConverter.lookupNamed(name)
+}
+
+// RegisterConverter register custom converter for supplied target, source type, it takes precedence over globally registered converter
+func (c *Converter) RegisterConverter(target, source reflect.Type, converter func(target, source interface{}) error) {
+	c.ensureRegistry().register(target, source, converter)
+}
+
+// RegisterNamedConverter register custom converter under supplied name, it takes precedence over globally registered converter
+func (c *Converter) RegisterNamedConverter(name string, converter func(target, source interface{}) error) {
+	c.ensureRegistry().registerNamed(name, converter)
+}
+
+// GetConverter returns converter for supplied target and source type, registered with this converter or globally
+func (c *Converter) GetConverter(target, source interface{}) (func(target, source interface{}) error, bool) {
+	if registry := c.getRegistry(); registry != nil {
+		if converter, ok := registry.lookup(reflect.TypeOf(target), reflect.TypeOf(source)); ok {
+			return converter, ok
+		}
+	}
+	return GetConverter(target, source)
+}
+
+// GetNamedConverter returns converter registered under supplied name with this converter or globally
+func (c *Converter) GetNamedConverter(name string) (func(target, source interface{}) error, bool) {
+	if registry := c.getRegistry(); registry != nil {
+		if converter, ok := registry.lookupNamed(name); ok {
+			return converter, ok
+		}
+	}
+	return GetNamedConverter(name)
+}
+
+func (c *Converter) getRegistry() *converterRegistry {
+	return (*converterRegistry)(atomic.LoadPointer(&c.registry))
+}
+
+// ensureRegistry returns converter registry, it is created lazily for Converter created without NewConverter
+func (c *Converter) ensureRegistry() *converterRegistry {
+	if registry := c.getRegistry(); registry != nil {
+		return registry
+	}
+	atomic.CompareAndSwapPointer(&c.registry, nil, unsafe.Pointer(newConverterRegistry()))
+	return c.getRegistry()
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/toolbox"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -768,4 +770,198 @@ func Test_Issue41(t *testing.T) {
 			assert.EqualValues(t, test.sourceItem, target)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestConverter_RegisterConverter(t *testing.T) {
+	type Money struct {
+		Cents int
+	}
+	var moneyType = reflect.TypeOf(&Money{})
+	var stringType = reflect.TypeOf("")
+
+	local := toolbox.NewConverter("", "json")
+	local.RegisterConverter(moneyType, stringType, func(target, source interface{}) error {
+		target.(*Money).Cents = len(source.(string))
+		return nil
+	})
+	other := toolbox.NewConverter("", "json")
+
+	money := &Money{}
+	assert.Nil(t, local.AssignConverted(money, "abc"))
+	assert.Equal(t, 3, money.Cents)
+
+	_, ok := other.GetConverter(money, "abc")
+	assert.False(t, ok, "converter should be scoped to registering instance")
+
+	toolbox.RegisterConverter(moneyType, reflect.TypeOf(0), func(target, source interface{}) error {
+		target.(*Money).Cents = source.(int) * 100
+		return nil
+	})
+	assert.Nil(t, other.AssignConverted(money, 2))
+	assert.Equal(t, 200, money.Cents, "global converter should be used as a fallback")
+}
+
+func TestConverter_RegisterConverterConcurrently(t *testing.T) {
+	type Code struct {
+		Value string
+	}
+	var codeType = reflect.TypeOf(&Code{})
+	var converter = &toolbox.Converter{MappedKeyTag: "json"}
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(2)
+		go func() {
+			defer waitGroup.Done()
+			converter.RegisterConverter(codeType, reflect.TypeOf(""), func(target, source interface{}) error {
+				target.(*Code).Value = strings.ToUpper(source.(string))
+				return nil
+			})
+		}()
+		go func() {
+			defer waitGroup.Done()
+			converter.GetConverter(&Code{}, "")
+		}()
+	}
+	waitGroup.Wait()
+	code := &Code{}
+	assert.Nil(t, converter.AssignConverted(code, "abc"))
+	assert.Equal(t, "ABC", code.Value)
+}
+
+func TestConverter_AssignConvertedTagDirectives(t *testing.T) {
+	type Item struct {
+		Name     string    `json:"name" required:"true"`
+		Price    float64   `json:"price"`
+		Currency string    `json:"currency" default:"USD"`
+		Created  time.Time `json:"created" dateLayout:"2006-01-02"`
+		Code     string    `json:"code" converter:"upper"`
+	}
+	type Order struct {
+		Items []*Item `json:"items"`
+	}
+	{ //required tag is not enforced by default
+		var order = &Order{}
+		err := toolbox.NewConverter("", "json").AssignConverted(order, map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{"price": 1.5}},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1.5, order.Items[0].Price)
+	}
+	converter := toolbox.NewConverter("", "json")
+	converter.EnforceRequired = true
+	converter.RegisterNamedConverter("upper", func(target, source interface{}) error {
+		*(target.(*string)) = strings.ToUpper(toolbox.AsString(source))
+		return nil
+	})
+
+	{
+		var order = &Order{}
+		err := converter.AssignConverted(order, map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"name": "a", "price": 1.5, "created": "2020-03-04", "code": "abc"},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(order.Items))
+		assert.Equal(t, "USD", order.Items[0].Currency)
+		assert.Equal(t, "ABC", order.Items[0].Code)
+		assert.Equal(t, time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC), order.Items[0].Created)
+	}
+	{
+		var order = &Order{}
+		err := converter.AssignConverted(order, map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"name": "a", "price": 1.5},
+				map[string]interface{}{"name": "b", "price": "abc"},
+			},
+		})
+		assert.NotNil(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "Items[1].Price:"), err.Error())
+	}
+	{
+		var order = &Order{}
+		err := converter.AssignConverted(order, map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"price": 1.5},
+			},
+		})
+		assert.NotNil(t, err)
+		assert.Equal(t, "Items[0].Name: required field is missing", err.Error())
+	}
+}
+
+func TestConverter_ConversionError(t *testing.T) {
+	type Step struct {
+		Name    string `json:"name" required:"true"`
+		Retries int    `json:"retries"`
+	}
+	type Workflow struct {
+		Steps  []Step         `json:"steps"`
+		Limits map[string]int `json:"limits"`
+	}
+	var config = map[string]interface{}{
+		"steps": []interface{}{
+			map[string]interface{}{"name": "a", "retries": 1},
+			map[string]interface{}{"retries": "x"},
+		},
+		"limits": map[string]interface{}{"cpu": "y"},
+	}
+
+	{
+		converter := toolbox.NewConverter("", "json")
+		var workflow = &Workflow{}
+		err := converter.AssignConverted(workflow, config)
+		assert.NotNil(t, err)
+		errs, ok := toolbox.AsConversionErrors(err)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(errs))
+	}
+	{
+		converter := toolbox.NewConverter("", "json")
+		converter.CollectAllErrors = true
+		converter.EnforceRequired = true
+		var workflow = &Workflow{}
+		err := converter.AssignConverted(workflow, config)
+		errs, ok := err.(toolbox.ConversionErrors)
+		if !assert.True(t, ok, err) {
+			return
+		}
+		var byPath = map[string]*toolbox.ConversionError{}
+		for _, e := range errs {
+			byPath[e.Path] = e
+		}
+		assert.Equal(t, 3, len(byPath), err.Error())
+		if retries, ok := byPath["/steps/1/retries"]; assert.True(t, ok) {
+			assert.Equal(t, "Steps[1].Retries", retries.Field)
+			assert.Equal(t, "x", retries.Source)
+			assert.Equal(t, reflect.TypeOf(0), retries.Target)
+		}
+		if name, ok := byPath["/steps/1/name"]; assert.True(t, ok) {
+			assert.Equal(t, "Steps[1].Name: required field is missing", name.Error())
+		}
+		if limit, ok := byPath["/limits/cpu"]; assert.True(t, ok) {
+			assert.Equal(t, "y", limit.Source)
+		}
+		var paths = make([]string, 0)
+		for _, e := range errs {
+			paths = append(paths, e.Path)
+		}
+		assert.Equal(t, []string{"/limits/cpu", "/steps/1/name", "/steps/1/retries"}, paths)
+		assert.Equal(t, "a", workflow.Steps[0].Name)
+	}
+	{ //numeric path segments are sorted numerically
+		converter := toolbox.NewConverter("", "json")
+		converter.CollectAllErrors = true
+		var steps = make([]interface{}, 11)
+		for i := range steps {
+			steps[i] = map[string]interface{}{"name": "a", "retries": i}
+		}
+		steps[10] = map[string]interface{}{"retries": "x"}
+		steps[2] = map[string]interface{}{"retries": "y"}
+		err := converter.AssignConverted(&Workflow{}, map[string]interface{}{"steps": steps})
+		errs, ok := toolbox.AsConversionErrors(err)
+		if assert.True(t, ok, err) && assert.Equal(t, 2, len(errs)) {
+			assert.Equal(t, "/steps/2/retries", errs[0].Path)
+			assert.Equal(t, "/steps/10/retries", errs[1].Path)
+		}
+	}
+}
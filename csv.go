@@ -0,0 +1,628 @@
+package toolbox
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	csvContentType = "text/csv"
+	tsvContentType = "text/tab-separated-values"
+)
+
+//CSVOptions represents RFC 4180 CSV codec options
+type CSVOptions struct {
+	Delimiter  rune       //field delimiter, ',' by default
+	Quote      rune       //quote character, '"' by default
+	Comment    rune       //optional comment character, lines starting with it are skipped
+	Columns    []string   //optional columns, if empty the first record is a header, otherwise the first record matching columns is skipped
+	TagName    string     //struct tag mapping a field to a column, "csv" by default, field name is used if tag is missing, "-" skips a field
+	DateLayout string     //time layout, time.RFC3339 by default
+	NoHeader   bool       //encoder does not write header record
+	Converter  *Converter //converter used to assign decoded values, by default a converter with DateLayout
+}
+
+func (o *CSVOptions) init() *CSVOptions {
+	var result = CSVOptions{}
+	if o != nil {
+		result = *o
+	}
+	if result.Delimiter == 0 {
+		result.Delimiter = ','
+	}
+	if result.Quote == 0 {
+		result.Quote = '"'
+	}
+	if result.TagName == "" {
+		result.TagName = "csv"
+	}
+	if result.DateLayout == "" {
+		result.DateLayout = time.RFC3339
+	}
+	if result.Converter == nil {
+		result.Converter = NewConverter(result.DateLayout, "")
+	}
+	return &result
+}
+
+//csvReader reads RFC 4180 records, quoted fields can contain delimiters, escaped (doubled) quotes and new lines
+type csvReader struct {
+	reader  *bufio.Reader
+	options *CSVOptions
+	line    int
+}
+
+func (r *csvReader) readRune() (rune, error) {
+	aRune, _, err := r.reader.ReadRune()
+	if err == nil && aRune == '\n' {
+		r.line++
+	}
+	return aRune, err
+}
+
+//Read returns the next record, empty and comment lines are skipped, io.EOF is returned at the end of the input
+func (r *csvReader) Read() ([]string, error) {
+	for {
+		record, err := r.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			return record, nil
+		}
+	}
+}
+
+//readRecord returns a record or nil for empty and comment lines
+func (r *csvReader) readRecord() ([]string, error) {
+	startLine := r.line + 1
+	aRune, err := r.readRune()
+	if err != nil {
+		return nil, err
+	}
+	if aRune == '\n' || (aRune == '\r' && r.skipNewLine()) {
+		return nil, nil
+	}
+	if r.options.Comment != 0 && aRune == r.options.Comment {
+		_, err := r.reader.ReadString('\n')
+		if err == nil {
+			r.line++
+		} else if err != io.EOF {
+			return nil, err
+		}
+		return nil, nil
+	}
+	var record = make([]string, 0)
+	var field = new(strings.Builder)
+	for ; ; aRune, err = r.readRune() {
+		if err == io.EOF {
+			return append(record, field.String()), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch aRune {
+		case r.options.Delimiter:
+			record = append(record, field.String())
+			field.Reset()
+			continue
+		case '\n':
+			return append(record, field.String()), nil
+		case '\r':
+			if r.skipNewLine() {
+				return append(record, field.String()), nil
+			}
+		case r.options.Quote:
+			if field.Len() == 0 {
+				if err := r.readQuoted(field, startLine); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		field.WriteRune(aRune)
+	}
+}
+
+//skipNewLine consumes \n following \r, it returns true if \n was found
+func (r *csvReader) skipNewLine() bool {
+	next, _, err := r.reader.ReadRune()
+	if err != nil {
+		return false
+	}
+	if next == '\n' {
+		r.line++
+		return true
+	}
+	_ = r.reader.UnreadRune()
+	return false
+}
+
+//readQuoted reads quoted field content up to closing quote, it has to be followed by delimiter, new line or end of input
+func (r *csvReader) readQuoted(field *strings.Builder, startLine int) error {
+	for {
+		aRune, err := r.readRune()
+		if err == io.EOF {
+			return fmt.Errorf("unterminated quoted field starting at line %d", startLine)
+		}
+		if err != nil {
+			return err
+		}
+		if aRune != r.options.Quote {
+			field.WriteRune(aRune)
+			continue
+		}
+		next, _, err := r.reader.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if next == r.options.Quote {
+			field.WriteRune(aRune)
+			continue
+		}
+		_ = r.reader.UnreadRune()
+		if next != r.options.Delimiter && next != '\n' && next != '\r' {
+			return fmt.Errorf("unexpected %q after closing quote at line %d", next, r.line+1)
+		}
+		return nil
+	}
+}
+
+func newCSVReader(reader io.Reader, options *CSVOptions) *csvReader {
+	return &csvReader{reader: bufio.NewReader(reader), options: options}
+}
+
+//csvStructField represents struct field mapped to a column
+type csvStructField struct {
+	column string
+	index  []int
+	tagged bool
+}
+
+type csvStructKey struct {
+	structType reflect.Type
+	tagName    string
+}
+
+var csvStructFields = &sync.Map{}
+
+//getCSVStructFields returns exported fields mapped to columns, fields order is preserved,
+//fields of embedded structs without column name are promoted following encoding/json rules
+func getCSVStructFields(structType reflect.Type, tagName string) []*csvStructField {
+	key := csvStructKey{structType: structType, tagName: tagName}
+	if fields, ok := csvStructFields.Load(key); ok {
+		return fields.([]*csvStructField)
+	}
+	var candidates = make([]*csvStructField, 0)
+	collectCSVStructFields(structType, tagName, nil, map[reflect.Type]bool{structType: true}, &candidates)
+	var byColumn = make(map[string][]*csvStructField)
+	for _, field := range candidates {
+		byColumn[field.column] = append(byColumn[field.column], field)
+	}
+	var result = make([]*csvStructField, 0)
+	for _, field := range candidates {
+		if dominantCSVField(byColumn[field.column]) == field {
+			result = append(result, field)
+		}
+	}
+	csvStructFields.Store(key, result)
+	return result
+}
+
+//collectCSVStructFields appends struct fields with their index path, embedded structs are visited unless already on the path
+func collectCSVStructFields(structType reflect.Type, tagName string, parent []int, visited map[reflect.Type]bool, result *[]*csvStructField) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		index := append(append(make([]int, 0, len(parent)+1), parent...), i)
+		column := ""
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			column = strings.Split(tag, ",")[0]
+			if column == "-" {
+				continue
+			}
+		}
+		if field.Anonymous && column == "" {
+			fieldType := field.Type
+			isPointer := fieldType.Kind() == reflect.Ptr
+			if isPointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				if (field.PkgPath != "" && isPointer) || visited[fieldType] { //unexported embedded pointer can not be allocated
+					continue
+				}
+				visited[fieldType] = true
+				collectCSVStructFields(fieldType, tagName, index, visited, result)
+				delete(visited, fieldType)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		tagged := column != ""
+		if !tagged {
+			column = field.Name
+		}
+		*result = append(*result, &csvStructField{column: column, index: index, tagged: tagged})
+	}
+}
+
+//dominantCSVField returns the shallowest field, tagged one if more share the depth, nil if ambiguous
+func dominantCSVField(fields []*csvStructField) *csvStructField {
+	var dominant []*csvStructField
+	for _, field := range fields {
+		if len(dominant) > 0 && len(field.index) > len(dominant[0].index) {
+			continue
+		}
+		if len(dominant) > 0 && len(field.index) < len(dominant[0].index) {
+			dominant = dominant[:0]
+		}
+		dominant = append(dominant, field)
+	}
+	if len(dominant) == 1 {
+		return dominant[0]
+	}
+	var result *csvStructField
+	for _, field := range dominant {
+		if !field.tagged {
+			continue
+		}
+		if result != nil {
+			return nil
+		}
+		result = field
+	}
+	return result
+}
+
+//csvFieldValue returns struct field value for the index path, nil embedded pointers are allocated if allocate is set
+func csvFieldValue(value reflect.Value, index []int, allocate bool) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !allocate {
+					return reflect.Value{}, false
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value, true
+}
+
+type csvDecoder struct {
+	reader  *csvReader
+	options *CSVOptions
+	columns []string
+	pending []string //the first record if it did not match supplied columns
+}
+
+//readColumns reads header, supplied columns are used if the first record does not match them
+func (d *csvDecoder) readColumns() error {
+	if len(d.columns) > 0 {
+		return nil
+	}
+	record, err := d.reader.Read()
+	if err != nil {
+		return err
+	}
+	if len(d.options.Columns) > 0 {
+		d.columns = d.options.Columns
+		if !isCSVHeader(record, d.columns) {
+			d.pending = record
+		}
+		return nil
+	}
+	d.columns = make([]string, len(record))
+	for i, column := range record {
+		d.columns[i] = strings.TrimSpace(column)
+	}
+	return nil
+}
+
+func isCSVHeader(record, columns []string) bool {
+	if len(record) != len(columns) {
+		return false
+	}
+	for i, column := range columns {
+		if !strings.EqualFold(strings.TrimSpace(record[i]), column) {
+			return false
+		}
+	}
+	return true
+}
+
+//readRecord returns the next data record
+func (d *csvDecoder) readRecord() ([]string, error) {
+	if err := d.readColumns(); err != nil {
+		return nil, err
+	}
+	if record := d.pending; record != nil {
+		d.pending = nil
+		return record, nil
+	}
+	return d.reader.Read()
+}
+
+//Decode decodes the next record into pointer to a struct or map, or all remaining records into pointer to a slice.
+//Single record decoding returns io.EOF at the end of the input.
+func (d *csvDecoder) Decode(target interface{}) error {
+	if record, ok := target.(*DelimitedRecord); ok {
+		return d.decodeDelimitedRecord(record)
+	}
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return fmt.Errorf("invalid target type, expected pointer but had %T", target)
+	}
+	value := targetValue.Elem()
+	if value.Kind() != reflect.Slice {
+		record, err := d.readRecord()
+		if err != nil {
+			return err
+		}
+		return d.assign(record, value)
+	}
+	for {
+		record, err := d.readRecord()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		item := reflect.New(value.Type().Elem()).Elem()
+		if err = d.assign(record, item); err != nil {
+			return err
+		}
+		value.Set(reflect.Append(value, item))
+	}
+}
+
+func (d *csvDecoder) decodeDelimitedRecord(target *DelimitedRecord) error {
+	record, err := d.readRecord()
+	if err != nil {
+		return err
+	}
+	target.Columns = d.columns
+	if target.Record == nil {
+		target.Record = make(map[string]interface{})
+	}
+	for i, value := range record {
+		if i < len(d.columns) {
+			target.Record[d.columns[i]] = value
+		}
+	}
+	return nil
+}
+
+//assign sets record values to struct fields mapped by column or to map keys
+func (d *csvDecoder) assign(record []string, value reflect.Value) error {
+	if len(record) > len(d.columns) {
+		return fmt.Errorf("expected at most %d fields, but had %d at line %d", len(d.columns), len(record), d.reader.line)
+	}
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Map:
+		if value.IsNil() {
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+		for i, field := range record {
+			item := reflect.New(value.Type().Elem())
+			if err := d.options.Converter.AssignConverted(item.Interface(), field); err != nil {
+				return fmt.Errorf("failed to convert %v at line %d: %v", d.columns[i], d.reader.line, err)
+			}
+			value.SetMapIndex(reflect.ValueOf(d.columns[i]), item.Elem())
+		}
+		return nil
+	case reflect.Struct:
+		fields := getCSVStructFields(value.Type(), d.options.TagName)
+		for i, field := range record {
+			structField := matchCSVField(fields, d.columns[i])
+			if structField == nil || field == "" {
+				continue
+			}
+			fieldValue, _ := csvFieldValue(value, structField.index, true)
+			if err := d.options.Converter.AssignConverted(fieldValue.Addr().Interface(), field); err != nil {
+				return fmt.Errorf("failed to convert %v at line %d: %v", d.columns[i], d.reader.line, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported target type: %v, expected struct or map", value.Type())
+}
+
+func matchCSVField(fields []*csvStructField, column string) *csvStructField {
+	for _, field := range fields {
+		if field.column == column {
+			return field
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.column, column) {
+			return field
+		}
+	}
+	return nil
+}
+
+type csvDecoderFactory struct {
+	options *CSVOptions
+}
+
+func (f *csvDecoderFactory) Create(reader io.Reader) Decoder {
+	return &csvDecoder{reader: newCSVReader(reader, f.options), options: f.options}
+}
+
+//NewCSVDecoderFactory creates RFC 4180 CSV decoder factory, options are optional
+func NewCSVDecoderFactory(options *CSVOptions) DecoderFactory {
+	return &csvDecoderFactory{options: options.init()}
+}
+
+//NewTSVDecoderFactory creates tab separated values decoder factory
+func NewTSVDecoderFactory() DecoderFactory {
+	return NewCSVDecoderFactory(&CSVOptions{Delimiter: '\t'})
+}
+
+type csvEncoder struct {
+	writer        *bufio.Writer
+	options       *CSVOptions
+	columns       []string
+	headerWritten bool
+}
+
+//Encode writes a struct or map record, or records of a slice or Iterator, header is written before the first record
+func (e *csvEncoder) Encode(object interface{}) error {
+	if iterator, ok := object.(Iterator); ok {
+		for iterator.HasNext() {
+			var item interface{}
+			if err := iterator.Next(&item); err != nil {
+				return err
+			}
+			if err := e.encodeRecord(item); err != nil {
+				return err
+			}
+		}
+		if err := AsStreamIterator(iterator).Err(); err != nil {
+			return err
+		}
+		return e.writer.Flush()
+	}
+	value := reflect.ValueOf(object)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		for i := 0; i < value.Len(); i++ {
+			if err := e.encodeRecord(value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return e.writer.Flush()
+	}
+	if err := e.encodeRecord(object); err != nil {
+		return err
+	}
+	return e.writer.Flush()
+}
+
+func (e *csvEncoder) encodeRecord(item interface{}) error {
+	value := reflect.ValueOf(item)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	var record []string
+	switch value.Kind() {
+	case reflect.Struct:
+		fields := getCSVStructFields(value.Type(), e.options.TagName)
+		if len(e.columns) == 0 {
+			for _, field := range fields {
+				e.columns = append(e.columns, field.column)
+			}
+		}
+		record = make([]string, len(e.columns))
+		for i, column := range e.columns {
+			if field := matchCSVField(fields, column); field != nil {
+				if fieldValue, ok := csvFieldValue(value, field.index, false); ok {
+					record[i] = e.format(fieldValue.Interface())
+				}
+			}
+		}
+	case reflect.Map:
+		if len(e.columns) == 0 {
+			for _, key := range value.MapKeys() {
+				e.columns = append(e.columns, AsString(key.Interface()))
+			}
+			sort.Strings(e.columns)
+		}
+		record = make([]string, len(e.columns))
+		for i, column := range e.columns {
+			if item := value.MapIndex(reflect.ValueOf(column).Convert(value.Type().Key())); item.IsValid() {
+				record[i] = e.format(item.Interface())
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported record type: %T, expected struct or map", item)
+	}
+	if !e.headerWritten {
+		e.headerWritten = true
+		if !e.options.NoHeader {
+			if err := e.write(e.columns); err != nil {
+				return err
+			}
+		}
+	}
+	return e.write(record)
+}
+
+func (e *csvEncoder) format(value interface{}) string {
+	value = DereferenceValue(value)
+	switch actual := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return actual.Format(e.options.DateLayout)
+	case []byte:
+		return string(actual)
+	}
+	return AsString(value)
+}
+
+//write writes record quoting fields containing delimiter, quote, new line or leading comment character
+func (e *csvEncoder) write(record []string) error {
+	quote := string(e.options.Quote)
+	for i, field := range record {
+		if i > 0 {
+			if _, err := e.writer.WriteRune(e.options.Delimiter); err != nil {
+				return err
+			}
+		}
+		needsQuotes := strings.ContainsRune(field, e.options.Delimiter) || strings.Contains(field, quote) || strings.ContainsAny(field, "\r\n") ||
+			(i == 0 && e.options.Comment != 0 && strings.HasPrefix(field, string(e.options.Comment)))
+		if needsQuotes {
+			field = quote + strings.Replace(field, quote, quote+quote, -1) + quote
+		}
+		if _, err := e.writer.WriteString(field); err != nil {
+			return err
+		}
+	}
+	_, err := e.writer.WriteString("\r\n")
+	return err
+}
+
+type csvEncoderFactory struct {
+	options *CSVOptions
+}
+
+func (f *csvEncoderFactory) Create(writer io.Writer) Encoder {
+	return &csvEncoder{writer: bufio.NewWriter(writer), options: f.options, columns: f.options.Columns}
+}
+
+//NewCSVEncoderFactory creates RFC 4180 CSV encoder factory, options are optional
+func NewCSVEncoderFactory(options *CSVOptions) EncoderFactory {
+	return &csvEncoderFactory{options: options.init()}
+}
+
+//NewTSVEncoderFactory creates tab separated values encoder factory
+func NewTSVEncoderFactory() EncoderFactory {
+	return NewCSVEncoderFactory(&CSVOptions{Delimiter: '\t'})
+}
@@ -0,0 +1,152 @@
+package toolbox_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+type csvProduct struct {
+	ID       int       `csv:"id"`
+	Name     string    `csv:"product name"`
+	Price    float64   `csv:"price"`
+	Created  time.Time `csv:"created"`
+	Internal string    `csv:"-"`
+	Active   bool
+}
+
+func TestCSVDecoderFactory(t *testing.T) {
+	input := "id,product name,price,created,active\r\n" +
+		"1,\"Desk, large\",10.5,2021-03-26T10:00:00Z,true\r\n" +
+		"# comment line\n" +
+		"\n" +
+		"2,\"Lamp \"\"LED\"\"\nwhite\",3,,false\n"
+	decoder := toolbox.NewCSVDecoderFactory(&toolbox.CSVOptions{Comment: '#'}).Create(strings.NewReader(input))
+	var products []*csvProduct
+	assert.Nil(t, decoder.Decode(&products))
+	assert.Equal(t, 2, len(products))
+	assert.Equal(t, &csvProduct{ID: 1, Name: "Desk, large", Price: 10.5, Created: time.Date(2021, 3, 26, 10, 0, 0, 0, time.UTC), Active: true}, products[0])
+	assert.Equal(t, &csvProduct{ID: 2, Name: "Lamp \"LED\"\nwhite", Price: 3}, products[1])
+
+	decoder = toolbox.NewTSVDecoderFactory().Create(strings.NewReader("a\tb\n1\t\"x\ty\"\n2\tz"))
+	var record map[string]interface{}
+	assert.Nil(t, decoder.Decode(&record))
+	assert.Equal(t, map[string]interface{}{"a": "1", "b": "x\ty"}, record)
+	record = nil
+	assert.Nil(t, decoder.Decode(&record))
+	assert.Equal(t, map[string]interface{}{"a": "2", "b": "z"}, record)
+	assert.Equal(t, io.EOF, decoder.Decode(&record))
+
+	factory := toolbox.NewCSVDecoderFactory(&toolbox.CSVOptions{Columns: []string{"id", "price"}, Delimiter: ';', Quote: '\''})
+	var items []csvProduct
+	assert.Nil(t, factory.Create(strings.NewReader("ID;PRICE\n1;'2.5'\n3;4")).Decode(&items), "header matching columns should be skipped")
+	assert.Equal(t, []csvProduct{{ID: 1, Price: 2.5}, {ID: 3, Price: 4}}, items)
+	items = nil
+	assert.Nil(t, factory.Create(strings.NewReader("1;2.5")).Decode(&items))
+	assert.Equal(t, []csvProduct{{ID: 1, Price: 2.5}}, items)
+
+	for _, invalid := range []string{"a,b\n1,\"unterminated", "a,b\n\"x\"y,1", "a\n1,2", "id\nabc"} {
+		items = nil
+		assert.NotNil(t, toolbox.NewCSVDecoderFactory(nil).Create(strings.NewReader(invalid)).Decode(&items), invalid)
+	}
+}
+
+func TestCSVEncoderFactory(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	encoder := toolbox.NewCSVEncoderFactory(nil).Create(buffer)
+	created := time.Date(2021, 3, 26, 10, 0, 0, 0, time.UTC)
+	assert.Nil(t, encoder.Encode([]*csvProduct{{ID: 1, Name: "Desk, large", Price: 10.5, Created: created, Internal: "x", Active: true}}))
+	assert.Nil(t, encoder.Encode(csvProduct{ID: 2, Name: "Lamp \"LED\"\nwhite"}))
+	expected := "id,product name,price,created,Active\r\n" +
+		"1,\"Desk, large\",10.5,2021-03-26T10:00:00Z,true\r\n" +
+		"2,\"Lamp \"\"LED\"\"\nwhite\",0,0001-01-01T00:00:00Z,false\r\n"
+	assert.Equal(t, expected, buffer.String())
+
+	var decoded []*csvProduct
+	assert.Nil(t, toolbox.NewCSVDecoderFactory(nil).Create(strings.NewReader(buffer.String())).Decode(&decoded))
+	assert.Equal(t, "Lamp \"LED\"\nwhite", decoded[1].Name)
+
+	buffer.Reset()
+	encoder = toolbox.NewTSVEncoderFactory().Create(buffer)
+	assert.Nil(t, encoder.Encode(toolbox.NewSliceIterator([]interface{}{
+		map[string]interface{}{"b": 1, "a": "x\ty"},
+		map[string]interface{}{"a": nil, "c": 3},
+	})))
+	assert.Equal(t, "a\tb\r\n\"x\ty\"\t1\r\n\t\r\n", buffer.String())
+
+	assert.NotNil(t, encoder.Encode(1))
+}
+
+type csvAudit struct {
+	Created string `csv:"created"`
+	Updated string `csv:"updated"`
+}
+
+type CSVOwner struct {
+	Owner string `csv:"owner"`
+}
+
+type csvOrder struct {
+	ID int `csv:"id"`
+	csvAudit
+	*CSVOwner
+	Updated string `csv:"updated"`
+}
+
+func TestCSV_EmbeddedStruct(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	encoder := toolbox.NewCSVEncoderFactory(nil).Create(buffer)
+	assert.Nil(t, encoder.Encode([]*csvOrder{
+		{ID: 1, csvAudit: csvAudit{Created: "c1", Updated: "shadowed"}, Updated: "u1"},
+		{ID: 2, CSVOwner: &CSVOwner{Owner: "o2"}},
+	}))
+	assert.Equal(t, "id,created,owner,updated\r\n1,c1,,u1\r\n2,,o2,\r\n", buffer.String())
+
+	var orders []*csvOrder
+	assert.Nil(t, toolbox.NewCSVDecoderFactory(nil).Create(strings.NewReader("id,created,owner,updated\n3,c3,o3,u3")).Decode(&orders))
+	if assert.Equal(t, 1, len(orders)) {
+		assert.Equal(t, &csvOrder{ID: 3, csvAudit: csvAudit{Created: "c3"}, CSVOwner: &CSVOwner{Owner: "o3"}, Updated: "u3"}, orders[0])
+	}
+}
+
+func TestServiceRouter_CSV(t *testing.T) {
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/products",
+			Handler: func() []*csvProduct {
+				return []*csvProduct{{ID: 1, Name: "Desk", Price: 10.5}}
+			},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "POST",
+			URI:        "/v1/products",
+			Handler: func(products []*csvProduct) []*csvProduct {
+				return products
+			},
+			Parameters: []string{"products"},
+		},
+	)
+	request := httptest.NewRequest("GET", "/v1/products", nil)
+	request.Header.Set("Accept", "text/csv")
+	response := httptest.NewRecorder()
+	assert.Nil(t, router.Route(response, request))
+	assert.Equal(t, "text/csv", response.Header().Get("Content-Type"))
+	assert.Equal(t, "id,product name,price,created,Active\r\n1,Desk,10.5,0001-01-01T00:00:00Z,false\r\n", response.Body.String())
+
+	body := "id,price\n1,2\n2,3\n"
+	request = httptest.NewRequest("POST", "/v1/products", strings.NewReader(body))
+	request.Header.Set("Content-Type", "text/csv")
+	request.ContentLength = int64(len(body))
+	response = httptest.NewRecorder()
+	assert.Nil(t, router.Route(response, request))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "id,product name,price,created,Active\r\n1,,2,0001-01-01T00:00:00Z,false\r\n2,,3,0001-01-01T00:00:00Z,false\r\n", response.Body.String())
+}
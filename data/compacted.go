@@ -365,6 +365,17 @@ type iterator struct {
 	index    int
 }
 
+//Err returns nil, provider errors are returned by Next
+func (i *iterator) Err() error {
+	return nil
+}
+
+//Close releases iterated records
+func (i *iterator) Close() error {
+	i.index = i.size
+	return nil
+}
+
 //HasNext returns true if iterator has next element.
 func (i *iterator) HasNext() bool {
 	return i.index < i.size
@@ -0,0 +1,438 @@
+package data
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// compactedMagic identifies compacted slice binary format, followed by format version
+const compactedMagic = "TBCS"
+
+const compactedVersion = 1
+
+// limits of compacted slice binary format, ReadFrom rejects larger length prefixes instead of allocating them
+const (
+	maxCompactedFieldCount = 1 << 16
+	maxCompactedValueSize  = 1 << 26
+)
+
+// value tags of compacted slice binary format
+const (
+	tagNil byte = iota
+	tagNilGroup
+	tagFalse
+	tagTrue
+	tagInt
+	tagInt8
+	tagInt16
+	tagInt32
+	tagInt64
+	tagUint
+	tagUint8
+	tagUint16
+	tagUint32
+	tagUint64
+	tagFloat32
+	tagFloat64
+	tagString
+	tagBytes
+	tagTime
+	tagJSON
+)
+
+type compactedWriter struct {
+	writer  *bufio.Writer
+	written int64
+	err     error
+	buffer  [binary.MaxVarintLen64]byte
+}
+
+func (w *compactedWriter) write(data []byte) {
+	if w.err != nil {
+		return
+	}
+	var n int
+	n, w.err = w.writer.Write(data)
+	w.written += int64(n)
+}
+
+func (w *compactedWriter) writeByte(value byte) {
+	w.write([]byte{value})
+}
+
+func (w *compactedWriter) writeUvarint(value uint64) {
+	n := binary.PutUvarint(w.buffer[:], value)
+	w.write(w.buffer[:n])
+}
+
+func (w *compactedWriter) writeVarint(value int64) {
+	n := binary.PutVarint(w.buffer[:], value)
+	w.write(w.buffer[:n])
+}
+
+func (w *compactedWriter) writeBytes(value []byte) {
+	if len(value) > maxCompactedValueSize && w.err == nil {
+		w.err = fmt.Errorf("value size %d exceeds limit %d", len(value), maxCompactedValueSize)
+		return
+	}
+	w.writeUvarint(uint64(len(value)))
+	w.write(value)
+}
+
+func (w *compactedWriter) writeValue(value interface{}) {
+	switch actual := value.(type) {
+	case nil:
+		w.writeByte(tagNil)
+	case nilGroup:
+		w.writeByte(tagNilGroup)
+		w.writeUvarint(uint64(actual))
+	case bool:
+		if actual {
+			w.writeByte(tagTrue)
+		} else {
+			w.writeByte(tagFalse)
+		}
+	case int:
+		w.writeByte(tagInt)
+		w.writeVarint(int64(actual))
+	case int8:
+		w.writeByte(tagInt8)
+		w.writeVarint(int64(actual))
+	case int16:
+		w.writeByte(tagInt16)
+		w.writeVarint(int64(actual))
+	case int32:
+		w.writeByte(tagInt32)
+		w.writeVarint(int64(actual))
+	case int64:
+		w.writeByte(tagInt64)
+		w.writeVarint(actual)
+	case uint:
+		w.writeByte(tagUint)
+		w.writeUvarint(uint64(actual))
+	case uint8:
+		w.writeByte(tagUint8)
+		w.writeUvarint(uint64(actual))
+	case uint16:
+		w.writeByte(tagUint16)
+		w.writeUvarint(uint64(actual))
+	case uint32:
+		w.writeByte(tagUint32)
+		w.writeUvarint(uint64(actual))
+	case uint64:
+		w.writeByte(tagUint64)
+		w.writeUvarint(actual)
+	case float32:
+		w.writeByte(tagFloat32)
+		w.writeUvarint(uint64(math.Float32bits(actual)))
+	case float64:
+		w.writeByte(tagFloat64)
+		w.writeUvarint(math.Float64bits(actual))
+	case string:
+		w.writeByte(tagString)
+		w.writeBytes([]byte(actual))
+	case []byte:
+		w.writeByte(tagBytes)
+		w.writeBytes(actual)
+	case time.Time:
+		data, err := actual.MarshalBinary()
+		if err != nil {
+			w.err = err
+			return
+		}
+		w.writeByte(tagTime)
+		w.writeBytes(data)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			w.err = fmt.Errorf("failed to encode %T: %v", value, err)
+			return
+		}
+		w.writeByte(tagJSON)
+		w.writeBytes(data)
+	}
+}
+
+type compactedReader struct {
+	reader *bufio.Reader
+	read   int64
+}
+
+func (r *compactedReader) ReadByte() (byte, error) {
+	value, err := r.reader.ReadByte()
+	if err == nil {
+		r.read++
+	}
+	return value, err
+}
+
+func (r *compactedReader) readUvarint() (uint64, error) {
+	return binary.ReadUvarint(r)
+}
+
+func (r *compactedReader) readVarint() (int64, error) {
+	return binary.ReadVarint(r)
+}
+
+func (r *compactedReader) readBytes() ([]byte, error) {
+	length, err := r.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if length > maxCompactedValueSize {
+		return nil, fmt.Errorf("invalid value size %d: exceeds limit %d", length, maxCompactedValueSize)
+	}
+	var data = make([]byte, length)
+	n, err := io.ReadFull(r.reader, data)
+	r.read += int64(n)
+	return data, err
+}
+
+func (r *compactedReader) readValue() (interface{}, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagNil:
+		return nil, nil
+	case tagNilGroup:
+		value, err := r.readUvarint()
+		return nilGroup(value), err
+	case tagFalse:
+		return false, nil
+	case tagTrue:
+		return true, nil
+	case tagInt, tagInt8, tagInt16, tagInt32, tagInt64:
+		value, err := r.readVarint()
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case tagInt:
+			return int(value), nil
+		case tagInt8:
+			return int8(value), nil
+		case tagInt16:
+			return int16(value), nil
+		case tagInt32:
+			return int32(value), nil
+		}
+		return value, nil
+	case tagUint, tagUint8, tagUint16, tagUint32, tagUint64:
+		value, err := r.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case tagUint:
+			return uint(value), nil
+		case tagUint8:
+			return uint8(value), nil
+		case tagUint16:
+			return uint16(value), nil
+		case tagUint32:
+			return uint32(value), nil
+		}
+		return value, nil
+	case tagFloat32:
+		value, err := r.readUvarint()
+		return math.Float32frombits(uint32(value)), err
+	case tagFloat64:
+		value, err := r.readUvarint()
+		return math.Float64frombits(value), err
+	case tagString:
+		data, err := r.readBytes()
+		return string(data), err
+	case tagBytes:
+		return r.readBytes()
+	case tagTime:
+		data, err := r.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		var value time.Time
+		err = value.UnmarshalBinary(data)
+		return value, err
+	case tagJSON:
+		data, err := r.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		err = json.Unmarshal(data, &value)
+		return value, err
+	}
+	return nil, fmt.Errorf("unsupported value tag: %v", tag)
+}
+
+// WriteTo writes fields and records in compact binary format, values of types other than
+// bool, numbers, string, []byte and time.Time are stored as JSON. Unlike Range, WriteTo does not remove data from this slice.
+func (s *CompactedSlice) WriteTo(writer io.Writer) (int64, error) {
+	fields, data := s.snapshot()
+	w := &compactedWriter{writer: bufio.NewWriter(writer)}
+	w.write([]byte(compactedMagic))
+	w.writeByte(compactedVersion)
+	if s.compressNils {
+		w.writeByte(1)
+	} else {
+		w.writeByte(0)
+	}
+	w.writeUvarint(uint64(len(fields)))
+	for _, field := range fields {
+		w.writeBytes([]byte(field.Name))
+		w.writeValue(fieldTypeSample(field.Type))
+	}
+	w.writeUvarint(uint64(len(data)))
+	for _, item := range data {
+		w.writeUvarint(uint64(len(item)))
+		for _, value := range item {
+			w.writeValue(value)
+		}
+	}
+	if w.err == nil {
+		w.err = w.writer.Flush()
+	}
+	return w.written, w.err
+}
+
+// fieldTypeSample returns zero value representing field type or nil if the type can not be restored from binary format
+func fieldTypeSample(fieldType reflect.Type) interface{} {
+	if fieldType == nil {
+		return nil
+	}
+	switch fieldType.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		if fieldType.PkgPath() == "" {
+			return reflect.Zero(fieldType).Interface()
+		}
+	}
+	if fieldType == reflect.TypeOf([]byte{}) || fieldType == reflect.TypeOf(time.Time{}) {
+		return reflect.Zero(fieldType).Interface()
+	}
+	return nil
+}
+
+// validateRecord checks that record expands to at most fieldCount values, nil groups are only valid in compressed record
+func validateRecord(item []interface{}, compressed bool, fieldCount int) error {
+	var result = 0
+	for _, value := range item {
+		group, ok := value.(nilGroup)
+		if !ok {
+			result++
+			continue
+		}
+		if !compressed {
+			return fmt.Errorf("unexpected nil group in uncompressed record")
+		}
+		if group < 2 || int(group) > fieldCount {
+			return fmt.Errorf("invalid nil group size %d", group)
+		}
+		if result += int(group); result > fieldCount {
+			return fmt.Errorf("expected at most %d values, but had %d", fieldCount, result)
+		}
+	}
+	return nil
+}
+
+// ReadFrom appends fields and records written by WriteTo, records are stored with this slice nil compression setting
+func (s *CompactedSlice) ReadFrom(reader io.Reader) (int64, error) {
+	r := &compactedReader{reader: bufio.NewReader(reader)}
+	err := s.readFrom(r)
+	return r.read, err
+}
+
+func (s *CompactedSlice) readFrom(r *compactedReader) error {
+	var header = make([]byte, len(compactedMagic)+2)
+	n, err := io.ReadFull(r.reader, header)
+	r.read += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to read header: %v", err)
+	}
+	if string(header[:len(compactedMagic)]) != compactedMagic {
+		return fmt.Errorf("invalid compacted slice format")
+	}
+	if version := header[len(compactedMagic)]; version != compactedVersion {
+		return fmt.Errorf("unsupported compacted slice format version: %v", version)
+	}
+	compressed := header[len(compactedMagic)+1] == 1
+	fieldCount, err := r.readUvarint()
+	if err != nil {
+		return err
+	}
+	if fieldCount > maxCompactedFieldCount {
+		return fmt.Errorf("invalid field count %d: exceeds limit %d", fieldCount, maxCompactedFieldCount)
+	}
+	var names = make([]string, fieldCount)
+	var samples = make([]interface{}, fieldCount)
+	for i := range names {
+		name, err := r.readBytes()
+		if err != nil {
+			return err
+		}
+		if samples[i], err = r.readValue(); err != nil {
+			return err
+		}
+		names[i] = string(name)
+	}
+	recordCount, err := r.readUvarint()
+	if err != nil {
+		return err
+	}
+	var decoded = make([][]interface{}, 0)
+	for i := uint64(0); i < recordCount; i++ {
+		itemLength, err := r.readUvarint()
+		if err != nil {
+			return err
+		}
+		if itemLength > fieldCount {
+			return fmt.Errorf("invalid record %d: expected at most %d values, but had %d", i, fieldCount, itemLength)
+		}
+		var item = make([]interface{}, itemLength)
+		for j := range item {
+			if item[j], err = r.readValue(); err != nil {
+				return fmt.Errorf("failed to read record %d: %v", i, err)
+			}
+		}
+		if err := validateRecord(item, compressed, int(fieldCount)); err != nil {
+			return fmt.Errorf("invalid record %d: %v", i, err)
+		}
+		var stored = make([]interface{}, fieldCount)
+		if compressed {
+			s.uncompress(item, stored)
+		} else {
+			copy(stored, item)
+		}
+		decoded = append(decoded, stored)
+	}
+	//fields and records are added once the whole input is decoded
+	var positions = make([]int, fieldCount)
+	for i, name := range names {
+		positions[i] = s.index(name, samples[i])
+	}
+	var records = make([][]interface{}, len(decoded))
+	for i, stored := range decoded {
+		var record = make([]interface{}, len(s.fields))
+		for j, position := range positions {
+			record[position] = stored[j]
+		}
+		if s.compressNils {
+			record = s.compress(record)
+		}
+		records[i] = record
+	}
+	s.lock.Lock()
+	s.data = append(s.data, records...)
+	s.lock.Unlock()
+	atomic.AddInt64(&s.size, int64(len(records)))
+	return nil
+}
@@ -0,0 +1,122 @@
+package data
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactedSlice_WriteTo(t *testing.T) {
+	now := time.Date(2021, 3, 26, 10, 0, 0, 0, time.UTC)
+	var records = []map[string]interface{}{
+		{"id": 1, "name": "a", "price": 1.5, "active": true, "created": now},
+		{"id": int64(2), "code": uint8(7), "tags": []interface{}{"x", "y"}},
+		{"id": 3, "raw": []byte("abc"), "ratio": float32(0.5)},
+	}
+	for _, useCase := range []struct {
+		description string
+		source      bool
+		target      bool
+	}{
+		{"compressed", true, true},
+		{"compressed to uncompressed", true, false},
+		{"uncompressed to compressed", false, true},
+	} {
+		collection := NewCompactedSlice(false, useCase.source)
+		for _, record := range records {
+			collection.Add(record)
+		}
+		buffer := new(bytes.Buffer)
+		written, err := collection.WriteTo(buffer)
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, buffer.Len(), written, useCase.description)
+		assert.Equal(t, 3, collection.Size(), useCase.description)
+
+		loaded := NewCompactedSlice(false, useCase.target)
+		read, err := loaded.ReadFrom(bytes.NewReader(buffer.Bytes()))
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, written, read, useCase.description)
+		assert.Equal(t, 3, loaded.Size(), useCase.description)
+		for _, field := range loaded.Fields() {
+			if field.Name == "id" {
+				assert.Equal(t, reflect.TypeOf(0), field.Type)
+			}
+		}
+		assert.EqualValues(t, records, rangeAll(t, loaded), useCase.description)
+	}
+}
+
+func TestCompactedSlice_ReadFrom(t *testing.T) {
+	source := NewCompactedSlice(false, true)
+	source.Add(map[string]interface{}{"b": 2, "c": "x"})
+	buffer := new(bytes.Buffer)
+	_, err := source.WriteTo(buffer)
+	assert.Nil(t, err)
+
+	target := NewCompactedSlice(false, true)
+	target.Add(map[string]interface{}{"a": 1, "b": 1})
+	_, err = target.ReadFrom(buffer)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []map[string]interface{}{{"a": 1, "b": 1}, {"b": 2, "c": "x"}}, rangeAll(t, target))
+
+	_, err = NewCompactedSlice(false, true).ReadFrom(bytes.NewReader([]byte("invalid")))
+	assert.NotNil(t, err)
+	_, err = NewCompactedSlice(false, true).ReadFrom(bytes.NewReader([]byte(compactedMagic)))
+	assert.NotNil(t, err)
+}
+
+func TestCompactedSlice_ReadFromCorrupted(t *testing.T) {
+	header := []byte(compactedMagic)
+	header = append(header, compactedVersion, 0)
+	uvarint := func(value uint64) []byte {
+		var buffer = make([]byte, binary.MaxVarintLen64)
+		return buffer[:binary.PutUvarint(buffer, value)]
+	}
+	join := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+	field := join(uvarint(1), []byte("a"), []byte{tagInt}, uvarint(2))
+	compressedHeader := []byte(compactedMagic)
+	compressedHeader = append(compressedHeader, compactedVersion, 1)
+	fields := join(uvarint(3), field, uvarint(1), []byte("b"), []byte{tagInt}, uvarint(2), uvarint(1), []byte("c"), []byte{tagInt}, uvarint(2))
+	nilGroupRecord := func(size uint64) []byte {
+		return join(uvarint(2), []byte{tagNilGroup}, uvarint(size), []byte{tagInt}, uvarint(2))
+	}
+	var useCases = []struct {
+		description string
+		data        []byte
+		expect      string
+	}{
+		{description: "field count", data: join(header, uvarint(math.MaxUint64)), expect: "invalid field count"},
+		{description: "field name size", data: join(header, uvarint(1), uvarint(math.MaxUint64)), expect: "invalid value size"},
+		{description: "record length", data: join(header, uvarint(1), field, uvarint(1), uvarint(math.MaxUint64)), expect: "invalid record 0"},
+		{description: "string value size", data: join(header, uvarint(1), field, uvarint(1), uvarint(1), []byte{tagString}, uvarint(math.MaxUint64)), expect: "invalid value size"},
+		{description: "nil group overflow", data: join(compressedHeader, fields, uvarint(1), nilGroupRecord(math.MaxUint64)), expect: "invalid nil group size"},
+		{description: "nil group of one", data: join(compressedHeader, fields, uvarint(1), nilGroupRecord(1)), expect: "invalid nil group size"},
+		{description: "nil group above field count", data: join(compressedHeader, fields, uvarint(1), nilGroupRecord(4)), expect: "invalid nil group size"},
+		{description: "nil groups above field count", data: join(compressedHeader, fields, uvarint(1), join(uvarint(3), []byte{tagNilGroup}, uvarint(2), []byte{tagNilGroup}, uvarint(2), []byte{tagInt}, uvarint(2))), expect: "expected at most 3 values"},
+		{description: "nil group in uncompressed record", data: join(header, fields, uvarint(1), nilGroupRecord(2)), expect: "unexpected nil group"},
+		{description: "corrupted second record", data: join(compressedHeader, fields, uvarint(2), nilGroupRecord(2), nilGroupRecord(5)), expect: "invalid record 1"},
+	}
+	for _, useCase := range useCases {
+		target := NewCompactedSlice(false, true)
+		_, err := target.ReadFrom(bytes.NewReader(useCase.data))
+		if assert.NotNil(t, err, useCase.description) {
+			assert.Contains(t, err.Error(), useCase.expect, useCase.description)
+		}
+		assert.Equal(t, 0, target.Size(), useCase.description)
+		assert.Equal(t, 0, len(target.Fields()), useCase.description)
+	}
+	valid := join(compressedHeader, fields, uvarint(1), nilGroupRecord(2))
+	target := NewCompactedSlice(false, true)
+	_, err := target.ReadFrom(bytes.NewReader(valid))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, target.Size())
+		assert.Equal(t, 3, len(target.Fields()))
+	}
+}
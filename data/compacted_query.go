@@ -0,0 +1,277 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// Aggregate functions supported by CompactedSlice.GroupBy
+const (
+	AggregateCount = "count"
+	AggregateSum   = "sum"
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+)
+
+// Aggregate represents group by aggregation
+type Aggregate struct {
+	Function string //count, sum, min or max
+	Field    string //aggregated field, count without field counts records
+	Alias    string //result field name, defaults to function_field, i.e. sum_amount
+}
+
+// Name returns aggregation result field name
+func (a *Aggregate) Name() string {
+	if a.Alias != "" {
+		return a.Alias
+	}
+	if a.Field == "" {
+		return a.Function
+	}
+	return a.Function + "_" + a.Field
+}
+
+type aggregateState struct {
+	count   int
+	sum     int64
+	sumF    float64
+	isFloat bool
+	value   interface{}
+}
+
+func (a *aggregateState) add(function string, value interface{}) {
+	if value == nil {
+		return
+	}
+	a.count++
+	switch function {
+	case AggregateSum:
+		if toolbox.IsInt(value) && !a.isFloat {
+			a.sum += int64(toolbox.AsInt(value))
+			return
+		}
+		if !a.isFloat {
+			a.isFloat = true
+			a.sumF = float64(a.sum)
+		}
+		a.sumF += toolbox.AsFloat(value)
+	case AggregateMin:
+		if a.value == nil || lessValue(value, a.value) {
+			a.value = value
+		}
+	case AggregateMax:
+		if a.value == nil || lessValue(a.value, value) {
+			a.value = value
+		}
+	}
+}
+
+func (a *aggregateState) result(function string) interface{} {
+	switch function {
+	case AggregateCount:
+		return a.count
+	case AggregateSum:
+		if a.isFloat {
+			return a.sumF
+		}
+		return int(a.sum)
+	}
+	return a.value
+}
+
+// lessValue compares numbers numerically and other values as text
+func lessValue(left, right interface{}) bool {
+	if toolbox.IsNumber(left) && toolbox.IsNumber(right) {
+		return toolbox.AsFloat(left) < toolbox.AsFloat(right)
+	}
+	return toolbox.AsString(left) < toolbox.AsString(right)
+}
+
+// snapshot returns fields and stored records, records are never modified in place so they can be shared
+func (s *CompactedSlice) snapshot() ([]*Field, [][]interface{}) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.fields, s.data
+}
+
+// decode returns full length record for the stored item
+func (s *CompactedSlice) decode(item []interface{}, record []interface{}) []interface{} {
+	if s.compressNils {
+		s.uncompress(item, record)
+		return record
+	}
+	copy(record, item)
+	for i := len(item); i < len(record); i++ {
+		record[i] = nil
+	}
+	return record
+}
+
+// derive creates an empty compacted slice with the same options and supplied fields
+func (s *CompactedSlice) derive(fields []*Field) *CompactedSlice {
+	result := NewCompactedSlice(s.omitEmpty, s.compressNils)
+	result.RawEncoding = s.RawEncoding
+	for i, field := range fields {
+		clone := &Field{Name: field.Name, Type: field.Type, index: i}
+		result.fields = append(result.fields, clone)
+		result.fieldNames[clone.Name] = clone
+	}
+	return result
+}
+
+// Filter returns a new compacted slice with records matching all field predicates, missing field value is passed to the predicate as nil.
+// Unlike Range, Filter does not remove data from this slice.
+func (s *CompactedSlice) Filter(predicates map[string]toolbox.Predicate) (*CompactedSlice, error) {
+	fields, data := s.snapshot()
+	var positions = make(map[int]toolbox.Predicate)
+	for name, predicate := range predicates {
+		field, ok := s.lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("failed to lookup Field: %v", name)
+		}
+		positions[field.index] = predicate
+	}
+	result := s.derive(fields)
+	var record = make([]interface{}, len(fields))
+outer:
+	for _, item := range data {
+		record = s.decode(item, record)
+		for position, predicate := range positions {
+			if !predicate.Apply(record[position]) {
+				continue outer
+			}
+		}
+		result.data = append(result.data, item)
+		result.size++
+	}
+	return result, nil
+}
+
+// Project returns a new compacted slice with supplied fields only
+func (s *CompactedSlice) Project(names ...string) (*CompactedSlice, error) {
+	fields, data := s.snapshot()
+	var projected = make([]*Field, len(names))
+	for i, name := range names {
+		field, ok := s.lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("failed to lookup Field: %v", name)
+		}
+		projected[i] = field
+	}
+	result := s.derive(projected)
+	var record = make([]interface{}, len(fields))
+	for _, item := range data {
+		record = s.decode(item, record)
+		var projection = make([]interface{}, len(projected))
+		for i, field := range projected {
+			projection[i] = record[field.index]
+		}
+		if s.compressNils {
+			projection = s.compress(projection)
+		}
+		result.data = append(result.data, projection)
+		result.size++
+	}
+	return result, nil
+}
+
+// GroupBy returns a new compacted slice with a record per distinct groupBy field values holding the key fields and aggregation results.
+// Groups are returned in the order of their first occurrence, nil values are ignored by aggregates.
+func (s *CompactedSlice) GroupBy(groupBy []string, aggregates ...*Aggregate) (*CompactedSlice, error) {
+	fields, data := s.snapshot()
+	keyPositions, err := s.positions(groupBy)
+	if err != nil {
+		return nil, err
+	}
+	var aggregatePositions = make([]int, len(aggregates))
+	for i, aggregate := range aggregates {
+		switch aggregate.Function {
+		case AggregateCount, AggregateSum, AggregateMin, AggregateMax:
+		default:
+			return nil, fmt.Errorf("unsupported aggregate: %v", aggregate.Function)
+		}
+		aggregatePositions[i] = -1
+		if aggregate.Field == "" {
+			if aggregate.Function != AggregateCount {
+				return nil, fmt.Errorf("%v field was empty", aggregate.Function)
+			}
+			continue
+		}
+		field, ok := s.lookup(aggregate.Field)
+		if !ok {
+			return nil, fmt.Errorf("failed to lookup Field: %v", aggregate.Field)
+		}
+		aggregatePositions[i] = field.index
+	}
+
+	type group struct {
+		key    []interface{}
+		states []*aggregateState
+	}
+	var groups = make(map[string]*group)
+	var order = make([]*group, 0)
+	var record = make([]interface{}, len(fields))
+	for _, item := range data {
+		record = s.decode(item, record)
+		var key = make([]interface{}, len(keyPositions))
+		for i, position := range keyPositions {
+			key[i] = record[position]
+		}
+		groupKey := encodeGroupKey(key)
+		aGroup, ok := groups[groupKey]
+		if !ok {
+			aGroup = &group{key: key, states: make([]*aggregateState, len(aggregates))}
+			for i := range aggregates {
+				aGroup.states[i] = &aggregateState{}
+			}
+			groups[groupKey] = aGroup
+			order = append(order, aGroup)
+		}
+		for i, aggregate := range aggregates {
+			var value interface{} = true
+			if position := aggregatePositions[i]; position != -1 {
+				value = record[position]
+			}
+			aGroup.states[i].add(aggregate.Function, value)
+		}
+	}
+
+	result := NewCompactedSlice(false, s.compressNils)
+	for _, aGroup := range order {
+		var aMap = make(map[string]interface{})
+		for i, name := range groupBy {
+			aMap[name] = aGroup.key[i]
+		}
+		for i, aggregate := range aggregates {
+			aMap[aggregate.Name()] = aGroup.states[i].result(aggregate.Function)
+		}
+		result.Add(aMap)
+	}
+	return result, nil
+}
+
+// encodeGroupKey returns group key with type and length prefixed values, so that distinct values never share a key, i.e. ("x y", "z") and ("x", "y z") or 1 and "1"
+func encodeGroupKey(key []interface{}) string {
+	var result = new(strings.Builder)
+	for _, value := range key {
+		typeName := fmt.Sprintf("%T", value)
+		text := fmt.Sprintf("%v", value)
+		fmt.Fprintf(result, "%d:%v%d:%v", len(typeName), typeName, len(text), text)
+	}
+	return result.String()
+}
+
+func (s *CompactedSlice) lookup(name string) (*Field, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	field, ok := s.fieldNames[name]
+	return field, ok
+}
+
+func (s *CompactedSlice) positions(names []string) ([]int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mapNamesToFieldPositions(names)
+}
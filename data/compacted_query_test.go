@@ -0,0 +1,105 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+func newTestSales(compressNils bool) *CompactedSlice {
+	collection := NewCompactedSlice(false, compressNils)
+	collection.Add(map[string]interface{}{"region": "us", "product": "a", "amount": 10, "note": "first"})
+	collection.Add(map[string]interface{}{"region": "eu", "product": "b", "amount": 2.5})
+	collection.Add(map[string]interface{}{"region": "us", "product": "c", "amount": 30})
+	collection.Add(map[string]interface{}{"region": "eu", "product": "a"})
+	return collection
+}
+
+func rangeAll(t *testing.T, collection *CompactedSlice) []map[string]interface{} {
+	var actual = make([]map[string]interface{}, 0)
+	err := collection.Range(func(item interface{}) (bool, error) {
+		actual = append(actual, toolbox.AsMap(item))
+		return true, nil
+	})
+	assert.Nil(t, err)
+	return actual
+}
+
+func TestCompactedSlice_Filter(t *testing.T) {
+	collection := newTestSales(true)
+	filtered, err := collection.Filter(map[string]toolbox.Predicate{
+		"region": toolbox.NewInPredicate("us"),
+		"amount": toolbox.NewComparablePredicate(">", 15),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, filtered.Size())
+	assert.EqualValues(t, []map[string]interface{}{{"region": "us", "product": "c", "amount": 30}}, rangeAll(t, filtered))
+	assert.Equal(t, 4, collection.Size(), "filter should not remove data")
+
+	filtered, err = collection.Filter(map[string]toolbox.Predicate{"amount": toolbox.NewNilPredicate()})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []map[string]interface{}{{"region": "eu", "product": "a"}}, rangeAll(t, filtered))
+
+	_, err = collection.Filter(map[string]toolbox.Predicate{"unknown": toolbox.NewNilPredicate()})
+	assert.NotNil(t, err)
+}
+
+func TestCompactedSlice_Project(t *testing.T) {
+	for _, compressNils := range []bool{true, false} {
+		collection := newTestSales(compressNils)
+		projected, err := collection.Project("product", "note")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(projected.Fields()))
+		assert.EqualValues(t, []map[string]interface{}{
+			{"product": "a", "note": "first"},
+			{"product": "b"},
+			{"product": "c"},
+			{"product": "a"},
+		}, rangeAll(t, projected))
+	}
+	_, err := newTestSales(true).Project("unknown")
+	assert.NotNil(t, err)
+}
+
+func TestCompactedSlice_GroupBy(t *testing.T) {
+	collection := newTestSales(true)
+	grouped, err := collection.GroupBy([]string{"region"},
+		&Aggregate{Function: AggregateCount},
+		&Aggregate{Function: AggregateCount, Field: "amount"},
+		&Aggregate{Function: AggregateSum, Field: "amount", Alias: "total"},
+		&Aggregate{Function: AggregateMin, Field: "product"},
+		&Aggregate{Function: AggregateMax, Field: "amount"},
+	)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []map[string]interface{}{
+		{"region": "us", "count": 2, "count_amount": 2, "total": 40, "min_product": "a", "max_amount": 30},
+		{"region": "eu", "count": 2, "count_amount": 1, "total": 2.5, "min_product": "a", "max_amount": 2.5},
+	}, rangeAll(t, grouped))
+
+	grouped, err = collection.GroupBy(nil, &Aggregate{Function: AggregateSum, Field: "amount"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []map[string]interface{}{{"sum_amount": 42.5}}, rangeAll(t, grouped))
+
+	_, err = collection.GroupBy([]string{"region"}, &Aggregate{Function: "avg", Field: "amount"})
+	assert.NotNil(t, err)
+	_, err = collection.GroupBy([]string{"region"}, &Aggregate{Function: AggregateSum})
+	assert.NotNil(t, err)
+}
+
+func TestCompactedSlice_GroupByDistinctKeys(t *testing.T) {
+	collection := NewCompactedSlice(false, true)
+	collection.Add(map[string]interface{}{"a": "x y", "b": "z", "amount": 1})
+	collection.Add(map[string]interface{}{"a": "x", "b": "y z", "amount": 2})
+	collection.Add(map[string]interface{}{"a": 1, "b": "z", "amount": 3})
+	collection.Add(map[string]interface{}{"a": "1", "b": "z", "amount": 4})
+	collection.Add(map[string]interface{}{"a": "x", "b": "y z", "amount": 5})
+	grouped, err := collection.GroupBy([]string{"a", "b"}, &Aggregate{Function: AggregateSum, Field: "amount"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []map[string]interface{}{
+		{"a": "x y", "b": "z", "sum_amount": 1},
+		{"a": "x", "b": "y z", "sum_amount": 7},
+		{"a": 1, "b": "z", "sum_amount": 3},
+		{"a": "1", "b": "z", "sum_amount": 4},
+	}, rangeAll(t, grouped))
+}
@@ -0,0 +1,334 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// Reference represents a variable or UDF call referenced by an expandable text
+type Reference struct {
+	Expression string //expression as it appears in the text, i.e. $x, ${a.b[1]} or $Fn($x), operands of ${...} operator expression are reported as $name
+	Name       string //variable path or UDF name, i.e. x, a.b[1] or Fn
+	IsUDF      bool
+	Path       string //location of the text within source data structure, i.e. /k1/0, empty for top level text
+	Position   int    //position of the expression within the text
+}
+
+// Root returns top level variable or UDF name, i.e. a for a.b[1]
+func (r *Reference) Root() string {
+	if index := strings.IndexAny(r.Name, ".["); index != -1 {
+		return r.Name[:index]
+	}
+	return r.Name
+}
+
+// References represents a list of references
+type References []*Reference
+
+// Variables returns unique sorted names of referenced variables
+func (r References) Variables() []string {
+	return r.names(false)
+}
+
+// UDFs returns unique sorted names of referenced UDFs
+func (r References) UDFs() []string {
+	return r.names(true)
+}
+
+func (r References) names(udf bool) []string {
+	var unique = make(map[string]bool)
+	var result = make([]string, 0)
+	for _, reference := range r {
+		if reference.IsUDF != udf || unique[reference.Name] {
+			continue
+		}
+		unique[reference.Name] = true
+		result = append(result, reference.Name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ReferencesOf returns all variables and UDFs referenced by a text or data structure, nothing is evaluated
+func ReferencesOf(source interface{}) References {
+	var result = make(References, 0)
+	collectReferences(source, "", &result)
+	return result
+}
+
+// DryRun returns references of a text or data structure split into ones the map can satisfy and missing ones, nothing is evaluated
+func (s *Map) DryRun(source interface{}) (satisfied, missing References) {
+	satisfied, missing = make(References, 0), make(References, 0)
+	for _, reference := range ReferencesOf(source) {
+		if s.Satisfies(reference) {
+			satisfied = append(satisfied, reference)
+			continue
+		}
+		missing = append(missing, reference)
+	}
+	return satisfied, missing
+}
+
+// Satisfies returns true if the map defines referenced UDF or variable, for a variable path with dynamic index (i.e. a[$i].b) only the path before the index is checked
+func (s *Map) Satisfies(reference *Reference) bool {
+	if reference.IsUDF {
+		udfs := s.GetMap(UDFKey)
+		candidate, ok := udfs.GetValue(reference.Name)
+		if !ok {
+			candidate, ok = s.GetValue(reference.Name)
+		}
+		switch candidate.(type) {
+		case func(interface{}, Map) (interface{}, error), Udf:
+			return ok
+		}
+		return false
+	}
+	name := reference.Name
+	if index := strings.Index(name, "$"); index != -1 {
+		if bracket := strings.LastIndex(name[:index], "["); bracket != -1 {
+			name = name[:bracket]
+		}
+	}
+	_, ok := s.GetValue(name)
+	return ok
+}
+
+// DependencyOrder returns definition keys ordered so that each key follows the keys referenced by its value,
+// independent keys are sorted by name, references to keys outside of definitions are ignored, it returns an error if definitions have a cycle
+func DependencyOrder(definitions map[string]interface{}) ([]string, error) {
+	var keys = make([]string, 0, len(definitions))
+	var dependencies = make(map[string]map[string]bool)
+	for key, value := range definitions {
+		keys = append(keys, key)
+		dependencies[key] = make(map[string]bool)
+		for _, reference := range ReferencesOf(value) {
+			root := reference.Root()
+			if _, ok := definitions[root]; ok && !reference.IsUDF {
+				dependencies[key][root] = true
+			}
+		}
+	}
+	sort.Strings(keys)
+	var result = make([]string, 0, len(keys))
+	var ordered = make(map[string]bool)
+	for len(result) < len(keys) {
+		progress := false
+		for _, key := range keys {
+			if ordered[key] || !isSatisfied(dependencies[key], ordered) {
+				continue
+			}
+			ordered[key] = true
+			result = append(result, key)
+			progress = true
+			break
+		}
+		if !progress {
+			var cycle = make([]string, 0)
+			for _, key := range keys {
+				if !ordered[key] {
+					cycle = append(cycle, key)
+				}
+			}
+			return nil, fmt.Errorf("detected dependency cycle: %v", strings.Join(cycle, ", "))
+		}
+	}
+	return result, nil
+}
+
+func isSatisfied(dependencies map[string]bool, ordered map[string]bool) bool {
+	for dependency := range dependencies {
+		if !ordered[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+func collectReferences(source interface{}, path string, result *References) {
+	switch value := source.(type) {
+	case string:
+		collectTextReferences(value, 0, path, result)
+	case *string:
+		if value != nil {
+			collectTextReferences(*value, 0, path, result)
+		}
+	case []byte:
+		collectTextReferences(string(value), 0, path, result)
+	case map[string]interface{}:
+		for _, key := range sortedKeys(value) {
+			collectTextReferences(key, 0, path, result)
+			collectReferences(value[key], path+"/"+key, result)
+		}
+	case []interface{}:
+		for i, item := range value {
+			collectReferences(item, path+"/"+toolbox.AsString(i), result)
+		}
+	default:
+		if source == nil {
+			return
+		}
+		if toolbox.IsMap(source) {
+			aMap := toolbox.AsMap(source)
+			for _, key := range sortedKeys(aMap) {
+				collectTextReferences(key, 0, path, result)
+				collectReferences(aMap[key], path+"/"+key, result)
+			}
+		} else if toolbox.IsSlice(source) {
+			collectReferences(toolbox.AsSlice(source), path, result)
+		}
+	}
+}
+
+func sortedKeys(aMap map[string]interface{}) []string {
+	var result = make([]string, 0, len(aMap))
+	for key := range aMap {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// collectTextReferences uses parser tokenizer to collect references, offset is the text position within the enclosing text
+func collectTextReferences(text string, offset int, path string, result *References) {
+	if !strings.Contains(text, "$") {
+		return
+	}
+	tokenizer := toolbox.NewTokenizer(text, invalidToken, eofToken, matchers)
+	for tokenizer.Index < len(text) {
+		match := tokenizer.Nexts(beforeVarToken, varToken, unmatchedToken, eofToken)
+		switch match.Token {
+		case beforeVarToken:
+			continue
+		case varToken:
+			position := tokenizer.Index - 1
+			match = tokenizer.Nexts(idToken, enclosedVarToken, incToken, decrementToken, shiftToken)
+			switch match.Token {
+			case enclosedVarToken:
+				collectEnclosedReferences(text[position:tokenizer.Index], offset+position, path, result)
+			case incToken, decrementToken, shiftToken:
+				if match = tokenizer.Nexts(idToken); match.Token != idToken {
+					continue
+				}
+				tokenizer.Index -= len(match.Matched)
+				collectVariableReference(tokenizer, text, position, offset, path, result)
+			case idToken:
+				tokenizer.Index -= len(match.Matched)
+				collectVariableReference(tokenizer, text, position, offset, path, result)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// collectVariableReference collects variable or UDF call reference starting at tokenizer position
+func collectVariableReference(tokenizer *toolbox.Tokenizer, text string, position, offset int, path string, result *References) {
+	match := tokenizer.Nexts(idToken)
+	name := expandVariable(tokenizer, match.Matched, func(expression string, isUDF bool, argument interface{}) (interface{}, bool) {
+		return expression, true
+	})
+	reference := &Reference{Name: name, Path: path, Position: offset + position}
+	var arguments string
+	argumentsPosition := 0
+	switch match = tokenizer.Nexts(callToken, incToken, decrementToken); match.Token {
+	case callToken:
+		reference.IsUDF = true
+		arguments = match.Matched[1 : len(match.Matched)-1]
+		argumentsPosition = tokenizer.Index - len(match.Matched) + 1
+	}
+	reference.Expression = text[position:tokenizer.Index]
+	*result = append(*result, reference)
+	if index := strings.Index(name, "$"); index != -1 { //dynamic index
+		nameStart := strings.Index(text[position:], name) + position
+		collectTextReferences(name[index:], offset+nameStart+index, path, result)
+	}
+	if arguments != "" {
+		collectTextReferences(arguments, offset+argumentsPosition, path, result)
+	}
+}
+
+// collectEnclosedReferences collects references of ${...} expression, compiled expression tree is used for operator expressions
+func collectEnclosedReferences(enclosed string, position int, path string, result *References) {
+	body := enclosed[2 : len(enclosed)-1]
+	expression, err := Compile(body)
+	if err != nil {
+		collectTextReferences("$"+body, position+1, path, result)
+		return
+	}
+	if variable, ok := expression.root.(*variableNode); ok {
+		*result = append(*result, &Reference{Expression: enclosed, Name: variablePath(variable), Path: path, Position: position})
+		collectIndexReferences(variable, body, position+2, path, result)
+		return
+	}
+	if call, ok := expression.root.(*callNode); ok && strings.HasSuffix(strings.TrimSpace(body), ")") {
+		*result = append(*result, &Reference{Expression: enclosed, Name: variablePath(call.variable), IsUDF: true, Path: path, Position: position})
+		collectCallReferences(call, body, position+2, path, result)
+		return
+	}
+	collectNodeReferences(expression.root, body, position+2, path, result)
+}
+
+func collectNodeReferences(node exprNode, body string, offset int, path string, result *References) {
+	switch actual := node.(type) {
+	case *variableNode:
+		name := variablePath(actual)
+		*result = append(*result, &Reference{Expression: "$" + name, Name: name, Path: path, Position: offset + actual.position})
+		collectIndexReferences(actual, body, offset, path, result)
+	case *callNode:
+		name := variablePath(actual.variable)
+		*result = append(*result, &Reference{Expression: "$" + name + "(" + actual.arguments + ")", Name: name, IsUDF: true, Path: path, Position: offset + actual.position})
+		collectCallReferences(actual, body, offset, path, result)
+	case *unaryNode:
+		collectNodeReferences(actual.operand, body, offset, path, result)
+	case *binaryNode:
+		collectNodeReferences(actual.left, body, offset, path, result)
+		collectNodeReferences(actual.right, body, offset, path, result)
+	case *ternaryNode:
+		collectNodeReferences(actual.condition, body, offset, path, result)
+		collectNodeReferences(actual.whenTrue, body, offset, path, result)
+		collectNodeReferences(actual.whenFalse, body, offset, path, result)
+	}
+}
+
+func collectIndexReferences(variable *variableNode, body string, offset int, path string, result *References) {
+	for _, segment := range variable.segments {
+		if segment.index != nil {
+			collectNodeReferences(segment.index, body, offset, path, result)
+		}
+	}
+}
+
+func collectCallReferences(call *callNode, body string, offset int, path string, result *References) {
+	collectIndexReferences(call.variable, body, offset, path, result)
+	if call.arguments == "" {
+		return
+	}
+	argumentsPosition := call.position + strings.Index(body[call.position:], call.arguments)
+	collectTextReferences(call.arguments, offset+argumentsPosition, path, result)
+}
+
+// variablePath returns variable path without evaluation, i.e. items[$i].price
+func variablePath(variable *variableNode) string {
+	var result = ""
+	for i, segment := range variable.segments {
+		if segment.index == nil {
+			if i > 0 {
+				result += "."
+			}
+			result += segment.name
+			continue
+		}
+		switch index := segment.index.(type) {
+		case *literalNode:
+			result += "[" + toolbox.AsString(index.value) + "]"
+		case *variableNode:
+			result += "[$" + variablePath(index) + "]"
+		default:
+			result += "[...]"
+		}
+	}
+	return result
+}
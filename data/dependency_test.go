@@ -0,0 +1,106 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferencesOf(t *testing.T) {
+	var useCases = []struct {
+		description string
+		source      interface{}
+		expected    References
+	}{
+		{
+			description: "variables and UDF",
+			source:      "app $abc ${a.b[1]} $Fn($x)",
+			expected: References{
+				{Expression: "$abc", Name: "abc", Position: 4},
+				{Expression: "${a.b[1]}", Name: "a.b[1]", Position: 9},
+				{Expression: "$Fn($x)", Name: "Fn", IsUDF: true, Position: 19},
+				{Expression: "$x", Name: "x", Position: 23},
+			},
+		},
+		{
+			description: "dynamic index and operator expression",
+			source:      "$key2[$key3] ${(key1 + 1) * 3}",
+			expected: References{
+				{Expression: "$key2[$key3]", Name: "key2[$key3]", Position: 0},
+				{Expression: "$key3", Name: "key3", Position: 6},
+				{Expression: "$key1", Name: "key1", Position: 16},
+			},
+		},
+		{
+			description: "data structure",
+			source: map[string]interface{}{
+				"k1": []interface{}{1, "$i++"},
+				"k2": map[string]interface{}{
+					"$key": "${Md5($name)}",
+				},
+			},
+			expected: References{
+				{Expression: "$i++", Name: "i", Path: "/k1/1", Position: 0},
+				{Expression: "$key", Name: "key", Path: "/k2", Position: 0},
+				{Expression: "${Md5($name)}", Name: "Md5", IsUDF: true, Path: "/k2/$key", Position: 0},
+				{Expression: "$name", Name: "name", Path: "/k2/$key", Position: 6},
+			},
+		},
+		{
+			description: "no references",
+			source:      "a $ b",
+			expected:    References{},
+		},
+	}
+	for _, useCase := range useCases {
+		actual := ReferencesOf(useCase.source)
+		assert.EqualValues(t, useCase.expected, actual, useCase.description)
+	}
+
+	references := ReferencesOf("$Fn($x) $x ${y.z} $Fn(1)")
+	assert.EqualValues(t, []string{"x", "y.z"}, references.Variables())
+	assert.EqualValues(t, []string{"Fn"}, references.UDFs())
+	assert.EqualValues(t, "y", references[3].Root())
+}
+
+func TestMap_DryRun(t *testing.T) {
+	state := NewMap()
+	state.Put("name", "endly")
+	state.Put("items", []interface{}{1, 2})
+	state.Put("Md5", func(source interface{}, state Map) (interface{}, error) {
+		return source, nil
+	})
+	satisfied, missing := state.DryRun("$Md5($name) ${items[$i]} $Sha1($name) $abc")
+	var names = func(references References) []string {
+		var result = make([]string, 0)
+		for _, reference := range references {
+			result = append(result, reference.Expression)
+		}
+		return result
+	}
+	assert.EqualValues(t, []string{"$Md5($name)", "$name", "${items[$i]}", "$name"}, names(satisfied))
+	assert.EqualValues(t, []string{"$i", "$Sha1($name)", "$abc"}, names(missing))
+}
+
+func TestDependencyOrder(t *testing.T) {
+	{
+		order, err := DependencyOrder(map[string]interface{}{
+			"url":     "http://$host:$port/$path",
+			"port":    8080,
+			"host":    "$domain",
+			"domain":  "$env.domain",
+			"path":    "v1",
+			"request": map[string]interface{}{"url": "$url", "id": "$Md5($path)"},
+		})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"domain", "host", "path", "port", "url", "request"}, order)
+	}
+	{
+		_, err := DependencyOrder(map[string]interface{}{
+			"a": "$b",
+			"b": "${a.x}",
+			"c": "v",
+		})
+		assert.EqualError(t, err, "detected dependency cycle: a, b")
+	}
+}
@@ -0,0 +1,96 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// UnresolvedExpression represents a variable or UDF call that could not be expanded
+type UnresolvedExpression struct {
+	Expression string //expression as it appears in the input, i.e. $abc, ${abc.x} or $Md5($abc)
+	Path       string //location of expanded value within source data structure, i.e. /k1/0, empty for top level value
+	Position   int    //position of the expression within expanded text
+	Err        error  //UDF call error, nil for unresolved variable
+}
+
+// Error returns unresolved expression description
+func (e *UnresolvedExpression) Error() string {
+	location := fmt.Sprintf("at %d", e.Position)
+	if e.Path != "" {
+		location = fmt.Sprintf("at %v:%d", e.Path, e.Position)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("failed to evaluate %v %v: %v", e.Expression, location, e.Err)
+	}
+	return fmt.Sprintf("unresolved %v %v", e.Expression, location)
+}
+
+// ExpansionError represents strict expansion error listing all unresolved variables and failed UDF calls
+type ExpansionError struct {
+	Unresolved []*UnresolvedExpression
+}
+
+// Error returns all unresolved expressions description
+func (e *ExpansionError) Error() string {
+	var messages = make([]string, len(e.Unresolved))
+	for i, unresolved := range e.Unresolved {
+		messages[i] = unresolved.Error()
+	}
+	return fmt.Sprintf("failed to expand %d expression(s): %v", len(e.Unresolved), strings.Join(messages, "; "))
+}
+
+// ExpandStrict expands provided value like Expand, but returns ExpansionError if any variable is unresolved or UDF call failed
+func (s *Map) ExpandStrict(source interface{}) (interface{}, error) {
+	report := &expansionReport{}
+	result := s.expand(source, report, "")
+	return result, report.error()
+}
+
+// ExpandAsTextStrict expands text like ExpandAsText, but returns ExpansionError if any variable is unresolved or UDF call failed
+func (s *Map) ExpandAsTextStrict(text string) (string, error) {
+	report := &expansionReport{}
+	result := s.expandAsTextWith(text, true, report, "")
+	return result, report.error()
+}
+
+// expansionReport collects unresolved expressions in strict expansion mode
+type expansionReport struct {
+	unresolved []*UnresolvedExpression
+}
+
+func (r *expansionReport) add(expression, path string, position int, udfErrors map[string]error) {
+	unresolved := &UnresolvedExpression{Expression: expression, Path: path, Position: position}
+	if function := udfName(expression); function != "" {
+		unresolved.Err = udfErrors[function]
+		if unresolved.Err == nil {
+			unresolved.Err = fmt.Errorf("failed to evaluate %v", function)
+		}
+	}
+	r.unresolved = append(r.unresolved, unresolved)
+}
+
+func (r *expansionReport) error() error {
+	if len(r.unresolved) == 0 {
+		return nil
+	}
+	return &ExpansionError{Unresolved: r.unresolved}
+}
+
+// udfName returns function expression for UDF call, i.e. $Md5 for $Md5($abc) or ${Md5($abc)}, otherwise empty string
+func udfName(expression string) string {
+	index := strings.Index(expression, "(")
+	if index == -1 {
+		return ""
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(expression[:index], "$"), "{")
+	if name == "" {
+		return ""
+	}
+	for _, r := range name {
+		if !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return ""
+		}
+	}
+	return "$" + name
+}
@@ -0,0 +1,767 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/viant/toolbox"
+)
+
+// ExpressionError represents an expression compilation or evaluation error
+type ExpressionError struct {
+	Expression string
+	Position   int //zero based position in the expression
+	Message    string
+}
+
+// Error returns error message with position
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("%v at position %d in %q", e.Message, e.Position, e.Expression)
+}
+
+// Expression represents a compiled expression, once compiled it can be evaluated many times against different states
+type Expression struct {
+	Text string
+	root exprNode
+}
+
+// Evaluate evaluates expression against supplied state, variables and UDFs are resolved the same way as with Map.Expand
+func (e *Expression) Evaluate(state Map) (interface{}, error) {
+	return e.evaluate(state.newExpressionHandler(true, nil, nil, ""))
+}
+
+func (e *Expression) evaluate(handler func(expression string, isUDF bool, argument interface{}) (interface{}, bool)) (interface{}, error) {
+	return e.root.eval(&exprContext{expression: e.Text, handler: handler})
+}
+
+// isSimple returns true if expression is just a variable, call or literal without any operator
+func (e *Expression) isSimple() bool {
+	switch e.root.(type) {
+	case *variableNode, *callNode, *literalNode:
+		return true
+	}
+	return false
+}
+
+// Compile compiles an expression, i.e. (key1 + 1) * 3 > 10 && status == "ok" ? "high" : "low".
+// Supported operators by precedence: ?:, ||, &&, == !=, < <= > >=, + -, * / %, ^, unary ! -.
+// Variables can be optionally prefixed with $, they support dot and index access, i.e. $items[0].price, calls are resolved as UDF.
+func Compile(expression string) (*Expression, error) {
+	tokens, err := lexExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	parser := &exprParser{expression: expression, tokens: tokens}
+	root, err := parser.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token.kind != exprEOF {
+		return nil, parser.errorf(token, "unexpected %q", token.text)
+	}
+	return &Expression{Text: expression, root: root}, nil
+}
+
+const maxCachedExpressions = 4096
+
+var expressionCache = struct {
+	sync.RWMutex
+	expressions map[string]*Expression
+}{expressions: make(map[string]*Expression)}
+
+// compiledExpression returns cached compiled expression, nil if expression can not be compiled
+func compiledExpression(expression string) *Expression {
+	expressionCache.RLock()
+	compiled, ok := expressionCache.expressions[expression]
+	expressionCache.RUnlock()
+	if ok {
+		return compiled
+	}
+	compiled, _ = Compile(expression)
+	expressionCache.Lock()
+	if len(expressionCache.expressions) >= maxCachedExpressions {
+		expressionCache.expressions = make(map[string]*Expression)
+	}
+	expressionCache.expressions[expression] = compiled
+	expressionCache.Unlock()
+	return compiled
+}
+
+// evaluateEnclosed evaluates ${...} expression body with operators, it returns false if expression is not an operator expression or can not be evaluated
+func evaluateEnclosed(enclosed string, handler func(expression string, isUDF bool, argument interface{}) (interface{}, bool)) (interface{}, bool) {
+	body := enclosed
+	if strings.HasPrefix(body, "{") && strings.HasSuffix(body, "}") {
+		body = body[1 : len(body)-1]
+	}
+	if !strings.ContainsAny(body, "+-*/%^=!<>&|?") || strings.Contains(body, "${") {
+		return nil, false
+	}
+	compiled := compiledExpression(body)
+	if compiled == nil || compiled.isSimple() {
+		return nil, false
+	}
+	value, err := compiled.evaluate(handler)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+const (
+	exprEOF = iota
+	exprNumber
+	exprString
+	exprIdent
+	exprOperator
+)
+
+type exprToken struct {
+	kind     int
+	text     string
+	position int
+}
+
+var exprOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "=", "!", "+", "-", "*", "/", "%", "^", "?", ":", "&", "|", "(", ")", "[", "]", ".", ",", "$"}
+
+func lexExpression(expression string) ([]*exprToken, error) {
+	var result = make([]*exprToken, 0)
+	for i := 0; i < len(expression); {
+		aChar := expression[i]
+		switch {
+		case aChar == ' ' || aChar == '\t' || aChar == '\n' || aChar == '\r':
+			i++
+		case isDigit(aChar):
+			start := i
+			for i < len(expression) && isDigit(expression[i]) {
+				i++
+			}
+			if i+1 < len(expression) && expression[i] == '.' && isDigit(expression[i+1]) {
+				i++
+				for i < len(expression) && isDigit(expression[i]) {
+					i++
+				}
+			}
+			if i < len(expression) && (expression[i] == 'e' || expression[i] == 'E') {
+				j := i + 1
+				if j < len(expression) && (expression[j] == '+' || expression[j] == '-') {
+					j++
+				}
+				if j < len(expression) && isDigit(expression[j]) {
+					for i = j; i < len(expression) && isDigit(expression[i]); i++ {
+					}
+				}
+			}
+			result = append(result, &exprToken{kind: exprNumber, text: expression[start:i], position: start})
+		case aChar == '"' || aChar == '\'':
+			start := i
+			i++
+			for i < len(expression) && expression[i] != aChar {
+				if expression[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(expression) {
+				return nil, &ExpressionError{Expression: expression, Position: start, Message: "unterminated string literal"}
+			}
+			i++
+			result = append(result, &exprToken{kind: exprString, text: expression[start:i], position: start})
+		case isIdentChar(aChar):
+			start := i
+			for i < len(expression) && (isIdentChar(expression[i]) || isDigit(expression[i])) {
+				i++
+			}
+			result = append(result, &exprToken{kind: exprIdent, text: expression[start:i], position: start})
+		default:
+			matched := ""
+			for _, operator := range exprOperators {
+				if strings.HasPrefix(expression[i:], operator) {
+					matched = operator
+					break
+				}
+			}
+			if matched == "" {
+				return nil, &ExpressionError{Expression: expression, Position: i, Message: fmt.Sprintf("unexpected character %q", aChar)}
+			}
+			result = append(result, &exprToken{kind: exprOperator, text: matched, position: i})
+			i += len(matched)
+		}
+	}
+	result = append(result, &exprToken{kind: exprEOF, position: len(expression)})
+	return result, nil
+}
+
+func isDigit(aChar byte) bool {
+	return aChar >= '0' && aChar <= '9'
+}
+
+func isIdentChar(aChar byte) bool {
+	return aChar == '_' || (aChar >= 'a' && aChar <= 'z') || (aChar >= 'A' && aChar <= 'Z')
+}
+
+type exprParser struct {
+	expression string
+	tokens     []*exprToken
+	index      int
+}
+
+func (p *exprParser) peek() *exprToken {
+	return p.tokens[p.index]
+}
+
+func (p *exprParser) next() *exprToken {
+	token := p.tokens[p.index]
+	if token.kind != exprEOF {
+		p.index++
+	}
+	return token
+}
+
+func (p *exprParser) isOperator(operators ...string) bool {
+	token := p.peek()
+	if token.kind != exprOperator {
+		return false
+	}
+	for _, operator := range operators {
+		if token.text == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *exprParser) expect(operator string) (*exprToken, error) {
+	if !p.isOperator(operator) {
+		token := p.peek()
+		if token.kind == exprEOF {
+			return nil, p.errorf(token, "expected %q but reached end of expression", operator)
+		}
+		return nil, p.errorf(token, "expected %q but had %q", operator, token.text)
+	}
+	return p.next(), nil
+}
+
+func (p *exprParser) errorf(token *exprToken, format string, args ...interface{}) error {
+	return &ExpressionError{Expression: p.expression, Position: token.position, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *exprParser) parseTernary() (exprNode, error) {
+	condition, err := p.parseBinary(0)
+	if err != nil || !p.isOperator("?") {
+		return condition, err
+	}
+	token := p.next()
+	whenTrue, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = p.expect(":"); err != nil {
+		return nil, err
+	}
+	whenFalse, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return &ternaryNode{position: token.position, condition: condition, whenTrue: whenTrue, whenFalse: whenFalse}, nil
+}
+
+// binaryPrecedence lists binary operators from the lowest precedence, legacy single =, & and | are aliases of ==, && and ||
+var binaryPrecedence = [][]string{
+	{"||", "|"},
+	{"&&", "&"},
+	{"==", "!=", "="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) parseBinary(level int) (exprNode, error) {
+	if level == len(binaryPrecedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(binaryPrecedence[level]...) {
+		token := p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = newBinaryNode(token, left, right)
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.isOperator("!", "-") {
+		token := p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{position: token.position, operator: token.text, operand: operand}, nil
+	}
+	return p.parsePower()
+}
+
+func (p *exprParser) parsePower() (exprNode, error) {
+	base, err := p.parsePrimary()
+	if err != nil || !p.isOperator("^") {
+		return base, err
+	}
+	token := p.next()
+	exponent, err := p.parseUnary() //right associative
+	if err != nil {
+		return nil, err
+	}
+	return newBinaryNode(token, base, exponent), nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	token := p.next()
+	switch token.kind {
+	case exprNumber:
+		return newNumberNode(p, token)
+	case exprString:
+		text, err := unquoteLiteral(token.text)
+		if err != nil {
+			return nil, p.errorf(token, "invalid string literal %v", token.text)
+		}
+		return &literalNode{position: token.position, value: text}, nil
+	case exprIdent:
+		switch token.text {
+		case "true", "false":
+			return &literalNode{position: token.position, value: token.text == "true"}, nil
+		case "nil", "null":
+			return &literalNode{position: token.position}, nil
+		}
+		return p.parseVariable(token)
+	case exprOperator:
+		switch token.text {
+		case "(":
+			node, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.expect(")")
+			return node, err
+		case "$":
+			identToken := p.next()
+			if identToken.kind != exprIdent {
+				return nil, p.errorf(identToken, "expected variable name after $")
+			}
+			return p.parseVariable(identToken)
+		}
+	case exprEOF:
+		return nil, p.errorf(token, "unexpected end of expression")
+	}
+	return nil, p.errorf(token, "unexpected %q", token.text)
+}
+
+func (p *exprParser) parseVariable(token *exprToken) (exprNode, error) {
+	result := &variableNode{position: token.position, segments: []*pathSegment{{name: token.text}}}
+	for {
+		switch {
+		case p.isOperator("."):
+			p.next()
+			member := p.next()
+			if member.kind != exprIdent && member.kind != exprNumber {
+				return nil, p.errorf(member, "expected field name after .")
+			}
+			result.segments = append(result.segments, &pathSegment{name: member.text})
+		case p.isOperator("["):
+			p.next()
+			index, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if _, err = p.expect("]"); err != nil {
+				return nil, err
+			}
+			result.segments = append(result.segments, &pathSegment{index: index})
+		case p.isOperator("("):
+			open := p.next()
+			call := &callNode{position: token.position, variable: result}
+			depth := 1
+			for depth > 0 {
+				argToken := p.next()
+				switch {
+				case argToken.kind == exprEOF:
+					return nil, p.errorf(argToken, "expected \")\" but reached end of expression")
+				case argToken.kind == exprOperator && argToken.text == "(":
+					depth++
+				case argToken.kind == exprOperator && argToken.text == ")":
+					depth--
+					if depth == 0 {
+						call.arguments = strings.TrimSpace(p.expression[open.position+1 : argToken.position])
+					}
+				}
+			}
+			return call, nil
+		default:
+			return result, nil
+		}
+	}
+}
+
+func unquoteLiteral(literal string) (string, error) {
+	if strings.HasPrefix(literal, "'") {
+		literal = `"` + strings.Replace(strings.Replace(literal[1:len(literal)-1], `\'`, `'`, -1), `"`, `\"`, -1) + `"`
+	}
+	return strconv.Unquote(literal)
+}
+
+func newNumberNode(p *exprParser, token *exprToken) (exprNode, error) {
+	if !strings.ContainsAny(token.text, ".eE") {
+		if value, err := strconv.ParseInt(token.text, 10, 64); err == nil {
+			return &literalNode{position: token.position, value: int(value)}, nil
+		}
+	}
+	value, err := strconv.ParseFloat(token.text, 64)
+	if err != nil {
+		return nil, p.errorf(token, "invalid number %v", token.text)
+	}
+	return &literalNode{position: token.position, value: value}, nil
+}
+
+type exprContext struct {
+	expression string
+	handler    func(expression string, isUDF bool, argument interface{}) (interface{}, bool)
+}
+
+func (c *exprContext) errorf(position int, format string, args ...interface{}) error {
+	return &ExpressionError{Expression: c.expression, Position: position, Message: fmt.Sprintf(format, args...)}
+}
+
+type exprNode interface {
+	eval(context *exprContext) (interface{}, error)
+}
+
+type literalNode struct {
+	position int
+	value    interface{}
+}
+
+func (n *literalNode) eval(context *exprContext) (interface{}, error) {
+	return n.value, nil
+}
+
+type pathSegment struct {
+	name  string
+	index exprNode
+}
+
+type variableNode struct {
+	position int
+	segments []*pathSegment
+}
+
+// path returns variable path, i.e. items[0].price
+func (n *variableNode) path(context *exprContext) (string, error) {
+	var result = ""
+	for i, segment := range n.segments {
+		if segment.index == nil {
+			if i > 0 {
+				result += "."
+			}
+			result += segment.name
+			continue
+		}
+		index, err := segment.index.eval(context)
+		if err != nil {
+			return "", err
+		}
+		result += "[" + toolbox.AsString(index) + "]"
+	}
+	return result, nil
+}
+
+func (n *variableNode) eval(context *exprContext) (interface{}, error) {
+	path, err := n.path(context)
+	if err != nil {
+		return nil, err
+	}
+	variable := "$" + path
+	value, ok := context.handler(variable, false, "")
+	if text, isText := value.(string); !ok || (isText && text == variable) {
+		return nil, context.errorf(n.position, "unresolved variable %v", variable)
+	}
+	return value, nil
+}
+
+type callNode struct {
+	position  int
+	variable  *variableNode
+	arguments string //raw arguments, they are expanded by UDF handler
+}
+
+func (n *callNode) eval(context *exprContext) (interface{}, error) {
+	path, err := n.variable.path(context)
+	if err != nil {
+		return nil, err
+	}
+	function := "$" + path
+	value, ok := context.handler(function, true, n.arguments)
+	if text, isText := value.(string); !ok || (isText && strings.HasPrefix(text, function+"(")) {
+		return nil, context.errorf(n.position, "failed to evaluate %v(%v)", function, n.arguments)
+	}
+	return value, nil
+}
+
+type unaryNode struct {
+	position int
+	operator string
+	operand  exprNode
+}
+
+func (n *unaryNode) eval(context *exprContext) (interface{}, error) {
+	value, err := n.operand.eval(context)
+	if err != nil {
+		return nil, err
+	}
+	if n.operator == "!" {
+		boolValue, err := toolbox.ToBoolean(value)
+		if err != nil {
+			return nil, context.errorf(n.position, "expected bool operand but had %v", value)
+		}
+		return !boolValue, nil
+	}
+	if intValue, ok := asIntOperand(value); ok {
+		return -intValue, nil
+	}
+	floatValue, err := toolbox.ToFloat(value)
+	if err != nil {
+		return nil, context.errorf(n.position, "expected numeric operand but had %v", value)
+	}
+	return -floatValue, nil
+}
+
+type ternaryNode struct {
+	position  int
+	condition exprNode
+	whenTrue  exprNode
+	whenFalse exprNode
+}
+
+func (n *ternaryNode) eval(context *exprContext) (interface{}, error) {
+	condition, err := n.condition.eval(context)
+	if err != nil {
+		return nil, err
+	}
+	boolValue, err := toolbox.ToBoolean(condition)
+	if err != nil {
+		return nil, context.errorf(n.position, "expected bool condition but had %v", condition)
+	}
+	if boolValue {
+		return n.whenTrue.eval(context)
+	}
+	return n.whenFalse.eval(context)
+}
+
+type binaryNode struct {
+	position int
+	operator string
+	left     exprNode
+	right    exprNode
+}
+
+func newBinaryNode(token *exprToken, left, right exprNode) exprNode {
+	operator := token.text
+	switch operator { //legacy aliases
+	case "=":
+		operator = "=="
+	case "&":
+		operator = "&&"
+	case "|":
+		operator = "||"
+	}
+	return &binaryNode{position: token.position, operator: operator, left: left, right: right}
+}
+
+func (n *binaryNode) eval(context *exprContext) (interface{}, error) {
+	left, err := n.left.eval(context)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "&&", "||":
+		leftBool, err := toolbox.ToBoolean(left)
+		if err != nil {
+			return nil, context.errorf(n.position, "expected bool operand but had %v", left)
+		}
+		if (n.operator == "&&" && !leftBool) || (n.operator == "||" && leftBool) {
+			return leftBool, nil
+		}
+		right, err := n.right.eval(context)
+		if err != nil {
+			return nil, err
+		}
+		rightBool, err := toolbox.ToBoolean(right)
+		if err != nil {
+			return nil, context.errorf(n.position, "expected bool operand but had %v", right)
+		}
+		return rightBool, nil
+	}
+	right, err := n.right.eval(context)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "==", "!=":
+		equal := isEqual(left, right)
+		return equal == (n.operator == "=="), nil
+	case "<", "<=", ">", ">=":
+		return compare(n.operator, left, right), nil
+	}
+	result, err := arithmetic(n.operator, left, right)
+	if err != nil {
+		return nil, context.errorf(n.position, "%v", err)
+	}
+	return result, nil
+}
+
+// asIntOperand returns int for int values or int literals, floats are not converted to avoid fraction loss
+func asIntOperand(value interface{}) (int, bool) {
+	switch actual := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, *int, *int64:
+		result, err := toolbox.ToInt(actual)
+		return result, err == nil
+	case string:
+		result, err := strconv.Atoi(strings.TrimSpace(actual))
+		return result, err == nil
+	}
+	return 0, false
+}
+
+func asFloatOperand(value interface{}) (float64, bool) {
+	switch actual := value.(type) {
+	case bool, nil:
+		return 0, false
+	case string:
+		result, err := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+		return result, err == nil
+	}
+	result, err := toolbox.ToFloat(value)
+	return result, err == nil
+}
+
+func isEqual(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	if leftInt, ok := asIntOperand(left); ok {
+		if rightInt, ok := asIntOperand(right); ok {
+			return leftInt == rightInt
+		}
+	}
+	if leftFloat, ok := asFloatOperand(left); ok {
+		if rightFloat, ok := asFloatOperand(right); ok {
+			return leftFloat == rightFloat
+		}
+	}
+	if leftBool, ok := left.(bool); ok {
+		rightBool, err := toolbox.ToBoolean(right)
+		return err == nil && leftBool == rightBool
+	}
+	if rightBool, ok := right.(bool); ok {
+		leftBool, err := toolbox.ToBoolean(left)
+		return err == nil && leftBool == rightBool
+	}
+	return toolbox.AsString(left) == toolbox.AsString(right)
+}
+
+func compare(operator string, left, right interface{}) bool {
+	var comparison int
+	leftInt, leftIsInt := asIntOperand(left)
+	rightInt, rightIsInt := asIntOperand(right)
+	leftFloat, leftIsFloat := asFloatOperand(left)
+	rightFloat, rightIsFloat := asFloatOperand(right)
+	switch {
+	case leftIsInt && rightIsInt:
+		comparison = compareOrdered(leftInt < rightInt, leftInt > rightInt)
+	case leftIsFloat && rightIsFloat:
+		comparison = compareOrdered(leftFloat < rightFloat, leftFloat > rightFloat)
+	default:
+		comparison = strings.Compare(toolbox.AsString(left), toolbox.AsString(right))
+	}
+	switch operator {
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	}
+	return comparison >= 0
+}
+
+func compareOrdered(less, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
+
+// arithmetic evaluates arithmetic operator, int operands use int arithmetic except / and ^, + concatenates non numeric strings
+func arithmetic(operator string, left, right interface{}) (interface{}, error) {
+	leftInt, leftIsInt := asIntOperand(left)
+	rightInt, rightIsInt := asIntOperand(right)
+	if leftIsInt && rightIsInt && operator != "/" && operator != "^" {
+		switch operator {
+		case "+":
+			return leftInt + rightInt, nil
+		case "-":
+			return leftInt - rightInt, nil
+		case "*":
+			return leftInt * rightInt, nil
+		case "%":
+			if rightInt == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return leftInt % rightInt, nil
+		}
+	}
+	leftFloat, leftIsFloat := asFloatOperand(left)
+	rightFloat, rightIsFloat := asFloatOperand(right)
+	if !leftIsFloat || !rightIsFloat {
+		_, leftIsText := left.(string)
+		_, rightIsText := right.(string)
+		if operator == "+" && (leftIsText || rightIsText) {
+			return toolbox.AsString(left) + toolbox.AsString(right), nil
+		}
+		return nil, fmt.Errorf("unsupported operands %v %v %v", left, operator, right)
+	}
+	var result float64
+	switch operator {
+	case "+":
+		result = leftFloat + rightFloat
+	case "-":
+		result = leftFloat - rightFloat
+	case "*":
+		result = leftFloat * rightFloat
+	case "/":
+		if rightFloat == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		result = leftFloat / rightFloat
+	case "%":
+		if int(rightFloat) == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		result = float64(int(leftFloat) % int(rightFloat))
+	case "^":
+		result = math.Pow(leftFloat, rightFloat)
+	}
+	if intResult := int(result); float64(intResult) == result && math.Abs(result) < math.MaxInt64 {
+		return intResult, nil
+	}
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	var useCases = []struct {
+		description string
+		expression  string
+		state       Map
+		expected    interface{}
+		hasError    bool
+	}{
+		{description: "precedence", expression: "1 + 2 * 3", expected: 7},
+		{description: "grouping", expression: "(1 + 2) * 3", expected: 9},
+		{description: "left associativity", expression: "10 - 4 - 3", expected: 3},
+		{description: "power right associativity", expression: "2 ^ 3 ^ 2", expected: 512},
+		{description: "float division", expression: "(i + j) / 2", state: Map{"i": 1, "j": 2}, expected: 1.5},
+		{description: "int division", expression: "10 / 2", expected: 5},
+		{description: "unary minus", expression: "-i * 2", state: Map{"i": 3}, expected: -6},
+		{description: "modulo", expression: "i % 4", state: Map{"i": "10"}, expected: 2},
+		{description: "big int", expression: "86935317801598977 + 1", expected: 86935317801598978},
+		{description: "string literals", expression: `"a" + 'b' + "\"c\""`, expected: `ab"c"`},
+		{description: "comparison", expression: "i * 2 >= 6 && i < 4", state: Map{"i": 3}, expected: true},
+		{description: "equality", expression: `status == "ok" || status != 'failed'`, state: Map{"status": "running"}, expected: true},
+		{description: "numeric equality", expression: `count == 3`, state: Map{"count": "3"}, expected: true},
+		{description: "not", expression: `!(a && b)`, state: Map{"a": true, "b": false}, expected: true},
+		{description: "short circuit", expression: `a || missing`, state: Map{"a": true}, expected: true},
+		{description: "ternary", expression: `score > 10 ? "high" : score > 5 ? "mid" : "low"`, state: Map{"score": 7}, expected: "mid"},
+		{description: "ternary lazy branch", expression: `ok ? 1 : missing`, state: Map{"ok": true}, expected: 1},
+		{description: "nested path", expression: `$order.items[idx].price * 2`, state: Map{"idx": 1, "order": map[string]interface{}{"items": []interface{}{map[string]interface{}{"price": 1}, map[string]interface{}{"price": 2.5}}}}, expected: 5},
+		{description: "legacy aliases", expression: `a = 1 & b | false`, state: Map{"a": 1, "b": true}, expected: true},
+		{description: "udf", expression: `IndexOf($collection, xtz) + 1`, state: Map{"IndexOf": IndexOf, "collection": []interface{}{"abc", "xtz"}}, expected: 2},
+		{description: "nil", expression: `value == nil`, state: Map{"value": nil}, expected: true},
+		{description: "unresolved variable", expression: `missing + 1`, hasError: true},
+		{description: "division by zero", expression: `1 / 0`, hasError: true},
+		{description: "unsupported operands", expression: `true * 2`, hasError: true},
+	}
+	for _, useCase := range useCases {
+		compiled, err := Compile(useCase.expression)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		state := useCase.state
+		if state == nil {
+			state = NewMap()
+		}
+		actual, err := compiled.Evaluate(state)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			assert.EqualValues(t, useCase.expected, actual, useCase.description)
+		}
+	}
+}
+
+func TestCompile_Error(t *testing.T) {
+	var useCases = []struct {
+		expression string
+		position   int
+	}{
+		{expression: "1 +", position: 3},
+		{expression: "(1 + 2", position: 6},
+		{expression: "a ? b", position: 5},
+		{expression: `"abc`, position: 0},
+		{expression: "a # b", position: 2},
+		{expression: "a b", position: 2},
+	}
+	for _, useCase := range useCases {
+		_, err := Compile(useCase.expression)
+		if !assert.NotNil(t, err, useCase.expression) {
+			continue
+		}
+		expressionError, ok := err.(*ExpressionError)
+		if assert.True(t, ok, useCase.expression) {
+			assert.Equal(t, useCase.position, expressionError.Position, useCase.expression)
+		}
+	}
+	state := Map{}
+	_, err := state.Evaluate("x + 1")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, 0, err.(*ExpressionError).Position)
+	}
+}
+
+func TestExpression_EvaluateManyStates(t *testing.T) {
+	compiled, err := Compile(`(key1 + 1) * 3 > 10 ? "high" : "low"`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for i, expected := range []string{"low", "low", "low", "high", "high"} {
+		actual, err := compiled.Evaluate(Map{"key1": i})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual, i)
+	}
+}
+
+func TestParse_CompiledExpression(t *testing.T) {
+	state := Map{"i": 3, "name": "abc", "items": []interface{}{1, 2}}
+	assert.Equal(t, "high", state.Expand(`${i > 2 ? "high" : "low"}`))
+	assert.Equal(t, "status: abc!", state.Expand(`status: ${name + "!"}`))
+	assert.Equal(t, true, state.Expand(`${i >= 3 && name == "abc"}`))
+	assert.Equal(t, 9, state.Expand(`${(i + items[1]) * 3 - 6}`))
+	assert.Equal(t, "${missing + 1}", state.Expand(`${missing + 1}`))
+}
+
+func BenchmarkExpression_Evaluate(b *testing.B) {
+	compiled, _ := Compile(`(key1 + 1) * 3 > 10 && status == "ok" ? "high" : "low"`)
+	state := Map{"key1": 4, "status": "ok"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = compiled.Evaluate(state)
+	}
+}
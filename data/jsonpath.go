@@ -0,0 +1,426 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// JSONPath represents compiled JSONPath query, the following subset is supported:
+// $ root, .name or ['name'] child, * wildcard, .. recursive descent, [0] or [-1] index, ['a','b'] or [0,2] union,
+// [start:end:step] slice and [?(@.price > 10)] filter, where filter is data expression with @ current node and $ root
+type JSONPath struct {
+	Text  string
+	steps []*pathStep
+}
+
+type pathStep struct {
+	recursive bool
+	wildcard  bool
+	names     []string
+	indices   []int
+	slice     []*int //start, end, step
+	filter    *Expression
+}
+
+// Select returns nodes matched by the path in document order, map keys are visited in sorted order
+func (p *JSONPath) Select(document interface{}) []interface{} {
+	var nodes = []interface{}{document}
+	for _, step := range p.steps {
+		var matched = make([]interface{}, 0)
+		for _, node := range nodes {
+			if step.recursive {
+				for _, descendant := range descendants(node) {
+					matched = step.apply(descendant, document, matched)
+				}
+				continue
+			}
+			matched = step.apply(node, document, matched)
+		}
+		nodes = matched
+	}
+	return nodes
+}
+
+func (s *pathStep) apply(node, document interface{}, result []interface{}) []interface{} {
+	switch {
+	case s.wildcard:
+		return append(result, children(node)...)
+	case len(s.names) > 0:
+		if !toolbox.IsMap(node) {
+			return result
+		}
+		aMap := toolbox.AsMap(node)
+		for _, name := range s.names {
+			if value, ok := aMap[name]; ok {
+				result = append(result, value)
+			}
+		}
+	case len(s.indices) > 0:
+		if !toolbox.IsSlice(node) || isBytes(node) {
+			return result
+		}
+		aSlice := toolbox.AsSlice(node)
+		for _, index := range s.indices {
+			if index < 0 {
+				index += len(aSlice)
+			}
+			if index >= 0 && index < len(aSlice) {
+				result = append(result, aSlice[index])
+			}
+		}
+	case s.slice != nil:
+		if !toolbox.IsSlice(node) || isBytes(node) {
+			return result
+		}
+		aSlice := toolbox.AsSlice(node)
+		for _, index := range sliceIndices(len(aSlice), s.slice) {
+			result = append(result, aSlice[index])
+		}
+	case s.filter != nil:
+		for _, child := range children(node) {
+			if s.matches(child, document) {
+				result = append(result, child)
+			}
+		}
+	}
+	return result
+}
+
+// matches returns true if filter evaluates to true or to a non nil value for the node, evaluation errors are treated as no match
+func (s *pathStep) matches(node, document interface{}) bool {
+	state := NewMap()
+	state.Put("item", node)
+	state.Put("root", document)
+	value, err := s.filter.Evaluate(state)
+	if err != nil || value == nil {
+		return false
+	}
+	if boolValue, ok := value.(bool); ok {
+		return boolValue
+	}
+	return true
+}
+
+func isBytes(node interface{}) bool {
+	_, ok := node.([]byte)
+	return ok
+}
+
+// children returns slice items or map values sorted by key
+func children(node interface{}) []interface{} {
+	if node == nil || isBytes(node) {
+		return nil
+	}
+	if toolbox.IsSlice(node) {
+		return toolbox.AsSlice(node)
+	}
+	if toolbox.IsMap(node) {
+		aMap := toolbox.AsMap(node)
+		var keys = make([]string, 0, len(aMap))
+		for key := range aMap {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var result = make([]interface{}, len(keys))
+		for i, key := range keys {
+			result[i] = aMap[key]
+		}
+		return result
+	}
+	return nil
+}
+
+// descendants returns node with all its descendants in document order
+func descendants(node interface{}) []interface{} {
+	var result = []interface{}{node}
+	for _, child := range children(node) {
+		result = append(result, descendants(child)...)
+	}
+	return result
+}
+
+// sliceIndices returns indices selected by [start:end:step] with python slice semantic
+func sliceIndices(length int, slice []*int) []int {
+	step := 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+	var result = make([]int, 0)
+	if step == 0 {
+		return result
+	}
+	normalize := func(index *int, defaultValue, lower, upper int) int {
+		if index == nil {
+			return defaultValue
+		}
+		value := *index
+		if value < 0 {
+			value += length
+		}
+		if value < lower {
+			return lower
+		}
+		if value > upper {
+			return upper
+		}
+		return value
+	}
+	if step > 0 {
+		start, end := normalize(slice[0], 0, 0, length), normalize(slice[1], length, 0, length)
+		for i := start; i < end; i += step {
+			result = append(result, i)
+		}
+		return result
+	}
+	start, end := normalize(slice[0], length-1, -1, length-1), normalize(slice[1], -1, -1, length-1)
+	for i := start; i > end; i += step {
+		result = append(result, i)
+	}
+	return result
+}
+
+// CompileJSONPath compiles JSONPath query, $ root prefix is optional
+func CompileJSONPath(path string) (*JSONPath, error) {
+	parser := &jsonPathParser{path: path}
+	steps, err := parser.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &JSONPath{Text: path, steps: steps}, nil
+}
+
+// Query returns nodes of the map matched by JSONPath query, i.e. $.items[?(@.price > 10)].id
+func (s *Map) Query(path string) ([]interface{}, error) {
+	jsonPath, err := CompileJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return jsonPath.Select(map[string]interface{}(*s)), nil
+}
+
+type jsonPathParser struct {
+	path  string
+	index int
+}
+
+func (p *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return &ExpressionError{Expression: p.path, Position: p.index, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *jsonPathParser) parse() ([]*pathStep, error) {
+	var result = make([]*pathStep, 0)
+	p.path = strings.TrimSpace(p.path)
+	if strings.HasPrefix(p.path, "$") {
+		p.index++
+	} else if p.path != "" && p.path[0] != '.' && p.path[0] != '[' { //relative path, i.e. items[0].id
+		step, err := p.parseName(false)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, step)
+	}
+	for p.index < len(p.path) {
+		var step *pathStep
+		var err error
+		switch p.path[p.index] {
+		case '.':
+			p.index++
+			recursive := p.index < len(p.path) && p.path[p.index] == '.'
+			if recursive {
+				p.index++
+			}
+			if recursive && p.index < len(p.path) && p.path[p.index] == '[' {
+				step, err = p.parseBracket(true)
+			} else {
+				step, err = p.parseName(recursive)
+			}
+		case '[':
+			step, err = p.parseBracket(false)
+		default:
+			return nil, p.errorf("unexpected %q", p.path[p.index])
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, step)
+	}
+	return result, nil
+}
+
+func (p *jsonPathParser) parseName(recursive bool) (*pathStep, error) {
+	start := p.index
+	for p.index < len(p.path) && p.path[p.index] != '.' && p.path[p.index] != '[' {
+		p.index++
+	}
+	name := p.path[start:p.index]
+	if name == "" {
+		return nil, p.errorf("expected name")
+	}
+	if name == "*" {
+		return &pathStep{recursive: recursive, wildcard: true}, nil
+	}
+	return &pathStep{recursive: recursive, names: []string{name}}, nil
+}
+
+func (p *jsonPathParser) parseBracket(recursive bool) (*pathStep, error) {
+	start := p.index
+	end, err := p.closingBracket()
+	if err != nil {
+		return nil, err
+	}
+	p.index = end + 1
+	body := strings.TrimSpace(p.path[start+1 : end])
+	step := &pathStep{recursive: recursive}
+	switch {
+	case body == "*":
+		step.wildcard = true
+	case strings.HasPrefix(body, "?"):
+		filter := strings.TrimSpace(body[1:])
+		if strings.HasPrefix(filter, "(") && strings.HasSuffix(filter, ")") {
+			filter = filter[1 : len(filter)-1]
+		}
+		expression, err := Compile(filterExpression(filter))
+		if err != nil {
+			p.index = start
+			return nil, p.errorf("invalid filter %v: %v", filter, err)
+		}
+		step.filter = expression
+	case body == "":
+		p.index = start
+		return nil, p.errorf("empty brackets")
+	default:
+		items := splitUnion(body)
+		if len(items) == 1 && strings.Contains(body, ":") && !isQuoted(body) {
+			return step, p.parseSlice(step, body, start)
+		}
+		for _, item := range items {
+			if isQuoted(item) {
+				step.names = append(step.names, item[1:len(item)-1])
+				continue
+			}
+			index, err := strconv.Atoi(item)
+			if err != nil {
+				p.index = start
+				return nil, p.errorf("invalid index %v", item)
+			}
+			step.indices = append(step.indices, index)
+		}
+		if len(step.names) > 0 && len(step.indices) > 0 {
+			p.index = start
+			return nil, p.errorf("union can not mix names and indices")
+		}
+	}
+	return step, nil
+}
+
+func (p *jsonPathParser) parseSlice(step *pathStep, body string, start int) error {
+	parts := strings.Split(body, ":")
+	if len(parts) > 3 {
+		p.index = start
+		return p.errorf("invalid slice %v", body)
+	}
+	step.slice = make([]*int, 3)
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			p.index = start
+			return p.errorf("invalid slice %v", body)
+		}
+		step.slice[i] = &value
+	}
+	return nil
+}
+
+// closingBracket returns position of bracket closing the one at current index, quotes and nested brackets are skipped
+func (p *jsonPathParser) closingBracket() (int, error) {
+	depth := 0
+	var quote byte
+	for i := p.index; i < len(p.path); i++ {
+		aChar := p.path[i]
+		switch {
+		case quote != 0:
+			if aChar == '\\' {
+				i++
+			} else if aChar == quote {
+				quote = 0
+			}
+		case aChar == '\'' || aChar == '"':
+			quote = aChar
+		case aChar == '[' || aChar == '(':
+			depth++
+		case aChar == ']' || aChar == ')':
+			depth--
+			if depth == 0 {
+				if aChar != ']' {
+					return 0, p.errorf("unbalanced brackets")
+				}
+				return i, nil
+			}
+		}
+	}
+	return 0, p.errorf("expected \"]\" but reached end of path")
+}
+
+// splitUnion splits bracket body by coma outside of quotes
+func splitUnion(body string) []string {
+	var result = make([]string, 0)
+	var quote byte
+	start := 0
+	for i := 0; i < len(body); i++ {
+		switch aChar := body[i]; {
+		case quote != 0:
+			if aChar == '\\' {
+				i++
+			} else if aChar == quote {
+				quote = 0
+			}
+		case aChar == '\'' || aChar == '"':
+			quote = aChar
+		case aChar == ',':
+			result = append(result, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	return append(result, strings.TrimSpace(body[start:]))
+}
+
+func isQuoted(text string) bool {
+	return len(text) >= 2 && (text[0] == '\'' || text[0] == '"') && text[len(text)-1] == text[0]
+}
+
+// filterExpression replaces @ current node with item and $ root with root variable outside of string literals
+func filterExpression(filter string) string {
+	var result = strings.Builder{}
+	var quote byte
+	for i := 0; i < len(filter); i++ {
+		aChar := filter[i]
+		switch {
+		case quote != 0:
+			if aChar == '\\' && i+1 < len(filter) {
+				result.WriteByte(aChar)
+				i++
+				aChar = filter[i]
+			} else if aChar == quote {
+				quote = 0
+			}
+		case aChar == '\'' || aChar == '"':
+			quote = aChar
+		case aChar == '@':
+			result.WriteString("item")
+			continue
+		case aChar == '$' && (i+1 == len(filter) || filter[i+1] == '.' || filter[i+1] == '['):
+			result.WriteString("root")
+			continue
+		}
+		result.WriteByte(aChar)
+	}
+	return result.String()
+}
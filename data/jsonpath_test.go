@@ -0,0 +1,82 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+const storeJSON = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 19.95}
+	},
+	"limit": 10
+}`
+
+func TestMap_Query(t *testing.T) {
+	document, err := toolbox.JSONToMap(storeJSON)
+	if !assert.Nil(t, err) {
+		return
+	}
+	state := Map(document)
+
+	var useCases = []struct {
+		description string
+		path        string
+		expected    []interface{}
+	}{
+		{description: "child", path: "$.store.bicycle.color", expected: []interface{}{"red"}},
+		{description: "relative path", path: "store.book[0].author", expected: []interface{}{"Nigel Rees"}},
+		{description: "bracket names", path: "$['store']['bicycle']['color']", expected: []interface{}{"red"}},
+		{description: "wildcard", path: "$.store.book[*].author", expected: []interface{}{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{description: "recursive descent", path: "$..author", expected: []interface{}{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{description: "recursive descent with wildcard", path: "$.store..price", expected: []interface{}{19.95, 8.95, 12.99, 8.99, 22.99}},
+		{description: "negative index", path: "$..book[-1].title", expected: []interface{}{"The Lord of the Rings"}},
+		{description: "index union", path: "$..book[0,2].title", expected: []interface{}{"Sayings of the Century", "Moby Dick"}},
+		{description: "name union", path: "$.store.book[0]['title','price']", expected: []interface{}{"Sayings of the Century", 8.95}},
+		{description: "slice", path: "$.store.book[1:3].price", expected: []interface{}{12.99, 8.99}},
+		{description: "slice with step", path: "$.store.book[::2].price", expected: []interface{}{8.95, 8.99}},
+		{description: "negative slice step", path: "$.store.book[::-1].price", expected: []interface{}{22.99, 8.99, 12.99, 8.95}},
+		{description: "open slice", path: "$.store.book[-2:].price", expected: []interface{}{8.99, 22.99}},
+		{description: "filter", path: "$.store.book[?(@.price > 10)].title", expected: []interface{}{"Sword of Honour", "The Lord of the Rings"}},
+		{description: "filter with logical operator", path: "$..book[?(@.category == 'fiction' && @.price < 10)].author", expected: []interface{}{"Herman Melville"}},
+		{description: "filter existence", path: "$..book[?(@.isbn)].isbn", expected: []interface{}{"0-553-21311-3", "0-395-19395-8"}},
+		{description: "filter with root", path: "$..book[?(@.price < $.limit)].price", expected: []interface{}{8.95, 8.99}},
+		{description: "no match", path: "$.store.car.color", expected: []interface{}{}},
+	}
+	for _, useCase := range useCases {
+		actual, err := state.Query(useCase.path)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expected, actual, useCase.description)
+	}
+}
+
+func TestCompileJSONPath_Error(t *testing.T) {
+	var useCases = []struct {
+		path     string
+		position int
+	}{
+		{path: "$.store[", position: 7},
+		{path: "$.store[]", position: 7},
+		{path: "$.store[?(@.price >)]", position: 7},
+		{path: "$.book[1:x]", position: 6},
+		{path: "$.book['a',1]", position: 6},
+		{path: "$.store..", position: 9},
+	}
+	for _, useCase := range useCases {
+		_, err := CompileJSONPath(useCase.path)
+		expressionError, ok := err.(*ExpressionError)
+		if assert.True(t, ok, useCase.path) {
+			assert.EqualValues(t, useCase.position, expressionError.Position, useCase.path)
+		}
+	}
+}
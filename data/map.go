@@ -2,6 +2,7 @@ package data
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/viant/toolbox"
 	"log"
 	"strings"
@@ -466,31 +467,36 @@ func (s *Map) ExpandWithoutUDF(text string) string {
 
 // Expand expands provided value of any type with dollar sign expression/
 func (s *Map) Expand(source interface{}) interface{} {
+	return s.expand(source, nil, "")
+}
+
+// expand expands source, unresolved expressions are collected into report if it is not nil, path is a source location used by the report
+func (s *Map) expand(source interface{}, report *expansionReport, path string) interface{} {
 	switch value := source.(type) {
 	case bool, []byte, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, float32, float64, time.Time:
 		return source
 	case *[]byte:
-		return s.expandExpressions(string(*value), true)
+		return s.expandExpressionsWith(string(*value), true, report, path)
 	case *string:
 		if value == nil {
 			return ""
 		}
-		return s.expandExpressions(*value, true)
+		return s.expandExpressionsWith(*value, true, report, path)
 	case string:
-		return s.expandExpressions(value, true)
+		return s.expandExpressionsWith(value, true, report, path)
 	case map[string]interface{}:
 		if hasGenericKeys(value) {
 			result := make(map[interface{}]interface{})
 			for k, v := range value {
-				var key = s.Expand(k)
-				result[key] = s.Expand(v)
+				var key = s.expand(k, report, path)
+				result[key] = s.expand(v, report, path+"/"+k)
 			}
 			return result
 		}
 		resultMap := make(map[string]interface{})
 		for k, v := range value {
-			var key = s.ExpandAsText(k)
-			var expanded = s.Expand(v)
+			var key = s.expandAsTextWith(k, true, report, path)
+			var expanded = s.expand(v, report, path+"/"+k)
 
 			if key == "..." {
 				if expanded != nil && toolbox.IsMap(expanded) {
@@ -506,8 +512,8 @@ func (s *Map) Expand(source interface{}) interface{} {
 	case map[interface{}]interface{}:
 		var resultMap = make(map[interface{}]interface{})
 		for k, v := range value {
-			var key = s.Expand(k)
-			var expanded = s.Expand(v)
+			var key = s.expand(k, report, path)
+			var expanded = s.expand(v, report, path+"/"+toolbox.AsString(k))
 			if key == "..." {
 				if expanded != nil && toolbox.IsMap(expanded) {
 					for key, value := range toolbox.AsMap(expanded) {
@@ -522,7 +528,7 @@ func (s *Map) Expand(source interface{}) interface{} {
 	case []interface{}:
 		var resultSlice = make([]interface{}, len(value))
 		for i, value := range value {
-			resultSlice[i] = s.Expand(value)
+			resultSlice[i] = s.expand(value, report, path+"/"+toolbox.AsString(i))
 		}
 		return resultSlice
 	default:
@@ -534,20 +540,20 @@ func (s *Map) Expand(source interface{}) interface{} {
 		if toolbox.IsMap(source) {
 			switch aMap := value.(type) {
 			case map[string]interface{}:
-				return s.Expand(aMap)
+				return s.expand(aMap, report, path)
 			case map[interface{}]interface{}:
-				return s.Expand(aMap)
+				return s.expand(aMap, report, path)
 			default:
-				return s.Expand(toolbox.AsMap(value))
+				return s.expand(toolbox.AsMap(value), report, path)
 			}
 
 		} else if toolbox.IsSlice(source) {
-			return s.Expand(toolbox.AsSlice(value))
+			return s.expand(toolbox.AsSlice(value), report, path)
 		} else if toolbox.IsStruct(value) {
 			aMap := toolbox.AsMap(value)
-			return s.Expand(aMap)
+			return s.expand(aMap, report, path)
 		} else if value != nil {
-			return s.Expand(toolbox.AsString(value))
+			return s.expand(toolbox.AsString(value), report, path)
 		}
 	}
 	return source
@@ -559,7 +565,11 @@ func (s *Map) ExpandAsText(text string) string {
 }
 
 func (s *Map) expandAsText(text string, unquoteExpression bool) string {
-	result := s.expandExpressions(text, unquoteExpression)
+	return s.expandAsTextWith(text, unquoteExpression, nil, "")
+}
+
+func (s *Map) expandAsTextWith(text string, unquoteExpression bool, report *expansionReport, path string) string {
+	result := s.expandExpressionsWith(text, unquoteExpression, report, path)
 	if toolbox.IsSlice(result) || toolbox.IsMap(result) {
 		buf := new(bytes.Buffer)
 		err := toolbox.NewJSONEncoderFactory().Create(buf).Encode(result)
@@ -573,25 +583,27 @@ func (s *Map) expandAsText(text string, unquoteExpression bool) string {
 	return toolbox.AsString(result)
 }
 
-func (s *Map) evaluateUDF(candidate interface{}, argument interface{}) (interface{}, bool) {
-	var canExpandAll = true
-
+func (s *Map) evaluateUDF(candidate interface{}, argument interface{}) (interface{}, error) {
+	var unresolved = make([]string, 0)
 	if toolbox.IsString(argument) {
 		var expandable = strings.TrimSpace(toolbox.AsString(argument))
+		udfs := s.GetMap(UDFKey)
 		Parse(expandable, func(expression string, udf bool, argument interface{}) (interface{}, bool) {
+			if _, has := udfs.GetValue(string(expression[1:])); udf && has {
+				return nil, false
+			}
 			if _, has := s.GetValue(string(expression[1:])); !has {
-				canExpandAll = false
+				unresolved = append(unresolved, expression)
 			}
 			return nil, false
 		})
 	}
-
-	if !canExpandAll {
-		return nil, false
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("unresolved argument %v", strings.Join(unresolved, ", "))
 	}
 	udf, ok := candidate.(func(interface{}, Map) (interface{}, error))
 	if !ok {
-		return nil, false
+		return nil, fmt.Errorf("expected %T, but had %T", udf, candidate)
 	}
 
 	expandedArgument := s.expandArgumentsExpressions(argument)
@@ -600,7 +612,7 @@ func (s *Map) evaluateUDF(candidate interface{}, argument interface{}) (interfac
 		if toolbox.IsStructuredJSON(expandedText) {
 			evaluated, err := toolbox.JSONToInterface(expandedText)
 			if err != nil {
-				return nil, false
+				return nil, err
 			}
 			expandedArgument = evaluated
 		}
@@ -608,10 +620,10 @@ func (s *Map) evaluateUDF(candidate interface{}, argument interface{}) (interfac
 
 	evaluated, err := udf(expandedArgument, *s)
 	if err == nil {
-		return evaluated, true
+		return evaluated, nil
 	}
 	log.Printf("failed to evaluate %v, %v", candidate, err)
-	return nil, false
+	return nil, err
 }
 
 func (s *Map) hasCycle(source interface{}, ownerVariable string) bool {
@@ -651,9 +663,35 @@ func (s *Map) hasCycle(source interface{}, ownerVariable string) bool {
 // expandExpressions will check provided text with any expression starting with dollar sign ($) to substitute it with key in the map if it is present.
 // The result can be an expanded text or type of key referenced by the expression.
 func (s *Map) expandExpressions(text string, unquoteExpression bool) interface{} {
+	return s.expandExpressionsWith(text, unquoteExpression, nil, "")
+}
+
+func (s *Map) expandExpressionsWith(text string, unquoteExpression bool, report *expansionReport, path string) interface{} {
 	if strings.Index(text, "$") == -1 {
 		return text
 	}
+	if report == nil {
+		return Parse(text, s.newExpressionHandler(unquoteExpression, nil, nil, path))
+	}
+	udfErrors := make(map[string]error)
+	return parse(text, s.newExpressionHandler(unquoteExpression, udfErrors, report, path), func(expression string, position int) {
+		report.add(expression, path, position, udfErrors)
+	})
+}
+
+// Evaluate compiles (or takes cached compiled) expression and evaluates it against the map, i.e. ${(key1 + 1) * 3} expression body: (key1 + 1) * 3
+func (s *Map) Evaluate(expression string) (interface{}, error) {
+	compiled := compiledExpression(expression)
+	if compiled == nil {
+		_, err := Compile(expression)
+		return nil, err
+	}
+	return compiled.Evaluate(*s)
+}
+
+// newExpressionHandler returns a handler resolving variables and UDFs for Parse, UDF errors are collected by function expression into udfErrors if it is not nil,
+// unresolved expressions nested in referenced map or slice values are collected into report if it is not nil
+func (s *Map) newExpressionHandler(unquoteExpression bool, udfErrors map[string]error, report *expansionReport, path string) func(expression string, isUDF bool, argument interface{}) (interface{}, bool) {
 	udfs := s.GetMap(UDFKey)
 	hasUdfs := len(udfs) > 0
 	var expandVariable = func(expression string, isUDF bool, argument interface{}) (value interface{}, hasExpValue bool) {
@@ -672,12 +710,16 @@ func (s *Map) expandExpressions(text string, unquoteExpression bool) interface{}
 				return expression, true
 			}
 			if isUDF {
-				if evaluated, ok := s.evaluateUDF(value, argument); ok {
+				evaluated, err := s.evaluateUDF(value, argument)
+				if err == nil {
 					return evaluated, true
 				}
+				if udfErrors != nil {
+					udfErrors[expression] = err
+				}
 			} else {
 				if value != nil && (toolbox.IsMap(value) || toolbox.IsSlice(value)) {
-					return s.Expand(value), true
+					return s.expand(value, report, path), true
 				}
 				if text, ok := value.(string); ok {
 					return text, true
@@ -690,6 +732,9 @@ func (s *Map) expandExpressions(text string, unquoteExpression bool) interface{}
 		}
 
 		if isUDF {
+			if !hasExpValue && udfErrors != nil {
+				udfErrors[expression] = fmt.Errorf("undefined function %v", aKey)
+			}
 			expandedArgument := s.expandArgumentsExpressions(argument)
 			_, isByteArray := expandedArgument.([]byte)
 
@@ -707,8 +752,7 @@ func (s *Map) expandExpressions(text string, unquoteExpression bool) interface{}
 		}
 		return expression, true
 	}
-
-	return Parse(text, expandVariable)
+	return expandVariable
 }
 
 // expandExpressions will check provided text with any expression starting with dollar sign ($) to substitute it with key in the map if it is present.
@@ -742,7 +786,7 @@ func (s *Map) expandArgumentsExpressions(argument interface{}) interface{} {
 				return expression, true
 			}
 			if isUDF {
-				if evaluated, ok := s.evaluateUDF(value, argument); ok {
+				if evaluated, err := s.evaluateUDF(value, argument); err == nil {
 					return evaluated, true
 				}
 			} else {
@@ -780,6 +824,7 @@ func (s *Map) expandArgumentsExpressions(argument interface{}) interface{} {
 		switch match.Token {
 		case doubleQuoteEnclosedToken:
 			result = append(result, strings.Trim(match.Matched, `"`))
+			skipArgumentSeparator(tokenizer)
 		case comaToken:
 			result = append(result, match.Matched)
 			tokenizer.Index++
@@ -803,6 +848,16 @@ func (s *Map) expandArgumentsExpressions(argument interface{}) interface{} {
 	return result
 }
 
+// skipArgumentSeparator skips white spaces and a coma following quoted argument
+func skipArgumentSeparator(tokenizer *toolbox.Tokenizer) {
+	for tokenizer.Index < len(tokenizer.Input) && strings.ContainsRune(" \t\n\r", rune(tokenizer.Input[tokenizer.Index])) {
+		tokenizer.Index++
+	}
+	if tokenizer.Index < len(tokenizer.Input) && tokenizer.Input[tokenizer.Index] == ',' {
+		tokenizer.Index++
+	}
+}
+
 // NewMap creates a new instance of a map.
 func NewMap() Map {
 	return make(map[string]interface{})
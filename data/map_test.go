@@ -2,6 +2,7 @@ package data
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -320,6 +321,42 @@ func Test_Udf(t *testing.T) {
 	}
 }
 
+func Test_UdfArguments(t *testing.T) {
+	udfs := NewMap()
+	udfs.Put("Quote", func(source interface{}, m Map) (interface{}, error) {
+		return fmt.Sprintf("%q", source), nil
+	})
+	udfs.Put("Upper", func(source interface{}, m Map) (interface{}, error) {
+		return strings.ToUpper(toolbox.AsString(source)), nil
+	})
+	udfs.Put("Trim", func(source interface{}, m Map) (interface{}, error) {
+		return strings.TrimSpace(toolbox.AsString(source)), nil
+	})
+	state := NewMap()
+	state.Put(UDFKey, udfs)
+	state.Put("name", " endly ")
+
+	var useCases = []struct {
+		description string
+		text        string
+		expect      interface{}
+	}{
+		{description: "quoted arguments with separator", text: `$Quote("a", "b")`, expect: `["a" "b"]`},
+		{description: "quoted argument with coma", text: `$Quote("a, b", "c")`, expect: `["a, b" "c"]`},
+		{description: "quoted argument with space before coma", text: `$Quote("a" ,"b",c)`, expect: `["a" "b" "c"]`},
+		{description: "nested registered udf", text: `$Upper($Trim(" x "))`, expect: "X"},
+		{description: "nested registered udf with variable", text: `${Upper($Trim($name))}`, expect: "ENDLY"},
+	}
+	for _, useCase := range useCases {
+		expanded, err := state.ExpandStrict(useCase.text)
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, useCase.expect, expanded, useCase.description)
+	}
+
+	_, err := state.ExpandStrict(`$Upper($Missing(1))`)
+	assert.NotNil(t, err)
+}
+
 func Test_ExpandWithoutUDF(t *testing.T) {
 	state := NewMap()
 	{
@@ -434,3 +471,134 @@ func Test_SubState(t *testing.T) {
 	assert.EqualValues(t, `"Hello World"`, expanded)
 
 }
+
+func TestMap_ExpandAsTextStrict(t *testing.T) {
+	state := NewMap()
+	state.Put("name", "endly")
+	state.Put("key1", 1)
+	state.Put("fail", func(source interface{}, m Map) (interface{}, error) {
+		return nil, fmt.Errorf("invalid argument: %v", source)
+	})
+	state.Put("upper", func(source interface{}, m Map) (interface{}, error) {
+		return strings.ToUpper(toolbox.AsString(source)), nil
+	})
+
+	var useCases = []struct {
+		description string
+		text        string
+		expected    string
+		unresolved  []*UnresolvedExpression
+	}{
+		{
+			description: "all resolved",
+			text:        "app $name ${key1} $upper($name) ${(key1 + 1) * 3}",
+			expected:    "app endly 1 ENDLY 6",
+		},
+		{
+			description: "unresolved variables",
+			text:        "app $abc ${xyz.k1} $name",
+			expected:    "app $abc ${xyz.k1} endly",
+			unresolved: []*UnresolvedExpression{
+				{Expression: "$abc", Position: 4},
+				{Expression: "${xyz.k1}", Position: 9},
+			},
+		},
+		{
+			description: "failed UDF",
+			text:        "id: $fail($name)",
+			expected:    "id: $fail(endly)",
+			unresolved: []*UnresolvedExpression{
+				{Expression: "$fail($name)", Position: 4, Err: fmt.Errorf("invalid argument: endly")},
+			},
+		},
+		{
+			description: "UDF with unresolved argument",
+			text:        "$upper($abc)",
+			expected:    "$upper($abc)",
+			unresolved: []*UnresolvedExpression{
+				{Expression: "$upper($abc)", Position: 0, Err: fmt.Errorf("unresolved argument $abc")},
+			},
+		},
+		{
+			description: "undefined UDF",
+			text:        "$lower(abc)",
+			expected:    "$lower(abc)",
+			unresolved: []*UnresolvedExpression{
+				{Expression: "$lower(abc)", Position: 0, Err: fmt.Errorf("undefined function lower")},
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		expanded, err := state.ExpandAsTextStrict(useCase.text)
+		assert.EqualValues(t, useCase.expected, expanded, useCase.description)
+		assert.EqualValues(t, state.ExpandAsText(useCase.text), expanded, useCase.description)
+		if len(useCase.unresolved) == 0 {
+			assert.Nil(t, err, useCase.description)
+			continue
+		}
+		expansionError, ok := err.(*ExpansionError)
+		if !assert.True(t, ok, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.unresolved, expansionError.Unresolved, useCase.description)
+	}
+}
+
+func TestMap_ExpandStrict(t *testing.T) {
+	state := NewMap()
+	state.Put("name", "endly")
+	source := map[string]interface{}{
+		"k1": "$name",
+		"k2": []interface{}{
+			"ok",
+			"hello $user",
+		},
+		"$abc": "v",
+	}
+	expanded, err := state.ExpandStrict(source)
+	assert.EqualValues(t, state.Expand(source), expanded)
+	expansionError, ok := err.(*ExpansionError)
+	if !assert.True(t, ok) {
+		return
+	}
+	var actual = make(map[string]*UnresolvedExpression)
+	for _, unresolved := range expansionError.Unresolved {
+		actual[unresolved.Expression] = unresolved
+	}
+	assert.EqualValues(t, &UnresolvedExpression{Expression: "$user", Path: "/k2/1", Position: 6}, actual["$user"])
+	assert.EqualValues(t, &UnresolvedExpression{Expression: "$abc", Position: 0}, actual["$abc"])
+	assert.Contains(t, err.Error(), "unresolved $user at /k2/1:6")
+
+	_, err = state.ExpandStrict([]interface{}{"$name", 1})
+	assert.Nil(t, err)
+}
+
+func TestMap_ExpandStrictNested(t *testing.T) {
+	state := NewMap()
+	state.Put("obj", map[string]interface{}{"x": "$missingInner"})
+	state.Put("list", []interface{}{"ok", "$missingItem"})
+
+	var useCases = []struct {
+		description string
+		source      interface{}
+		expression  string
+		path        string
+	}{
+		{description: "map variable", source: "$obj", expression: "$missingInner", path: "/x"},
+		{description: "enclosed map variable", source: "${obj}", expression: "$missingInner", path: "/x"},
+		{description: "slice variable", source: "$list", expression: "$missingItem", path: "/1"},
+		{description: "map variable in map value", source: map[string]interface{}{"a": "$obj"}, expression: "$missingInner", path: "/a/x"},
+	}
+	for _, useCase := range useCases {
+		_, err := state.ExpandStrict(useCase.source)
+		expansionError, ok := err.(*ExpansionError)
+		if !assert.True(t, ok, useCase.description) {
+			continue
+		}
+		if assert.Len(t, expansionError.Unresolved, 1, useCase.description) {
+			assert.EqualValues(t, useCase.expression, expansionError.Unresolved[0].Expression, useCase.description)
+			assert.EqualValues(t, useCase.path, expansionError.Unresolved[0].Path, useCase.description)
+		}
+	}
+}
@@ -52,6 +52,14 @@ var matchers = map[int]toolbox.Matcher{
 }
 
 func Parse(expression string, handler func(expression string, isUDF bool, argument interface{}) (interface{}, bool)) interface{} {
+	return parse(expression, handler, nil)
+}
+
+// parse expands expression, report is called for each unresolved variable or UDF call with its position in the expression
+func parse(expression string, handler func(expression string, isUDF bool, argument interface{}) (interface{}, bool), report func(expression string, position int)) interface{} {
+	if report == nil {
+		report = func(expression string, position int) {}
+	}
 	tokenizer := toolbox.NewTokenizer(expression, invalidToken, eofToken, matchers)
 	var value interface{}
 	var result = fragments{}
@@ -72,13 +80,17 @@ func Parse(expression string, handler func(expression string, isUDF bool, argume
 
 		case varToken:
 			variable := "$"
+			position := tokenizer.Index - 1
 			match = tokenizer.Nexts(idToken, enclosedVarToken, incToken, decrementToken, shiftToken)
 			switch match.Token {
 			case eofToken:
 				result.Append(variable)
 				continue
 			case enclosedVarToken:
-
+				if value, ok = evaluateEnclosed(match.Matched, handler); ok {
+					result.Append(value)
+					continue
+				}
 				expanded := expandEnclosed(match.Matched, handler)
 				if toolbox.IsFloat(expanded) || toolbox.IsInt(expanded) || toolbox.IsBool(expanded) {
 					value = expanded
@@ -87,9 +99,14 @@ func Parse(expression string, handler func(expression string, isUDF bool, argume
 				}
 				expandedText := toolbox.AsString(expanded)
 				if strings.Contains(expandedText, ")") {
-					value = Parse("$"+expandedText, handler)
+					value = parse("$"+expandedText, handler, func(expression string, _ int) {
+						if expression != "$"+expandedText { //the whole expression is reported below
+							report(expression, position)
+						}
+					})
 					if textValue, ok := value.(string); ok && textValue == "$"+expandedText {
 						value = "${" + expandedText + "}"
+						report("$"+match.Matched, position)
 					} else if textValue, ok := value.(string); ok {
 						value = expandEnclosed("{"+textValue+"}", handler)
 					}
@@ -101,6 +118,9 @@ func Parse(expression string, handler func(expression string, isUDF bool, argume
 				if value, ok = handler(variable, false, ""); !ok {
 					value = variable
 				}
+				if textValue, ok := value.(string); ok && textValue == variable {
+					report("$"+match.Matched, position)
+				}
 				result.Append(value)
 				continue
 
@@ -125,6 +145,9 @@ func Parse(expression string, handler func(expression string, isUDF bool, argume
 					if value, ok = handler(variable, true, arguments); !ok {
 						value = variable + match.Matched
 					}
+					if textValue, ok := value.(string); ok && strings.HasPrefix(textValue, variable+"(") {
+						report(variable+match.Matched, position)
+					}
 
 					result.Append(value)
 					continue
@@ -137,6 +160,9 @@ func Parse(expression string, handler func(expression string, isUDF bool, argume
 					if value, ok = handler(variable, false, ""); !ok {
 						value = variable
 					}
+					if textValue, ok := value.(string); ok && textValue == variable {
+						report(variable, position)
+					}
 					result.Append(value)
 					result.Append(match.Matched)
 					continue
@@ -0,0 +1,365 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SyncMap represents concurrency safe, copy-on-write variant of Map.
+// Snapshot and Fork are O(1): the underlying state is shared until either side writes, the writer copies the state first.
+// Values returned by Get or GetValue may be shared with snapshots and must be treated as read only.
+type SyncMap struct {
+	mutex  sync.RWMutex
+	state  Map
+	shared bool //true if state is shared with a snapshot, fork or source map
+	base   Map  //state at fork time, used to detect changes on merge
+}
+
+// MergeConflict represents top level key changed by both the parent and the forked map since the fork
+type MergeConflict struct {
+	Key    string
+	Base   interface{} //value at fork time, nil if key did not exist
+	Parent interface{} //current parent value, nil if key was deleted
+	Child  interface{} //current child value, nil if key was deleted
+}
+
+// MergePolicy resolves a merge conflict, returned value is stored under conflicting key, nil value deletes the key
+type MergePolicy func(conflict *MergeConflict) (interface{}, error)
+
+// PreferChild resolves conflicts with the child value
+func PreferChild(conflict *MergeConflict) (interface{}, error) {
+	return conflict.Child, nil
+}
+
+// PreferParent resolves conflicts with the parent value
+func PreferParent(conflict *MergeConflict) (interface{}, error) {
+	return conflict.Parent, nil
+}
+
+// FailOnConflict rejects any conflict, merge is not applied
+func FailOnConflict(conflict *MergeConflict) (interface{}, error) {
+	return nil, fmt.Errorf("conflicting changes of %v: %v != %v", conflict.Key, conflict.Parent, conflict.Child)
+}
+
+// Get returns a value for provided key
+func (s *SyncMap) Get(key string) interface{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.state.Get(key)
+}
+
+// Has returns true if the provided key is present
+func (s *SyncMap) Has(key string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.state.Has(key)
+}
+
+// GetValue returns value for provided expression, shift (<-key) and increment (++key, key++) expressions are applied atomically
+func (s *SyncMap) GetValue(expr string) (interface{}, bool) {
+	if !isMutatingExpression(expr) {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		return s.state.GetValue(expr)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureWritable()
+	return s.state.GetValue(expr)
+}
+
+// isMutatingExpression returns true for shift or increment expressions
+func isMutatingExpression(expr string) bool {
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = expr[1 : len(expr)-1]
+	}
+	return strings.HasPrefix(expr, "<-") || strings.HasPrefix(expr, "++") || strings.HasSuffix(expr, "++")
+}
+
+// Put puts key value into the map.
+func (s *SyncMap) Put(key string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureWritable()
+	s.state.Put(key, value)
+}
+
+// SetValue sets value for the supplied expression, see Map.SetValue
+func (s *SyncMap) SetValue(expr string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureWritable()
+	s.state.SetValue(expr, value)
+}
+
+// Delete removes the supplied keys, see Map.Delete
+func (s *SyncMap) Delete(keys ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureWritable()
+	s.state.Delete(keys...)
+}
+
+// Apply copies all elements of provided map to this map.
+func (s *SyncMap) Apply(source map[string]interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureWritable()
+	s.state.Apply(source)
+}
+
+// Expand expands provided source with map state, source with shift, increment or UDF expressions is expanded with exclusive lock
+// since these can modify the state, otherwise the shared state is read without copying.
+func (s *SyncMap) Expand(source interface{}) interface{} {
+	if !isMutatingSource(source) {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		return s.state.Expand(source)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureWritable()
+	return s.state.Expand(source)
+}
+
+// ExpandAsText expands all matching expressions in the text, see Expand
+func (s *SyncMap) ExpandAsText(text string) string {
+	if !isMutatingSource(text) {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		return s.state.ExpandAsText(text)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureWritable()
+	return s.state.ExpandAsText(text)
+}
+
+// isMutatingSource returns true if source may contain shift, increment or UDF expressions
+func isMutatingSource(source interface{}) bool {
+	switch actual := source.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.Contains(actual, "<-") || strings.Contains(actual, "++") || strings.Contains(actual, "(")
+	case Map:
+		return isMutatingSource(map[string]interface{}(actual))
+	case map[string]interface{}:
+		for k, v := range actual {
+			if isMutatingSource(k) || isMutatingSource(v) {
+				return true
+			}
+		}
+		return false
+	case []interface{}:
+		for _, item := range actual {
+			if isMutatingSource(item) {
+				return true
+			}
+		}
+		return false
+	case *Collection:
+		return actual != nil && isMutatingSource([]interface{}(*actual))
+	}
+	switch reflect.ValueOf(source).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Ptr, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// Range iterates every key, value pair of the current state with read lock held, calling supplied callback as long it does return true.
+// The callback must not modify this map, use Snapshot to iterate state that is modified during iteration.
+func (s *SyncMap) Range(callback func(k string, v interface{}) (bool, error)) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.state.Range(callback)
+}
+
+// Len returns number of top level keys
+func (s *SyncMap) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.state)
+}
+
+// Clone returns independent deep copy of the current state
+func (s *SyncMap) Clone() Map {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return copyValue(s.state).(Map)
+}
+
+// Snapshot returns copy-on-write snapshot of this map
+func (s *SyncMap) Snapshot() *SyncMap {
+	return &SyncMap{state: s.share(), shared: true}
+}
+
+// Fork returns copy-on-write snapshot of this map that can be merged back with Merge
+func (s *SyncMap) Fork() *SyncMap {
+	state := s.share()
+	return &SyncMap{state: state, shared: true, base: state}
+}
+
+// Merge applies top level changes made in the forked child since the fork.
+// Keys changed only by the child are applied, keys changed differently by both maps are resolved with the policy.
+// If policy returns an error the merge is not applied.
+func (s *SyncMap) Merge(child *SyncMap, policy MergePolicy) error {
+	if policy == nil {
+		policy = FailOnConflict
+	}
+	child.mutex.Lock()
+	childState, base := child.state, child.base
+	child.shared = true
+	child.mutex.Unlock()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var keys = make([]string, 0, len(childState))
+	for key := range childState {
+		keys = append(keys, key)
+	}
+	for key := range base {
+		if _, ok := childState[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var changes = make(map[string]interface{})
+	for _, key := range keys {
+		baseValue, hasBase := base[key]
+		childValue, hasChild := childState[key]
+		if hasBase == hasChild && equalValues(baseValue, childValue) {
+			continue
+		}
+		parentValue, hasParent := s.state[key]
+		if (hasParent == hasBase && equalValues(parentValue, baseValue)) || (hasParent == hasChild && equalValues(parentValue, childValue)) {
+			changes[key] = childValue
+			continue
+		}
+		value, err := policy(&MergeConflict{Key: key, Base: baseValue, Parent: parentValue, Child: childValue})
+		if err != nil {
+			return fmt.Errorf("failed to merge %v: %v", key, err)
+		}
+		changes[key] = value
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	s.ensureWritable()
+	for key, value := range changes {
+		if value == nil {
+			delete(s.state, key)
+			continue
+		}
+		s.state[key] = copyValue(value)
+	}
+	return nil
+}
+
+// share marks the state as shared and returns it, subsequent writes copy the state first
+func (s *SyncMap) share() Map {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.shared = true
+	return s.state
+}
+
+// ensureWritable copies shared state, it has to be called with write lock held
+func (s *SyncMap) ensureWritable() {
+	if !s.shared {
+		return
+	}
+	s.state = copyValue(s.state).(Map)
+	s.shared = false
+}
+
+// copyValue returns deep copy of maps, slices and collections, other values are returned as is
+func copyValue(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case Map:
+		var result = make(Map, len(actual))
+		for k, v := range actual {
+			result[k] = copyValue(v)
+		}
+		return result
+	case map[string]interface{}:
+		var result = make(map[string]interface{}, len(actual))
+		for k, v := range actual {
+			result[k] = copyValue(v)
+		}
+		return result
+	case []interface{}:
+		var result = make([]interface{}, len(actual))
+		for i, v := range actual {
+			result[i] = copyValue(v)
+		}
+		return result
+	case *Collection:
+		if actual == nil {
+			return actual
+		}
+		var result = Collection(copyValue([]interface{}(*actual)).([]interface{}))
+		return &result
+	}
+	return value
+}
+
+// equalValues compares values deeply, functions are compared by identity
+func equalValues(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	leftValue, rightValue := reflect.ValueOf(left), reflect.ValueOf(right)
+	if leftValue.Type() != rightValue.Type() {
+		return false
+	}
+	switch leftValue.Kind() {
+	case reflect.Func:
+		return leftValue.Pointer() == rightValue.Pointer()
+	case reflect.Map:
+		if leftValue.Len() != rightValue.Len() {
+			return false
+		}
+		if leftValue.Pointer() == rightValue.Pointer() {
+			return true
+		}
+		for _, key := range leftValue.MapKeys() {
+			item := rightValue.MapIndex(key)
+			if !item.IsValid() || !equalValues(leftValue.MapIndex(key).Interface(), item.Interface()) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if leftValue.Len() != rightValue.Len() {
+			return false
+		}
+		for i := 0; i < leftValue.Len(); i++ {
+			if !equalValues(leftValue.Index(i).Interface(), rightValue.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	case reflect.Ptr:
+		if leftValue.Pointer() == rightValue.Pointer() {
+			return true
+		}
+		if leftValue.IsNil() || rightValue.IsNil() {
+			return false
+		}
+		return equalValues(leftValue.Elem().Interface(), rightValue.Elem().Interface())
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// NewSyncMap creates a concurrency safe map, source map is shared copy-on-write and is never modified
+func NewSyncMap(source Map) *SyncMap {
+	if source == nil {
+		return &SyncMap{state: NewMap()}
+	}
+	return &SyncMap{state: source, shared: true}
+}
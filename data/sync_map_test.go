@@ -0,0 +1,172 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMap_GetValue(t *testing.T) {
+	source := Map{"counter": 0, "request": map[string]interface{}{"method": "GET"}}
+	aMap := NewSyncMap(source)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+			aMap.GetValue("counter++")
+			aMap.SetValue(fmt.Sprintf("branch.k%d", i), i)
+			value, ok := aMap.GetValue("request.method")
+			assert.True(t, ok)
+			assert.Equal(t, "GET", value)
+			assert.Equal(t, "GET", aMap.ExpandAsText("$request.method"))
+		}(i)
+	}
+	waitGroup.Wait()
+	assert.Equal(t, 50, aMap.Get("counter"))
+	branch, _ := aMap.GetValue("branch")
+	assert.Equal(t, 50, len(branch.(Map)))
+	assert.Equal(t, 0, source["counter"], "source map should not be modified")
+}
+
+func TestSyncMap_Snapshot(t *testing.T) {
+	aMap := NewSyncMap(nil)
+	aMap.SetValue("a.b", 1)
+	aMap.SetValue("->items", "x")
+	snapshot := aMap.Snapshot()
+	aMap.SetValue("a.b", 2)
+	aMap.SetValue("->items", "y")
+	value, _ := snapshot.GetValue("a.b")
+	assert.Equal(t, 1, value)
+	items, _ := snapshot.GetValue("items")
+	assert.Equal(t, 1, len(*items.(*Collection)))
+	value, _ = aMap.GetValue("a.b")
+	assert.Equal(t, 2, value)
+
+	clone := aMap.Clone()
+	clone.SetValue("a.b", 3)
+	value, _ = aMap.GetValue("a.b")
+	assert.Equal(t, 2, value)
+}
+
+func TestSyncMap_Range(t *testing.T) {
+	parent := NewSyncMap(nil)
+	parent.Put("a", 1)
+	parent.Put("counter", 1)
+	fork := parent.Fork()
+	var keys = 0
+	assert.Nil(t, parent.Range(func(k string, v interface{}) (bool, error) {
+		keys++
+		return true, nil
+	}))
+	assert.Equal(t, 2, keys)
+	assert.Equal(t, "1", parent.ExpandAsText("$a"))
+	assert.EqualValues(t, Map{"value": 1}, parent.Expand(map[string]interface{}{"value": "$a"}))
+	assert.Equal(t, reflect.ValueOf(fork.state).Pointer(), reflect.ValueOf(parent.state).Pointer(), "read only access should not copy shared state")
+
+	assert.Equal(t, "1", parent.ExpandAsText("$counter++"))
+	assert.NotEqual(t, reflect.ValueOf(fork.state).Pointer(), reflect.ValueOf(parent.state).Pointer(), "increment should copy shared state")
+	assert.Equal(t, 2, parent.Get("counter"))
+	assert.Equal(t, 1, fork.Get("counter"))
+}
+
+func TestSyncMap_Merge(t *testing.T) {
+	var useCases = []struct {
+		description string
+		policy      MergePolicy
+		parent      func(aMap *SyncMap)
+		child       func(aMap *SyncMap)
+		expect      Map
+		hasError    bool
+	}{
+		{
+			description: "child changes",
+			parent:      func(aMap *SyncMap) { aMap.Put("p", 1) },
+			child: func(aMap *SyncMap) {
+				aMap.Put("c", 2)
+				aMap.Delete("d")
+			},
+			expect: Map{"k": 1, "p": 1, "c": 2},
+		},
+		{
+			description: "same change on both sides",
+			parent:      func(aMap *SyncMap) { aMap.Put("k", 2) },
+			child:       func(aMap *SyncMap) { aMap.Put("k", 2) },
+			expect:      Map{"k": 2, "d": 1},
+		},
+		{
+			description: "conflict prefer child",
+			policy:      PreferChild,
+			parent:      func(aMap *SyncMap) { aMap.Put("k", 2) },
+			child:       func(aMap *SyncMap) { aMap.Put("k", 3) },
+			expect:      Map{"k": 3, "d": 1},
+		},
+		{
+			description: "conflict prefer parent",
+			policy:      PreferParent,
+			parent:      func(aMap *SyncMap) { aMap.Put("k", 2) },
+			child:       func(aMap *SyncMap) { aMap.Delete("k") },
+			expect:      Map{"k": 2, "d": 1},
+		},
+		{
+			description: "conflict custom policy",
+			policy: func(conflict *MergeConflict) (interface{}, error) {
+				return conflict.Parent.(int) + conflict.Child.(int) - conflict.Base.(int), nil
+			},
+			parent: func(aMap *SyncMap) { aMap.Put("k", 2) },
+			child:  func(aMap *SyncMap) { aMap.Put("k", 3) },
+			expect: Map{"k": 4, "d": 1},
+		},
+		{
+			description: "conflict fail",
+			policy:      FailOnConflict,
+			parent: func(aMap *SyncMap) {
+				aMap.Put("k", 2)
+			},
+			child: func(aMap *SyncMap) {
+				aMap.Put("k", 3)
+				aMap.Put("c", 1)
+			},
+			expect:   Map{"k": 2, "d": 1},
+			hasError: true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		parent := NewSyncMap(Map{"k": 1, "d": 1})
+		child := parent.Fork()
+		useCase.parent(parent)
+		useCase.child(child)
+		err := parent.Merge(child, useCase.policy)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+		} else if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, parent.Clone(), useCase.description)
+	}
+}
+
+func TestSyncMap_MergeParallel(t *testing.T) {
+	parent := NewSyncMap(Map{"input": "x"})
+	var forks = make([]*SyncMap, 10)
+	var waitGroup sync.WaitGroup
+	for i := range forks {
+		forks[i] = parent.Fork()
+		waitGroup.Add(1)
+		go func(fork *SyncMap, i int) {
+			defer waitGroup.Done()
+			fork.SetValue(fmt.Sprintf("step%d.output", i), fork.ExpandAsText("$input-")+fmt.Sprint(i))
+		}(forks[i], i)
+	}
+	waitGroup.Wait()
+	for _, fork := range forks {
+		assert.Nil(t, parent.Merge(fork, FailOnConflict))
+	}
+	assert.Equal(t, 11, parent.Len())
+	value, _ := parent.GetValue("step3.output")
+	assert.Equal(t, "x-3", value)
+}
@@ -0,0 +1,187 @@
+package udf
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+// Sort returns sorted copy of a slice, maps are sorted by optional key, numbers are compared numerically
+func Sort(source interface{}, state data.Map) (interface{}, error) {
+	return sortUDF.Call(source, state)
+}
+
+var sortUDF = &Definition{
+	Name:    "Sort",
+	Params:  []*Param{{Name: "items", Type: SliceType}, {Name: "key", Type: StringType, Optional: true, Default: ""}, {Name: "descending", Type: BoolType, Optional: true, Default: false}},
+	Doc:     Doc{Description: "returns sorted slice, map items are sorted by key", Example: "$Sort($products, price, true)"},
+	Handler: sortItems,
+}
+
+func sortItems(args []interface{}, state data.Map) (interface{}, error) {
+	items, key, descending := args[0].([]interface{}), args[1].(string), args[2].(bool)
+	var result = make([]interface{}, len(items))
+	copy(result, items)
+	sortValue := func(item interface{}) interface{} {
+		if key == "" || !toolbox.IsMap(item) {
+			return item
+		}
+		itemMap := data.Map(toolbox.AsMap(item))
+		value, _ := itemMap.GetValue(key)
+		return value
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if descending {
+			return less(sortValue(result[j]), sortValue(result[i]))
+		}
+		return less(sortValue(result[i]), sortValue(result[j]))
+	})
+	return result, nil
+}
+
+// less compares numbers numerically and other values as text
+func less(left, right interface{}) bool {
+	if toolbox.IsNumber(left) && toolbox.IsNumber(right) {
+		return toolbox.AsFloat(left) < toolbox.AsFloat(right)
+	}
+	return toolbox.AsString(left) < toolbox.AsString(right)
+}
+
+// Unique returns slice items without duplicates, the first occurrence is kept
+func Unique(source interface{}, state data.Map) (interface{}, error) {
+	return uniqueUDF.Call(source, state)
+}
+
+var uniqueUDF = &Definition{
+	Name:    "Unique",
+	Params:  []*Param{{Name: "items", Type: SliceType}},
+	Doc:     Doc{Description: "returns slice items without duplicates", Example: "$Unique($tags)"},
+	Handler: unique,
+}
+
+func unique(args []interface{}, state data.Map) (interface{}, error) {
+	var result = make([]interface{}, 0)
+	var has = make(map[string]bool)
+	for _, item := range args[0].([]interface{}) {
+		key := fmt.Sprintf("%T:%v", item, item)
+		if toolbox.IsMap(item) || toolbox.IsSlice(item) {
+			if text, err := toolbox.AsJSONText(item); err == nil {
+				key = text
+			}
+		}
+		if has[key] {
+			continue
+		}
+		has[key] = true
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// Filter returns slice items matching predicate expression, map item keys and item itself (as item) can be used in the expression
+func Filter(source interface{}, state data.Map) (interface{}, error) {
+	return filterUDF.Call(source, state)
+}
+
+var filterUDF = &Definition{
+	Name:    "Filter",
+	Params:  []*Param{{Name: "items", Type: SliceType}, {Name: "predicate", Type: StringType}},
+	Doc:     Doc{Description: "returns items matching predicate expression, map keys and item can be used in the expression", Example: "$Filter($products, \"price > 10 && active\")"},
+	Handler: filter,
+}
+
+func filter(args []interface{}, state data.Map) (interface{}, error) {
+	predicate, err := data.Compile(args[1].(string))
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]interface{}, 0)
+	for _, item := range args[0].([]interface{}) {
+		itemState := data.NewMap()
+		if toolbox.IsMap(item) {
+			for k, v := range toolbox.AsMap(item) {
+				itemState[k] = v
+			}
+		}
+		itemState.Put("item", item)
+		matched, err := predicate.Evaluate(itemState)
+		if err != nil {
+			return nil, err
+		}
+		if toolbox.AsBoolean(matched) {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// Slice returns slice items between from and optional to index, negative index is counted from the end
+func Slice(source interface{}, state data.Map) (interface{}, error) {
+	return sliceUDF.Call(source, state)
+}
+
+var sliceUDF = &Definition{
+	Name:    "Slice",
+	Params:  []*Param{{Name: "items", Type: SliceType}, {Name: "from", Type: IntType}, {Name: "to", Type: IntType, Optional: true}},
+	Doc:     Doc{Description: "returns items between from and optional to index, negative index is counted from the end", Example: "$Slice($items, 0, 10)"},
+	Handler: slice,
+}
+
+func slice(args []interface{}, state data.Map) (interface{}, error) {
+	items := args[0].([]interface{})
+	from, to := sliceRange(len(items), args[1].(int), args[2])
+	var result = make([]interface{}, to-from)
+	copy(result, items[from:to])
+	return result, nil
+}
+
+// Reverse returns slice items in reversed order
+func Reverse(source interface{}, state data.Map) (interface{}, error) {
+	return reverseUDF.Call(source, state)
+}
+
+var reverseUDF = &Definition{
+	Name:    "Reverse",
+	Params:  []*Param{{Name: "items", Type: SliceType}},
+	Doc:     Doc{Description: "returns items in reversed order", Example: "$Reverse($items)"},
+	Handler: reverse,
+}
+
+func reverse(args []interface{}, state data.Map) (interface{}, error) {
+	items := args[0].([]interface{})
+	var result = make([]interface{}, len(items))
+	for i, item := range items {
+		result[len(items)-1-i] = item
+	}
+	return result, nil
+}
+
+// Flatten returns items of nested slices as a single slice
+func Flatten(source interface{}, state data.Map) (interface{}, error) {
+	return flattenUDF.Call(source, state)
+}
+
+var flattenUDF = &Definition{
+	Name:    "Flatten",
+	Params:  []*Param{{Name: "items", Type: SliceType}},
+	Doc:     Doc{Description: "returns items of nested slices as a single slice", Example: "$Flatten($groups)"},
+	Handler: flatten,
+}
+
+func flatten(args []interface{}, state data.Map) (interface{}, error) {
+	var result = make([]interface{}, 0)
+	var appendItems func(items []interface{})
+	appendItems = func(items []interface{}) {
+		for _, item := range items {
+			if _, isBytes := item.([]byte); !isBytes && toolbox.IsSlice(item) {
+				appendItems(toolbox.AsSlice(item))
+				continue
+			}
+			result = append(result, item)
+		}
+	}
+	appendItems(args[0].([]interface{}))
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package udf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/data"
+)
+
+func Test_Sort(t *testing.T) {
+	{
+		value, err := Sort([]interface{}{10, 2, "1.5"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{"1.5", 2, 10}, value)
+	}
+	{
+		value, err := Sort(`["b", "c", "a"]`, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{"a", "b", "c"}, value)
+	}
+	{
+		products := []interface{}{
+			map[string]interface{}{"id": 1, "price": 12},
+			map[string]interface{}{"id": 2, "price": 3},
+			map[string]interface{}{"id": 3, "price": 20},
+		}
+		value, err := Sort([]interface{}{products, "price", true}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{products[2], products[0], products[1]}, value)
+		assert.EqualValues(t, 1, products[0].(map[string]interface{})["id"])
+	}
+	{
+		value, err := Sort([]interface{}{[]interface{}{3, 1}, []interface{}{2}}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{[]interface{}{2}, []interface{}{3, 1}}, value)
+	}
+	{
+		groups := []interface{}{[]interface{}{3, 1}, []interface{}{2}}
+		value, err := Sort([]interface{}{groups, "", true}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{[]interface{}{3, 1}, []interface{}{2}}, value)
+	}
+	{
+		aMap := data.NewMap()
+		aMap.Put("items", []interface{}{"b", "a"})
+		Register(aMap)
+		assert.EqualValues(t, []interface{}{"a", "b"}, aMap.Expand(`$Sort($items)`))
+	}
+}
+
+func Test_Unique(t *testing.T) {
+	value, err := Unique([]interface{}{1, "1", 2, 1, map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1}}, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{1, "1", 2, map[string]interface{}{"a": 1}}, value)
+}
+
+func Test_Filter(t *testing.T) {
+	{
+		products := []interface{}{
+			map[string]interface{}{"id": 1, "price": 12, "active": true},
+			map[string]interface{}{"id": 2, "price": 3, "active": true},
+			map[string]interface{}{"id": 3, "price": 20, "active": false},
+		}
+		value, err := Filter([]interface{}{products, "price > 10 && active"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{products[0]}, value)
+	}
+	{
+		value, err := Filter([]interface{}{[]interface{}{1, 5, 8}, "item % 2 == 0"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{8}, value)
+	}
+	{
+		_, err := Filter([]interface{}{[]interface{}{1}, "item >"}, nil)
+		assert.NotNil(t, err)
+	}
+}
+
+func Test_Slice(t *testing.T) {
+	{
+		value, err := Slice([]interface{}{[]interface{}{1, 2, 3, 4}, 1, 3}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{2, 3}, value)
+	}
+	{
+		value, err := Slice([]interface{}{[]interface{}{1, 2, 3, 4}, -1}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{4}, value)
+	}
+	{
+		value, err := Slice([]interface{}{[]interface{}{1, 2}, 5}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{}, value)
+	}
+}
+
+func Test_Reverse(t *testing.T) {
+	value, err := Reverse([]interface{}{1, 2, 3}, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{3, 2, 1}, value)
+}
+
+func Test_Flatten(t *testing.T) {
+	{
+		value, err := Flatten([]interface{}{[]interface{}{1, []interface{}{2, []interface{}{3}}, []string{"a"}}}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{1, 2, 3, "a"}, value)
+	}
+	{
+		aMap := data.NewMap()
+		aMap.Put("groups", []interface{}{[]interface{}{"a", "b"}, []interface{}{"b", "c"}})
+		Register(aMap)
+		expanded := aMap.Expand(`$Unique($Flatten($groups))`)
+		assert.EqualValues(t, []interface{}{"a", "b", "c"}, expanded)
+	}
+}
@@ -0,0 +1,111 @@
+package udf
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/viant/toolbox/data"
+)
+
+// Md5 returns hex encoded md5 hash of text
+func Md5(source interface{}, state data.Map) (interface{}, error) {
+	return md5UDF.Call(source, state)
+}
+
+var md5UDF = &Definition{
+	Name:    "Md5",
+	Params:  []*Param{{Name: "text", Type: StringType}},
+	Doc:     Doc{Description: "returns hex encoded md5 hash", Example: "$Md5($text)"},
+	Handler: hashHandler(md5.New),
+}
+
+// Sha1 returns hex encoded sha1 hash of text
+func Sha1(source interface{}, state data.Map) (interface{}, error) {
+	return sha1UDF.Call(source, state)
+}
+
+var sha1UDF = &Definition{
+	Name:    "Sha1",
+	Params:  []*Param{{Name: "text", Type: StringType}},
+	Doc:     Doc{Description: "returns hex encoded sha1 hash", Example: "$Sha1($text)"},
+	Handler: hashHandler(sha1.New),
+}
+
+// Sha256 returns hex encoded sha256 hash of text
+func Sha256(source interface{}, state data.Map) (interface{}, error) {
+	return sha256UDF.Call(source, state)
+}
+
+var sha256UDF = &Definition{
+	Name:    "Sha256",
+	Params:  []*Param{{Name: "text", Type: StringType}},
+	Doc:     Doc{Description: "returns hex encoded sha256 hash", Example: "$Sha256($text)"},
+	Handler: hashHandler(sha256.New),
+}
+
+func hashHandler(newHash func() hash.Hash) Func {
+	return func(args []interface{}, state data.Map) (interface{}, error) {
+		aHash := newHash()
+		aHash.Write([]byte(args[0].(string)))
+		return hex.EncodeToString(aHash.Sum(nil)), nil
+	}
+}
+
+var hmacHashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Hmac returns hex encoded HMAC of text with supplied key, algorithm is one of md5, sha1, sha256 (default) or sha512
+func Hmac(source interface{}, state data.Map) (interface{}, error) {
+	return hmacUDF.Call(source, state)
+}
+
+var hmacUDF = &Definition{
+	Name:    "Hmac",
+	Params:  []*Param{{Name: "text", Type: StringType}, {Name: "key", Type: StringType}, {Name: "algorithm", Type: StringType, Optional: true, Default: "sha256"}},
+	Doc:     Doc{Description: "returns hex encoded HMAC, algorithm: md5, sha1, sha256 (default) or sha512", Example: "$Hmac($payload, $secret, sha256)"},
+	Handler: hmacHash,
+}
+
+func hmacHash(args []interface{}, state data.Map) (interface{}, error) {
+	algorithm := strings.ToLower(args[2].(string))
+	newHash, ok := hmacHashes[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hmac algorithm: %v", algorithm)
+	}
+	aHash := hmac.New(newHash, []byte(args[1].(string)))
+	aHash.Write([]byte(args[0].(string)))
+	return hex.EncodeToString(aHash.Sum(nil)), nil
+}
+
+// UUID returns random (version 4) UUID
+func UUID(source interface{}, state data.Map) (interface{}, error) {
+	return uuidUDF.Call(source, state)
+}
+
+var uuidUDF = &Definition{
+	Name:    "UUID",
+	Doc:     Doc{Description: "returns random (version 4) UUID", Example: "$UUID()"},
+	Handler: newUUID,
+}
+
+func newUUID(args []interface{}, state data.Map) (interface{}, error) {
+	var id = make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	id[6] = (id[6] & 0x0f) | 0x40 //version 4
+	id[8] = (id[8] & 0x3f) | 0x80 //RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
@@ -0,0 +1,51 @@
+package udf
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Hash(t *testing.T) {
+	{
+		value, err := Md5("abc", nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "900150983cd24fb0d6963f7d28e17f72", value)
+	}
+	{
+		value, err := Sha1("abc", nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "a9993e364706816aba3e25717850c26c9cd0d89d", value)
+	}
+	{
+		value, err := Sha256("abc", nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", value)
+	}
+}
+
+func Test_Hmac(t *testing.T) {
+	{
+		value, err := Hmac([]interface{}{"The quick brown fox jumps over the lazy dog", "key"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", value)
+	}
+	{
+		value, err := Hmac([]interface{}{"The quick brown fox jumps over the lazy dog", "key", "md5"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "80070713463e7749b90c2dc24911e275", value)
+	}
+	{
+		_, err := Hmac([]interface{}{"abc", "key", "crc"}, nil)
+		assert.EqualError(t, err, "unsupported hmac algorithm: crc")
+	}
+}
+
+func Test_UUID(t *testing.T) {
+	value, err := UUID(nil, nil)
+	assert.Nil(t, err)
+	assert.Regexp(t, regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$"), value)
+	other, _ := UUID("", nil)
+	assert.NotEqual(t, value, other)
+}
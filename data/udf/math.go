@@ -0,0 +1,87 @@
+package udf
+
+import (
+	"math"
+
+	"github.com/viant/toolbox/data"
+)
+
+// Min returns the smallest of supplied numbers
+func Min(source interface{}, state data.Map) (interface{}, error) {
+	return minUDF.Call(source, state)
+}
+
+var minUDF = &Definition{
+	Name:    "Min",
+	Params:  []*Param{{Name: "values", Type: NumberType, Variadic: true}},
+	Doc:     Doc{Description: "returns the smallest number of values or slice", Example: "$Min($prices)"},
+	Handler: func(args []interface{}, state data.Map) (interface{}, error) { return extreme(args, -1), nil },
+}
+
+// Max returns the largest of supplied numbers
+func Max(source interface{}, state data.Map) (interface{}, error) {
+	return maxUDF.Call(source, state)
+}
+
+var maxUDF = &Definition{
+	Name:    "Max",
+	Params:  []*Param{{Name: "values", Type: NumberType, Variadic: true}},
+	Doc:     Doc{Description: "returns the largest number of values or slice", Example: "$Max(1, $count)"},
+	Handler: func(args []interface{}, state data.Map) (interface{}, error) { return extreme(args, 1), nil },
+}
+
+// extreme returns min (sign -1) or max (sign 1) number or nil for no arguments
+func extreme(args []interface{}, sign float64) interface{} {
+	var result interface{}
+	for _, arg := range args {
+		if result == nil || sign*asFloat(arg) > sign*asFloat(result) {
+			result = arg
+		}
+	}
+	return result
+}
+
+func asFloat(number interface{}) float64 {
+	if intValue, ok := number.(int); ok {
+		return float64(intValue)
+	}
+	return number.(float64)
+}
+
+// Round returns value rounded half away from zero to supplied decimal places
+func Round(source interface{}, state data.Map) (interface{}, error) {
+	return roundUDF.Call(source, state)
+}
+
+var roundUDF = &Definition{
+	Name:    "Round",
+	Params:  []*Param{{Name: "value", Type: FloatType}, {Name: "precision", Type: IntType, Optional: true, Default: 0}},
+	Doc:     Doc{Description: "rounds value half away from zero to precision decimal places", Example: "$Round($price, 2)"},
+	Handler: round,
+}
+
+func round(args []interface{}, state data.Map) (interface{}, error) {
+	value, precision := args[0].(float64), args[1].(int)
+	factor := math.Pow(10, float64(precision))
+	return AsNumber(math.Round(value*factor)/factor, state)
+}
+
+// Abs returns absolute value
+func Abs(source interface{}, state data.Map) (interface{}, error) {
+	return absUDF.Call(source, state)
+}
+
+var absUDF = &Definition{
+	Name:   "Abs",
+	Params: []*Param{{Name: "value", Type: NumberType}},
+	Doc:    Doc{Description: "returns absolute value", Example: "$Abs($delta)"},
+	Handler: func(args []interface{}, state data.Map) (interface{}, error) {
+		if intValue, ok := args[0].(int); ok {
+			if intValue < 0 {
+				return -intValue, nil
+			}
+			return intValue, nil
+		}
+		return math.Abs(args[0].(float64)), nil
+	},
+}
@@ -0,0 +1,60 @@
+package udf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MinMax(t *testing.T) {
+	{
+		value, err := Min([]interface{}{3, "1.5", 2}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1.5, value)
+	}
+	{
+		value, err := Max([]interface{}{3, "1.5", 12}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 12, value)
+	}
+	{
+		value, err := Max(7, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 7, value)
+	}
+	{
+		_, err := Min([]interface{}{1, "abc"}, nil)
+		assert.NotNil(t, err)
+	}
+}
+
+func Test_Round(t *testing.T) {
+	{
+		value, err := Round(2.5, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, value)
+	}
+	{
+		value, err := Round([]interface{}{"3.14159", 2}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3.14, value)
+	}
+	{
+		value, err := Round([]interface{}{-1.005, 1}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, -1, value)
+	}
+}
+
+func Test_Abs(t *testing.T) {
+	{
+		value, err := Abs(-3, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, value)
+	}
+	{
+		value, err := Abs("-2.5", nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2.5, value)
+	}
+}
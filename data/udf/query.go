@@ -0,0 +1,29 @@
+package udf
+
+import (
+	"github.com/viant/toolbox/data"
+)
+
+// Query returns nodes matched by JSONPath query on optional source or state, i.e. $Query("$..book[?(@.price > 10)].title")
+func Query(source interface{}, state data.Map) (interface{}, error) {
+	return queryUDF.Call(source, state)
+}
+
+var queryUDF = &Definition{
+	Name:    "Query",
+	Params:  []*Param{{Name: "path", Type: StringType}, {Name: "source", Type: AnyType, Optional: true}},
+	Doc:     Doc{Description: "returns nodes matched by JSONPath query on source or state", Example: "$Query(\"$.response.items[?(@.price > 10)].id\")"},
+	Handler: query,
+}
+
+func query(args []interface{}, state data.Map) (interface{}, error) {
+	jsonPath, err := data.CompileJSONPath(args[0].(string))
+	if err != nil {
+		return nil, err
+	}
+	document := args[1]
+	if document == nil {
+		document = map[string]interface{}(state)
+	}
+	return jsonPath.Select(document), nil
+}
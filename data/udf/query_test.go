@@ -0,0 +1,37 @@
+package udf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/data"
+)
+
+func Test_Query(t *testing.T) {
+	state := data.NewMap()
+	state.Put("response", map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": 1, "price": 3},
+			map[string]interface{}{"id": 2, "price": 13},
+			map[string]interface{}{"id": 3, "price": 23},
+		},
+	})
+	{
+		value, err := Query("$.response.items[?(@.price > 10)].id", state)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{2, 3}, value)
+	}
+	{
+		value, err := Query([]interface{}{"$[0,2]", []interface{}{"a", "b", "c"}}, state)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{"a", "c"}, value)
+	}
+	{
+		_, err := Query("$.response[", state)
+		assert.NotNil(t, err)
+	}
+	{
+		Register(state)
+		assert.EqualValues(t, []interface{}{1, 2, 3}, state.Expand(`$Query("$..id")`))
+	}
+}
@@ -2,50 +2,95 @@ package udf
 
 import "github.com/viant/toolbox/data"
 
-var Predefined = data.Map{
-	"AsInt":                  AsInt,
-	"AsString":               AsString,
-	"AsFloat":                AsFloat,
-	"AsFloat32":              AsFloat32,
-	"AsFloat32Ptr":           AsFloat32Ptr,
-	"AsBool":                 AsBool,
-	"AsMap":                  AsMap,
-	"AsData":                 AsData,
-	"AsCollection":           AsCollection,
-	"AsJSON":                 AsJSON,
-	"Type":                   Type,
-	"Join":                   Join,
-	"Split":                  Split,
-	"Keys":                   Keys,
-	"StringKeys":             StringKeys,
-	"Values":                 Values,
-	"Length":                 Length,
-	"Len":                    Length,
-	"IndexOf":                IndexOf,
-	"FormatTime":             FormatTime,
-	"QueryEscape":            QueryEscape,
-	"QueryUnescape":          QueryUnescape,
-	"Base64Encode":           Base64Encode,
-	"Base64Decode":           Base64Decode,
-	"Base64RawURLEncode":     Base64RawURLEncode,
-	"Base64RawURLDecode":     Base64RawURLDecode,
-	"Base64DecodeText":       Base64DecodeText,
-	"TrimSpace":              TrimSpace,
-	"Elapsed":                Elapsed,
-	"Sum":                    Sum,
-	"Count":                  Count,
-	"AsNumber":               AsNumber,
-	"Select":                 Select,
-	"Rand":                   Rand,
-	"Concat":                 Concat,
-	"Merge":                  Merge,
-	"AsStringMap":            AsStringMap,
-	"PackInt32sTo64":         PackInt32sTo64,
-	"Replace":                Replace,
-	"ToLower":                ToLower,
-	"ToUpper":                ToUpper,
-	"AsNewLineDelimitedJSON": AsNewLineDelimitedJSON,
-	"LoadJSON":               LoadJSON,
+// Definitions represents predefined UDF definitions
+var Definitions = []*Definition{
+	{Name: "AsInt", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts value to int"}, Handler: unary(AsInt)},
+	{Name: "AsString", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts value to string, map, slice or struct are converted to JSON"}, Handler: unary(AsString)},
+	{Name: "AsFloat", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts value to float64"}, Handler: unary(AsFloat)},
+	{Name: "AsFloat32", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts value to float32"}, Handler: unary(AsFloat32)},
+	{Name: "AsFloat32Ptr", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts value to *float32"}, Handler: unary(AsFloat32Ptr)},
+	{Name: "AsBool", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts value to bool"}, Handler: unary(AsBool)},
+	{Name: "AsMap", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts data structure, JSON or YAML literal to a map"}, Handler: unary(AsMap)},
+	{Name: "AsData", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts data structure, JSON or YAML literal to a map or slice"}, Handler: unary(AsData)},
+	{Name: "AsCollection", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts data structure, JSON or YAML literal to a slice"}, Handler: unary(AsCollection)},
+	{Name: "AsJSON", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts value to indented JSON"}, Handler: unary(AsJSON)},
+	{Name: "AsNumber", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "converts value to int or float64 if it has a fraction"}, Handler: unary(AsNumber)},
+	{Name: "AsStringMap", Params: []*Param{{Name: "value", Type: MapType}}, Doc: Doc{Description: "converts map values to string"}, Handler: unary(AsStringMap)},
+	{Name: "AsNewLineDelimitedJSON", Params: []*Param{{Name: "items", Type: SliceType}}, Doc: Doc{Description: "converts slice to new line delimited JSON"}, Handler: unary(AsNewLineDelimitedJSON)},
+	{Name: "Type", Params: []*Param{{Name: "value", Type: AnyType}}, Doc: Doc{Description: "returns value type"}, Handler: unary(Type)},
+	{Name: "Keys", Params: []*Param{{Name: "source", Type: AnyType}}, Doc: Doc{Description: "returns map keys"}, Handler: unary(Keys)},
+	{Name: "StringKeys", Params: []*Param{{Name: "source", Type: AnyType}}, Doc: Doc{Description: "returns map keys as strings"}, Handler: unary(StringKeys)},
+	{Name: "Values", Params: []*Param{{Name: "source", Type: AnyType}}, Doc: Doc{Description: "returns map values"}, Handler: unary(Values)},
+	{Name: "Length", Aliases: []string{"Len"}, Params: []*Param{{Name: "source", Type: AnyType}}, Doc: Doc{Description: "returns length of slice, map or string"}, Handler: unary(Length)},
+	{Name: "QueryEscape", Params: []*Param{{Name: "text", Type: StringType}}, Doc: Doc{Description: "url escapes text"}, Handler: unary(QueryEscape)},
+	{Name: "QueryUnescape", Params: []*Param{{Name: "text", Type: StringType}}, Doc: Doc{Description: "url unescapes text"}, Handler: unary(QueryUnescape)},
+	{Name: "Base64Encode", Params: []*Param{{Name: "source", Type: AnyType}}, Doc: Doc{Description: "encodes text, bytes or JSON of data structure with base64 standard encoding"}, Handler: unary(Base64Encode)},
+	{Name: "Base64Decode", Params: []*Param{{Name: "text", Type: AnyType}}, Doc: Doc{Description: "decodes base64 standard encoded text to bytes"}, Handler: unary(Base64Decode)},
+	{Name: "Base64RawURLEncode", Params: []*Param{{Name: "source", Type: AnyType}}, Doc: Doc{Description: "encodes text, bytes or JSON of data structure with base64 raw url encoding"}, Handler: unary(Base64RawURLEncode)},
+	{Name: "Base64RawURLDecode", Params: []*Param{{Name: "text", Type: AnyType}}, Doc: Doc{Description: "decodes base64 raw url encoded text to bytes"}, Handler: unary(Base64RawURLDecode)},
+	{Name: "Base64DecodeText", Params: []*Param{{Name: "text", Type: AnyType}}, Doc: Doc{Description: "decodes base64 standard encoded text to text"}, Handler: unary(Base64DecodeText)},
+	{Name: "TrimSpace", Params: []*Param{{Name: "text", Type: StringType}}, Doc: Doc{Description: "trims leading and trailing white spaces"}, Handler: unary(TrimSpace)},
+	{Name: "ToLower", Params: []*Param{{Name: "text", Type: StringType}}, Doc: Doc{Description: "converts text to lower case"}, Handler: unary(ToLower)},
+	{Name: "ToUpper", Params: []*Param{{Name: "text", Type: StringType}}, Doc: Doc{Description: "converts text to upper case"}, Handler: unary(ToUpper)},
+	{Name: "Elapsed", Params: []*Param{{Name: "time", Type: AnyType}}, Doc: Doc{Description: "returns time elapsed since RFC3339 time, i.e. 3d, 2h, 15s"}, Handler: unary(Elapsed)},
+	{Name: "Sum", Params: []*Param{{Name: "path", Type: StringType}}, Doc: Doc{Description: "sums values of matched state path, i.e. $Sum('node1/obj/*/amount')"}, Handler: unary(Sum)},
+	{Name: "Count", Params: []*Param{{Name: "path", Type: StringType}}, Doc: Doc{Description: "counts values of matched state path, i.e. $Count('node1/obj/*/amount')"}, Handler: unary(Count)},
+	{Name: "PackInt32sTo64", Params: []*Param{{Name: "values", Type: AnyType}}, Doc: Doc{Description: "returns values[0]<<32 | values[1]"}, Handler: unary(PackInt32sTo64)},
+	{Name: "LoadJSON", Params: []*Param{{Name: "location", Type: StringType}}, Doc: Doc{Description: "loads JSON or new line delimited JSON file"}, Handler: unary(LoadJSON)},
+	replaceUDF,
+	joinUDF,
+	splitUDF,
+	indexOfUDF,
+	selectUDF,
+	randUDF,
+	concatUDF,
+	mergeUDF,
+	formatTimeUDF,
+	timeAddUDF,
+	timeDiffUDF,
+	padLeftUDF,
+	padRightUDF,
+	substringUDF,
+	matchesUDF,
+	regexReplaceUDF,
+	sprintfUDF,
+	md5UDF,
+	sha1UDF,
+	sha256UDF,
+	hmacUDF,
+	uuidUDF,
+	minUDF,
+	maxUDF,
+	roundUDF,
+	absUDF,
+	sortUDF,
+	uniqueUDF,
+	filterUDF,
+	sliceUDF,
+	reverseUDF,
+	flattenUDF,
+	queryUDF,
+}
+
+// Default represents predefined UDFs registry
+var Default = newDefaultRegistry()
+
+// Predefined represents predefined UDFs map keyed by name and aliases
+var Predefined = Default.Map()
+
+func newDefaultRegistry() *Registry {
+	registry, err := NewRegistry(Definitions...)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// unary adapts UDF taking a single argument to a typed handler
+func unary(udf func(interface{}, data.Map) (interface{}, error)) Func {
+	return func(args []interface{}, state data.Map) (interface{}, error) {
+		return udf(args[0], state)
+	}
 }
 
 func Register(aMap data.Map) {
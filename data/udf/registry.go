@@ -0,0 +1,76 @@
+package udf
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/viant/toolbox/data"
+)
+
+// Registry represents UDF definitions registry
+type Registry struct {
+	mux         sync.RWMutex
+	definitions map[string]*Definition
+}
+
+// Register validates and registers definitions, it returns an error if a name or alias is already registered
+func (r *Registry) Register(definitions ...*Definition) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for _, definition := range definitions {
+		if err := definition.Validate(); err != nil {
+			return err
+		}
+		names := append([]string{definition.Name}, definition.Aliases...)
+		for _, name := range names {
+			if _, ok := r.definitions[name]; ok {
+				return fmt.Errorf("udf %v was already registered", name)
+			}
+		}
+		for _, name := range names {
+			r.definitions[name] = definition
+		}
+	}
+	return nil
+}
+
+// Definition returns definition for supplied name or alias or nil
+func (r *Registry) Definition(name string) *Definition {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.definitions[name]
+}
+
+// Definitions returns registered definitions sorted by name
+func (r *Registry) Definitions() []*Definition {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	var result = make([]*Definition, 0, len(r.definitions))
+	for name, definition := range r.definitions {
+		if name == definition.Name {
+			result = append(result, definition)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Map returns UDFs map keyed by name and aliases, it can be used as data.UDFKey value
+func (r *Registry) Map() data.Map {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	var result = data.NewMap()
+	for name, definition := range r.definitions {
+		result[name] = definition.UDF()
+	}
+	return result
+}
+
+// NewRegistry creates a registry with supplied definitions
+func NewRegistry(definitions ...*Definition) (*Registry, error) {
+	result := &Registry{definitions: make(map[string]*Definition)}
+	return result, result.Register(definitions...)
+}
@@ -0,0 +1,136 @@
+package udf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/data"
+)
+
+func TestDefinition_Call(t *testing.T) {
+	repeat := &Definition{
+		Name: "Repeat",
+		Params: []*Param{
+			{Name: "text", Type: StringType},
+			{Name: "count", Type: IntType, Optional: true, Default: 2},
+			{Name: "suffixes", Type: StringType, Variadic: true},
+		},
+		Handler: func(args []interface{}, state data.Map) (interface{}, error) {
+			return strings.Repeat(args[0].(string), args[1].(int)) + strings.Join(toStrings(args[2:]), ""), nil
+		},
+	}
+	assert.Nil(t, repeat.Validate())
+	assert.EqualValues(t, "Repeat(text string, [count int], suffixes ...string)", repeat.Signature())
+
+	var useCases = []struct {
+		description string
+		source      interface{}
+		expected    interface{}
+		expectError string
+	}{
+		{description: "single argument", source: "ab", expected: "abab"},
+		{description: "converted argument", source: []interface{}{"ab", "3"}, expected: "ababab"},
+		{description: "variadic arguments", source: []interface{}{"ab", 1, "c", 2}, expected: "abc2"},
+		{description: "missing argument", source: nil, expectError: "Repeat(text string, [count int], suffixes ...string): expected at least 1 argument(s), but had 0"},
+		{description: "invalid argument", source: []interface{}{"ab", "x"}, expectError: "Repeat(text string, [count int], suffixes ...string): invalid count argument"},
+		{description: "invalid variadic argument", source: []interface{}{"ab", 1, "c", []interface{}{1}}, expectError: "invalid suffixes argument[1]: expected string, but had []interface {}"},
+	}
+	for _, useCase := range useCases {
+		actual, err := repeat.Call(useCase.source, nil)
+		if useCase.expectError != "" {
+			if assert.NotNil(t, err, useCase.description) {
+				assert.Contains(t, err.Error(), useCase.expectError, useCase.description)
+			}
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, useCase.expected, actual, useCase.description)
+	}
+}
+
+func TestDefinition_Arguments(t *testing.T) {
+	count := &Definition{
+		Name:   "Count",
+		Params: []*Param{{Name: "items", Type: SliceType}, {Name: "value", Type: AnyType, Optional: true}},
+	}
+	args, err := count.Arguments([]interface{}{1, 2})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{[]interface{}{1, 2}, nil}, args, "items slice passed as the only argument")
+	args, err = count.Arguments([]interface{}{[]interface{}{1, 2}, 2})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{[]interface{}{1, 2}, 2}, args, "items slice passed as the first argument")
+	args, err = count.Arguments([]interface{}{[]interface{}{1, 2}})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{[]interface{}{[]interface{}{1, 2}}, nil}, args, "single nested slice passed as items")
+	args, err = count.Arguments([]interface{}{[]interface{}{1, 2}, []interface{}{3}})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{[]interface{}{[]interface{}{1, 2}, []interface{}{3}}, nil}, args, "slice of slices passed as items")
+
+	at := &Definition{Name: "At", Params: []*Param{{Name: "time", Type: TimeType}}}
+	for _, literal := range []string{"2024-03-01T10:20:30Z", "2024-03-01 10:20:30", "2024-03-01T10:20:30", "2024-03-01"} {
+		args, err := at.Arguments(literal)
+		if assert.Nil(t, err, literal) {
+			assert.EqualValues(t, "2024-03-01", args[0].(time.Time).Format("2006-01-02"), literal)
+		}
+	}
+	_, err = at.Arguments("01/03/2024")
+	assert.NotNil(t, err)
+}
+
+func TestDefinition_Validate(t *testing.T) {
+	handler := func(args []interface{}, state data.Map) (interface{}, error) { return nil, nil }
+	var useCases = []struct {
+		definition  *Definition
+		expectError string
+	}{
+		{definition: &Definition{Handler: handler}, expectError: "udf name was empty"},
+		{definition: &Definition{Name: "F"}, expectError: "F handler was empty"},
+		{definition: &Definition{Name: "F", Handler: handler, Params: []*Param{{Name: "a", Variadic: true}, {Name: "b"}}}, expectError: "F variadic param a has to be the last one"},
+		{definition: &Definition{Name: "F", Handler: handler, Params: []*Param{{Name: "a", Optional: true}, {Name: "b"}}}, expectError: "F param b has to be optional as it follows optional param"},
+	}
+	for _, useCase := range useCases {
+		assert.EqualError(t, useCase.definition.Validate(), useCase.expectError)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := NewRegistry(replaceUDF, &Definition{Name: "Upper", Aliases: []string{"ToUpperCase"}, Params: []*Param{{Name: "text", Type: StringType}}, Handler: unary(ToUpper)})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualError(t, registry.Register(joinUDF, replaceUDF), "udf Replace was already registered")
+	assert.NotNil(t, registry.Definition("ToUpperCase"))
+	var names = make([]string, 0)
+	for _, definition := range registry.Definitions() {
+		names = append(names, definition.Name)
+	}
+	assert.EqualValues(t, []string{"Join", "Replace", "Upper"}, names)
+
+	state := data.NewMap()
+	state.Put("text", "abc")
+	state.Put(data.UDFKey, registry.Map())
+	assert.EqualValues(t, "ABC xbc", state.ExpandAsText("$ToUpperCase($text) $Replace($text, a, x)"))
+	_, err = state.ExpandAsTextStrict("$Replace($text, a)")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Replace(text string, old string, new string): expected 3 argument(s), but had 2")
+	}
+}
+
+func TestPredefined(t *testing.T) {
+	for _, definition := range Default.Definitions() {
+		assert.NotEmpty(t, definition.Description, definition.Name)
+		assert.NotNil(t, Predefined[definition.Name], definition.Name)
+	}
+	assert.NotNil(t, Predefined["Len"])
+	assert.EqualValues(t, 67, len(Predefined))
+}
+
+func toStrings(values []interface{}) []string {
+	var result = make([]string, len(values))
+	for i, value := range values {
+		result[i] = value.(string)
+	}
+	return result
+}
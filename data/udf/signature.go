@@ -0,0 +1,272 @@
+package udf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+// ParamType represents UDF parameter type, arguments are converted to the type before UDF call
+type ParamType string
+
+const (
+	// AnyType passes argument as is
+	AnyType ParamType = "any"
+	// StringType converts argument to string
+	StringType ParamType = "string"
+	// IntType converts argument to int
+	IntType ParamType = "int"
+	// FloatType converts argument to float64
+	FloatType ParamType = "float"
+	// NumberType converts argument to int or float64 if it has a fraction
+	NumberType ParamType = "number"
+	// BoolType converts argument to bool
+	BoolType ParamType = "bool"
+	// MapType converts argument, including JSON or YAML literal, to map[string]interface{}
+	MapType ParamType = "map"
+	// SliceType converts argument, including JSON or YAML literal, to []interface{}
+	SliceType ParamType = "slice"
+	// TimeType converts argument, including time expression (i.e. now, 2 days ago), RFC3339 or yyyy-MM-dd [HH:mm:ss] literal, to time.Time
+	TimeType ParamType = "time"
+)
+
+// Param represents UDF parameter
+type Param struct {
+	Name     string
+	Type     ParamType
+	Optional bool        //optional parameter can be omitted, all following parameters have to be optional
+	Default  interface{} //default value of omitted optional parameter, nil if not specified
+	Variadic bool        //variadic parameter takes all remaining arguments, it has to be the last one
+}
+
+// String returns parameter description, i.e. items ...string
+func (p *Param) String() string {
+	paramType := string(p.Type)
+	if p.Variadic {
+		paramType = "..." + paramType
+	}
+	if p.Optional {
+		return "[" + p.Name + " " + paramType + "]"
+	}
+	return p.Name + " " + paramType
+}
+
+// Func represents typed UDF handler, it receives validated arguments converted to parameters type,
+// omitted optional arguments are set to their defaults, variadic arguments are appended
+type Func func(args []interface{}, state data.Map) (interface{}, error)
+
+// Definition represents UDF signature with its handler
+type Definition struct {
+	Name    string
+	Aliases []string
+	Params  []*Param
+	Doc
+	Handler Func
+}
+
+// Signature returns UDF signature, i.e. Replace(text string, old string, new string)
+func (d *Definition) Signature() string {
+	var params = make([]string, len(d.Params))
+	for i, param := range d.Params {
+		params[i] = param.String()
+	}
+	return d.Name + "(" + strings.Join(params, ", ") + ")"
+}
+
+// Validate checks definition name, handler and parameters order
+func (d *Definition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("udf name was empty")
+	}
+	if d.Handler == nil {
+		return fmt.Errorf("%v handler was empty", d.Name)
+	}
+	optional := false
+	for i, param := range d.Params {
+		if param.Name == "" {
+			return fmt.Errorf("%v param[%d] name was empty", d.Name, i)
+		}
+		if param.Variadic && i != len(d.Params)-1 {
+			return fmt.Errorf("%v variadic param %v has to be the last one", d.Name, param.Name)
+		}
+		if optional && !param.Optional && !param.Variadic {
+			return fmt.Errorf("%v param %v has to be optional as it follows optional param", d.Name, param.Name)
+		}
+		optional = optional || param.Optional
+	}
+	return nil
+}
+
+// UDF returns data.Map compatible UDF validating and converting arguments before calling the handler
+func (d *Definition) UDF() func(interface{}, data.Map) (interface{}, error) {
+	return d.Call
+}
+
+// Call validates and converts source arguments and calls the handler, a UDF with more than one parameter expects arguments slice,
+// if the first parameter is a slice, a slice source is the arguments slice only if it has at least two elements and the first one is the only slice,
+// otherwise the whole source is used as the first argument, i.e. [[3, 1], "key"] are arguments, but [3, 1], [[3, 1]] and [[3, 1], [2]] are items
+func (d *Definition) Call(source interface{}, state data.Map) (interface{}, error) {
+	args, err := d.Arguments(source)
+	if err != nil {
+		return nil, err
+	}
+	return d.Handler(args, state)
+}
+
+// Arguments returns validated arguments converted to parameters type
+func (d *Definition) Arguments(source interface{}) ([]interface{}, error) {
+	var args []interface{}
+	switch {
+	case len(d.Params) == 1 && !d.Params[0].Variadic:
+		args = []interface{}{source}
+	case source == nil:
+		args = []interface{}{}
+	case toolbox.IsSlice(source) && !isByteSlice(source):
+		args = toolbox.AsSlice(source)
+		if len(d.Params) > 0 && d.Params[0].Type == SliceType && !isArgumentsSlice(args) {
+			args = []interface{}{source} //items slice passed as the only argument
+		}
+	case source == "":
+		args = []interface{}{}
+	default:
+		args = []interface{}{source}
+	}
+	required, max := d.arity()
+	if len(args) < required || (max != -1 && len(args) > max) {
+		return nil, fmt.Errorf("%v: expected %v argument(s), but had %v", d.Signature(), d.arityText(required, max), len(args))
+	}
+	var result = make([]interface{}, 0, len(args))
+	for i, param := range d.Params {
+		if param.Variadic {
+			for j := i; j < len(args); j++ {
+				value, err := convertArgument(param, args[j])
+				if err != nil {
+					return nil, fmt.Errorf("%v: invalid %v argument[%d]: %v", d.Signature(), param.Name, j-i, err)
+				}
+				result = append(result, value)
+			}
+			break
+		}
+		if i >= len(args) {
+			result = append(result, param.Default)
+			continue
+		}
+		value, err := convertArgument(param, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("%v: invalid %v argument: %v", d.Signature(), param.Name, err)
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+// isArgumentsSlice returns true if slice source of UDF with slice first parameter lists arguments rather than items
+func isArgumentsSlice(args []interface{}) bool {
+	if len(args) < 2 || !toolbox.IsSlice(args[0]) {
+		return false
+	}
+	for _, arg := range args[1:] {
+		if toolbox.IsSlice(arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// arity returns required and max number of arguments, max is -1 for variadic UDF
+func (d *Definition) arity() (int, int) {
+	required := 0
+	for _, param := range d.Params {
+		if param.Variadic {
+			return required, -1
+		}
+		if !param.Optional {
+			required++
+		}
+	}
+	return required, len(d.Params)
+}
+
+func (d *Definition) arityText(required, max int) string {
+	switch {
+	case max == -1:
+		return fmt.Sprintf("at least %d", required)
+	case required == max:
+		return fmt.Sprintf("%d", required)
+	}
+	return fmt.Sprintf("%d to %d", required, max)
+}
+
+func isByteSlice(source interface{}) bool {
+	_, ok := source.([]byte)
+	return ok
+}
+
+func convertArgument(param *Param, value interface{}) (interface{}, error) {
+	switch param.Type {
+	case StringType:
+		if value == nil {
+			return "", nil
+		}
+		if toolbox.IsMap(value) || (toolbox.IsSlice(value) && !isByteSlice(value)) {
+			return nil, fmt.Errorf("expected %v, but had %T", param.Type, value)
+		}
+		return toolbox.AsString(value), nil
+	case IntType:
+		return toolbox.ToInt(value)
+	case FloatType:
+		return toolbox.ToFloat(value)
+	case NumberType:
+		floatValue, err := toolbox.ToFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		if float64(int(floatValue)) == floatValue {
+			return int(floatValue), nil
+		}
+		return floatValue, nil
+	case BoolType:
+		return toolbox.ToBoolean(value)
+	case MapType:
+		aMap, err := AsMap(value, nil)
+		if err != nil {
+			return nil, err
+		}
+		if aMap == nil || !toolbox.IsMap(aMap) {
+			return nil, fmt.Errorf("expected %v, but had %T", param.Type, value)
+		}
+		return toolbox.AsMap(aMap), nil
+	case SliceType:
+		aSlice, err := AsCollection(value, nil)
+		if err != nil {
+			return nil, err
+		}
+		if aSlice == nil || !toolbox.IsSlice(aSlice) {
+			return nil, fmt.Errorf("expected %v, but had %T", param.Type, value)
+		}
+		return toolbox.AsSlice(aSlice), nil
+	case TimeType:
+		if timeValue, ok := value.(time.Time); ok {
+			return timeValue, nil
+		}
+		if timeValue, err := toolbox.TimeAt(toolbox.AsString(value)); err == nil {
+			return *timeValue, nil
+		}
+		timeValue, err := toolbox.ToTime(value, time.RFC3339)
+		if err == nil {
+			return *timeValue, nil
+		}
+		for _, layout := range timeLayouts {
+			if parsed, parseErr := time.Parse(layout, toolbox.AsString(value)); parseErr == nil {
+				return parsed, nil
+			}
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+var timeLayouts = []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
@@ -0,0 +1,176 @@
+package udf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/viant/toolbox/data"
+)
+
+// PadLeft pads text on the left with pad (space by default) up to supplied length
+func PadLeft(source interface{}, state data.Map) (interface{}, error) {
+	return padLeftUDF.Call(source, state)
+}
+
+var padLeftUDF = &Definition{
+	Name:    "PadLeft",
+	Params:  []*Param{{Name: "text", Type: StringType}, {Name: "length", Type: IntType}, {Name: "pad", Type: StringType, Optional: true, Default: " "}},
+	Doc:     Doc{Description: "pads text on the left with pad up to length", Example: "$PadLeft($id, 8, 0)"},
+	Handler: func(args []interface{}, state data.Map) (interface{}, error) { return pad(args, true) },
+}
+
+// PadRight pads text on the right with pad (space by default) up to supplied length
+func PadRight(source interface{}, state data.Map) (interface{}, error) {
+	return padRightUDF.Call(source, state)
+}
+
+var padRightUDF = &Definition{
+	Name:    "PadRight",
+	Params:  []*Param{{Name: "text", Type: StringType}, {Name: "length", Type: IntType}, {Name: "pad", Type: StringType, Optional: true, Default: " "}},
+	Doc:     Doc{Description: "pads text on the right with pad up to length", Example: "$PadRight($name, 10, .)"},
+	Handler: func(args []interface{}, state data.Map) (interface{}, error) { return pad(args, false) },
+}
+
+func pad(args []interface{}, left bool) (interface{}, error) {
+	text, length, padding := args[0].(string), args[1].(int), args[2].(string)
+	if padding == "" {
+		return nil, fmt.Errorf("pad was empty")
+	}
+	missing := length - len([]rune(text))
+	if missing <= 0 {
+		return text, nil
+	}
+	fill := []rune(strings.Repeat(padding, missing/len([]rune(padding))+1))[:missing]
+	if left {
+		return string(fill) + text, nil
+	}
+	return text + string(fill), nil
+}
+
+// Substring returns text fragment between from and optional to rune index, negative index is counted from the end
+func Substring(source interface{}, state data.Map) (interface{}, error) {
+	return substringUDF.Call(source, state)
+}
+
+var substringUDF = &Definition{
+	Name:    "Substring",
+	Params:  []*Param{{Name: "text", Type: StringType}, {Name: "from", Type: IntType}, {Name: "to", Type: IntType, Optional: true}},
+	Doc:     Doc{Description: "returns text fragment between from and optional to index, negative index is counted from the end", Example: "$Substring($name, 0, 3)"},
+	Handler: substring,
+}
+
+func substring(args []interface{}, state data.Map) (interface{}, error) {
+	runes := []rune(args[0].(string))
+	from, to := sliceRange(len(runes), args[1].(int), args[2])
+	return string(runes[from:to]), nil
+}
+
+// sliceRange returns from and to index clamped to length, negative index is counted from the end, nil to means length
+func sliceRange(length, from int, to interface{}) (int, int) {
+	var end = length
+	if to != nil {
+		end = to.(int)
+	}
+	clamp := func(index int) int {
+		if index < 0 {
+			index += length
+		}
+		if index < 0 {
+			return 0
+		}
+		if index > length {
+			return length
+		}
+		return index
+	}
+	from, end = clamp(from), clamp(end)
+	if end < from {
+		end = from
+	}
+	return from, end
+}
+
+// Matches returns true if text matches regular expression
+func Matches(source interface{}, state data.Map) (interface{}, error) {
+	return matchesUDF.Call(source, state)
+}
+
+var matchesUDF = &Definition{
+	Name:    "Matches",
+	Params:  []*Param{{Name: "text", Type: StringType}, {Name: "pattern", Type: StringType}},
+	Doc:     Doc{Description: "returns true if text matches regular expression", Example: "$Matches($email, \"^[^@]+@[^@]+$\")"},
+	Handler: matches,
+}
+
+func matches(args []interface{}, state data.Map) (interface{}, error) {
+	expression, err := compileExpression(args[1].(string))
+	if err != nil {
+		return nil, err
+	}
+	return expression.MatchString(args[0].(string)), nil
+}
+
+// RegexReplace replaces all regular expression matches with replacement, replacement can use $1 style submatch references
+func RegexReplace(source interface{}, state data.Map) (interface{}, error) {
+	return regexReplaceUDF.Call(source, state)
+}
+
+var regexReplaceUDF = &Definition{
+	Name:    "RegexReplace",
+	Params:  []*Param{{Name: "text", Type: StringType}, {Name: "pattern", Type: StringType}, {Name: "replacement", Type: StringType}},
+	Doc:     Doc{Description: "replaces all regular expression matches with replacement, ${1} refers to submatch", Example: "$RegexReplace($text, \"[0-9]+\", \"#\")"},
+	Handler: regexReplace,
+}
+
+func regexReplace(args []interface{}, state data.Map) (interface{}, error) {
+	expression, err := compileExpression(args[1].(string))
+	if err != nil {
+		return nil, err
+	}
+	return expression.ReplaceAllString(args[0].(string), args[2].(string)), nil
+}
+
+// maxCompiledExpressions limits regular expressions cache, the cache is reset once the limit is reached
+const maxCompiledExpressions = 256
+
+var compiledExpressions = make(map[string]*regexp.Regexp)
+var compiledExpressionsMux sync.RWMutex
+
+// compileExpression returns cached compiled regular expression
+func compileExpression(pattern string) (*regexp.Regexp, error) {
+	compiledExpressionsMux.RLock()
+	expression, ok := compiledExpressions[pattern]
+	compiledExpressionsMux.RUnlock()
+	if ok {
+		return expression, nil
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compiledExpressionsMux.Lock()
+	defer compiledExpressionsMux.Unlock()
+	if len(compiledExpressions) >= maxCompiledExpressions {
+		compiledExpressions = make(map[string]*regexp.Regexp)
+	}
+	compiledExpressions[pattern] = expression
+	return expression, nil
+}
+
+// Sprintf returns text formatted with fmt.Sprintf
+func Sprintf(source interface{}, state data.Map) (interface{}, error) {
+	return sprintfUDF.Call(source, state)
+}
+
+var sprintfUDF = &Definition{
+	Name:    "Sprintf",
+	Params:  []*Param{{Name: "format", Type: StringType}, {Name: "args", Type: AnyType, Variadic: true}},
+	Doc:     Doc{Description: "formats arguments with fmt.Sprintf format", Example: "$Sprintf(\"%05d-%v\", $id, $name)"},
+	Handler: sprintf,
+}
+
+func sprintf(args []interface{}, state data.Map) (interface{}, error) {
+	return fmt.Sprintf(args[0].(string), args[1:]...), nil
+}
@@ -0,0 +1,95 @@
+package udf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/data"
+)
+
+func Test_Pad(t *testing.T) {
+	{
+		value, err := PadLeft([]interface{}{"12", 5, "0"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "00012", value)
+	}
+	{
+		value, err := PadRight([]interface{}{"ab", "6", "xy"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "abxyxy", value)
+	}
+	{
+		value, err := PadLeft([]interface{}{"abc", 2}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "abc", value)
+	}
+	{
+		_, err := PadLeft([]interface{}{"abc", "x"}, nil)
+		assert.NotNil(t, err)
+	}
+}
+
+func Test_Substring(t *testing.T) {
+	{
+		value, err := Substring([]interface{}{"endly", 1, 3}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "nd", value)
+	}
+	{
+		value, err := Substring([]interface{}{"endly", -2}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "ly", value)
+	}
+	{
+		value, err := Substring([]interface{}{"żółw", 1, 100}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "ółw", value)
+	}
+}
+
+func Test_Matches(t *testing.T) {
+	{
+		value, err := Matches([]interface{}{"abc@test.com", "^[^@]+@[^@]+$"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, true, value)
+	}
+	{
+		value, err := Matches([]interface{}{"abc", "[0-9]"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, false, value)
+	}
+	{
+		_, err := Matches([]interface{}{"abc", "[0-9"}, nil)
+		assert.NotNil(t, err)
+	}
+}
+
+func Test_RegexReplace(t *testing.T) {
+	value, err := RegexReplace([]interface{}{"id: 123, code: 45", "([a-z]+): ([0-9]+)", "${1}=${2}"}, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "id=123, code=45", value)
+}
+
+func Test_compileExpression(t *testing.T) {
+	expression, err := compileExpression("^[a-z]+$")
+	assert.Nil(t, err)
+	cached, err := compileExpression("^[a-z]+$")
+	assert.Nil(t, err)
+	assert.True(t, expression == cached)
+	_, err = compileExpression("[a-z")
+	assert.NotNil(t, err)
+}
+
+func Test_Sprintf(t *testing.T) {
+	{
+		value, err := Sprintf([]interface{}{"%05d-%v", 12, "abc"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "00012-abc", value)
+	}
+	{
+		aMap := data.NewMap()
+		aMap.Put("id", 7)
+		Register(aMap)
+		assert.EqualValues(t, "item-007", aMap.ExpandAsText(`$Sprintf("item-%03d", $id)`))
+	}
+}
@@ -10,29 +10,38 @@ import (
 
 // FormatTime return formatted time, it takes an array of arguments, the first is  time express, or now followed by java style time format, optional timezone and truncate format .
 func FormatTime(source interface{}, state data.Map) (interface{}, error) {
-	if !toolbox.IsSlice(source) {
-		return nil, fmt.Errorf("unable to run FormatTime: expected %T, but had: %T", []interface{}{}, source)
-	}
-	aSlice := toolbox.AsSlice(source)
-	if len(aSlice) < 2 {
-		return nil, fmt.Errorf("unable to run FormatTime, expected 2 parameters, but had: %v", len(aSlice))
-	}
+	return formatTimeUDF.Call(source, state)
+}
+
+var formatTimeUDF = &Definition{
+	Name: "FormatTime",
+	Params: []*Param{
+		{Name: "time", Type: AnyType},
+		{Name: "format", Type: StringType},
+		{Name: "timezone", Type: StringType, Optional: true, Default: ""},
+		{Name: "truncate", Type: StringType, Optional: true, Default: ""},
+	},
+	Doc:     Doc{Description: "formats time expression or now with java style format, truncate can be a format or weekday", Example: "$FormatTime(now, \"yyyy-MM-dd\", UTC)"},
+	Handler: formatTime,
+}
+
+func formatTime(args []interface{}, state data.Map) (interface{}, error) {
 	var err error
-	var timeText = toolbox.AsString(aSlice[0])
+	var timeText = toolbox.AsString(args[0])
+	var timeFormat = args[1].(string)
 	var timeValue *time.Time
 	timeValue, err = toolbox.TimeAt(timeText)
 	var timeLayout string
 	if err != nil {
-		timeFormat := toolbox.AsString(aSlice[1])
 		timeLayout = toolbox.DateFormatToLayout(timeFormat)
-		timeValue, err = toolbox.ToTime(aSlice[0], timeLayout)
+		timeValue, err = toolbox.ToTime(args[0], timeLayout)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	if len(aSlice) > 2 && aSlice[2] != "" {
-		timeLocation, err := time.LoadLocation(toolbox.AsString(aSlice[2]))
+	if timezone := args[2].(string); timezone != "" {
+		timeLocation, err := time.LoadLocation(timezone)
 		if err != nil {
 			return nil, err
 		}
@@ -40,30 +49,24 @@ func FormatTime(source interface{}, state data.Map) (interface{}, error) {
 		timeValue = &timeInLocation
 	}
 
-	if len(aSlice) > 1 {
-		formatArg := toolbox.AsString(aSlice[1])
-		switch strings.Trim(formatArg, `" ,`) {
-		case "secondsOfDay":
-			return timeValue.Hour()*60*60 + timeValue.Minute()*60 + timeValue.Second(), nil
-		}
-		if timeLayout == "" {
-			timeFormat := toolbox.AsString(aSlice[1])
-			timeLayout = toolbox.DateFormatToLayout(timeFormat)
-		}
+	switch strings.Trim(timeFormat, `" ,`) {
+	case "secondsOfDay":
+		return timeValue.Hour()*60*60 + timeValue.Minute()*60 + timeValue.Second(), nil
+	}
+	if timeLayout == "" {
+		timeLayout = toolbox.DateFormatToLayout(timeFormat)
 	}
 
-	if len(aSlice) > 3 {
-		switch aSlice[3] {
-		case "weekday":
-			return timeValue.Weekday(), nil
-		default:
-			truncFromat := toolbox.DateFormatToLayout(toolbox.AsString(aSlice[3]))
-			if ts, err := time.Parse(truncFromat, timeValue.Format(truncFromat)); err == nil {
-				timeValue = &ts
-			}
+	switch truncate := args[3].(string); truncate {
+	case "":
+	case "weekday":
+		return timeValue.Weekday(), nil
+	default:
+		truncFromat := toolbox.DateFormatToLayout(truncate)
+		if ts, err := time.Parse(truncFromat, timeValue.Format(truncFromat)); err == nil {
+			timeValue = &ts
 		}
 	}
-
 	return timeValue.Format(timeLayout), nil
 }
 
@@ -93,3 +96,78 @@ func Elapsed(source interface{}, state data.Map) (interface{}, error) {
 	return result, nil
 
 }
+
+// TimeAdd returns time shifted by duration, i.e. 1h30m, -2 days, 3 weeks, formatted with optional java style format or RFC3339
+func TimeAdd(source interface{}, state data.Map) (interface{}, error) {
+	return timeAddUDF.Call(source, state)
+}
+
+var timeAddUDF = &Definition{
+	Name:    "TimeAdd",
+	Params:  []*Param{{Name: "time", Type: TimeType}, {Name: "duration", Type: StringType}, {Name: "format", Type: StringType, Optional: true, Default: ""}},
+	Doc:     Doc{Description: "returns time shifted by duration (i.e. 1h30m, -2 days), formatted with optional java style format or RFC3339", Example: "$TimeAdd(now, \"-3 days\", \"yyyy-MM-dd\")"},
+	Handler: timeAdd,
+}
+
+func timeAdd(args []interface{}, state data.Map) (interface{}, error) {
+	duration, err := parseDuration(args[1].(string))
+	if err != nil {
+		return nil, err
+	}
+	timeValue := args[0].(time.Time).Add(duration)
+	layout := time.RFC3339
+	if format := args[2].(string); format != "" {
+		layout = toolbox.DateFormatToLayout(format)
+	}
+	return timeValue.Format(layout), nil
+}
+
+// parseDuration parses go duration or value with time unit, i.e. 3 days, -1 week, 2d
+func parseDuration(expression string) (time.Duration, error) {
+	expression = strings.TrimSpace(expression)
+	if duration, err := time.ParseDuration(expression); err == nil {
+		return duration, nil
+	}
+	index := strings.IndexFunc(expression, func(r rune) bool {
+		return !(r == '-' || r == '+' || (r >= '0' && r <= '9'))
+	})
+	if index <= 0 {
+		return 0, fmt.Errorf("invalid duration: %v", expression)
+	}
+	value, err := toolbox.ToInt(expression[:index])
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %v, %v", expression, err)
+	}
+	unit := strings.ToLower(strings.TrimSpace(expression[index:]))
+	switch unit {
+	case "d":
+		unit = toolbox.DurationDay
+	case "w":
+		unit = toolbox.DurationWeek
+	}
+	if len(unit) > 2 {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+	return toolbox.NewDuration(value, unit)
+}
+
+// TimeDiff returns (to - from) time difference in supplied unit: ms, sec (default), min, hour, day or week
+func TimeDiff(source interface{}, state data.Map) (interface{}, error) {
+	return timeDiffUDF.Call(source, state)
+}
+
+var timeDiffUDF = &Definition{
+	Name:    "TimeDiff",
+	Params:  []*Param{{Name: "from", Type: TimeType}, {Name: "to", Type: TimeType}, {Name: "unit", Type: StringType, Optional: true, Default: toolbox.DurationSecondAbbr}},
+	Doc:     Doc{Description: "returns (to - from) time difference in unit: ms, sec (default), min, hour, day or week", Example: "$TimeDiff($created, now, day)"},
+	Handler: timeDiff,
+}
+
+func timeDiff(args []interface{}, state data.Map) (interface{}, error) {
+	unit, err := parseDuration("1 " + args[2].(string))
+	if err != nil {
+		return nil, err
+	}
+	elapsed := args[1].(time.Time).Sub(args[0].(time.Time))
+	return AsNumber(float64(elapsed)/float64(unit), state)
+}
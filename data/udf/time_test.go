@@ -66,3 +66,43 @@ func TestElapsed(t *testing.T) {
 		assert.Equal(t, "1h0s", value)
 	}
 }
+
+func Test_TimeAdd(t *testing.T) {
+	{
+		value, err := TimeAdd([]interface{}{"2015-02-11", "-3 days", "yyyy-MM-dd"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "2015-02-08", value)
+	}
+	{
+		value, err := TimeAdd([]interface{}{"2015-02-11T10:00:00Z", "1h30m"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "2015-02-11T11:30:00Z", value)
+	}
+	{
+		value, err := TimeAdd([]interface{}{"2015-02-11 10:00:00", "2w", "yyyy-MM-dd HH:mm"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "2015-02-25 10:00", value)
+	}
+	{
+		_, err := TimeAdd([]interface{}{"2015-02-11", "3 fortnights"}, nil)
+		assert.NotNil(t, err)
+	}
+}
+
+func Test_TimeDiff(t *testing.T) {
+	{
+		value, err := TimeDiff([]interface{}{"2015-02-11", "2015-02-14", "day"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, value)
+	}
+	{
+		value, err := TimeDiff([]interface{}{"2015-02-11T10:00:00Z", "2015-02-11T10:01:30Z"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 90, value)
+	}
+	{
+		value, err := TimeDiff([]interface{}{"2015-02-11T10:00:00Z", "2015-02-11T09:30:00Z", "hours"}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, -0.5, value)
+	}
+}
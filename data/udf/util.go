@@ -33,52 +33,52 @@ func Length(source interface{}, state data.Map) (interface{}, error) {
 
 // Replace replaces text with old and new fragments
 func Replace(source interface{}, state data.Map) (interface{}, error) {
-	var args []interface{}
-	if !toolbox.IsSlice(source) {
-		return nil, fmt.Errorf("expacted %T, but had %T", args, source)
-	}
-	args = toolbox.AsSlice(source)
-	if len(args) < 3 {
-		return nil, fmt.Errorf("expected 3 arguments (text, old, new), but had: %v", len(args))
-	}
-	text := toolbox.AsString(args[0])
-	old := toolbox.AsString(args[1])
-	new := toolbox.AsString(args[2])
-	count := strings.Count(text, old)
-	return strings.Replace(text, old, new, count), nil
+	return replaceUDF.Call(source, state)
+}
+
+var replaceUDF = &Definition{
+	Name:    "Replace",
+	Params:  []*Param{{Name: "text", Type: StringType}, {Name: "old", Type: StringType}, {Name: "new", Type: StringType}},
+	Doc:     Doc{Description: "replaces all old fragments with new in text", Example: "$Replace($text, old, new)"},
+	Handler: replace,
+}
+
+func replace(args []interface{}, state data.Map) (interface{}, error) {
+	return strings.Replace(args[0].(string), args[1].(string), args[2].(string), -1), nil
 }
 
 // Join joins slice by separator
 func Join(args interface{}, state data.Map) (interface{}, error) {
-	if !toolbox.IsSlice(args) {
-		return nil, fmt.Errorf("expected 2 arguments but had: %T", args)
-	}
-	arguments := toolbox.AsSlice(args)
-	if len(arguments) != 2 {
-		return nil, fmt.Errorf("expected 2 arguments but had: %v", len(arguments))
-	}
+	return joinUDF.Call(args, state)
+}
 
-	if !toolbox.IsSlice(arguments[0]) {
-		return nil, fmt.Errorf("expected 1st arguments as slice but had: %T", arguments[0])
-	}
+var joinUDF = &Definition{
+	Name:    "Join",
+	Params:  []*Param{{Name: "items", Type: SliceType}, {Name: "separator", Type: StringType}},
+	Doc:     Doc{Description: "joins slice elements with separator", Example: "$Join($items, \",\")"},
+	Handler: join,
+}
+
+func join(args []interface{}, state data.Map) (interface{}, error) {
 	var result = make([]string, 0)
-	toolbox.CopySliceElements(arguments[0], &result)
-	return strings.Join(result, toolbox.AsString(arguments[1])), nil
+	toolbox.CopySliceElements(args[0], &result)
+	return strings.Join(result, args[1].(string)), nil
 }
 
 // Split split text to build a slice
 func Split(args interface{}, state data.Map) (interface{}, error) {
-	if !toolbox.IsSlice(args) {
-		return nil, fmt.Errorf("expected 2 arguments but had: %T", args)
-	}
-	arguments := toolbox.AsSlice(args)
-	if len(arguments) != 2 {
-		return nil, fmt.Errorf("expected 2 arguments but had: %v", len(arguments))
-	}
-	if !toolbox.IsString(arguments[0]) {
-		return nil, fmt.Errorf("expected 1st arguments as string but had: %T", arguments[0])
-	}
-	result := strings.Split(toolbox.AsString(arguments[0]), toolbox.AsString(arguments[1]))
+	return splitUDF.Call(args, state)
+}
+
+var splitUDF = &Definition{
+	Name:    "Split",
+	Params:  []*Param{{Name: "text", Type: StringType}, {Name: "separator", Type: StringType}},
+	Doc:     Doc{Description: "splits text by separator, elements are trimmed", Example: "$Split($text, \",\")"},
+	Handler: split,
+}
+
+func split(args []interface{}, state data.Map) (interface{}, error) {
+	result := strings.Split(args[0].(string), args[1].(string))
 	for i := range result {
 		result[i] = strings.TrimSpace(result[i])
 	}
@@ -138,14 +138,17 @@ func Values(source interface{}, state data.Map) (interface{}, error) {
 
 // IndexOf returns index of the matched slice elements or -1
 func IndexOf(source interface{}, state data.Map) (interface{}, error) {
-	if !toolbox.IsSlice(source) {
-		return nil, fmt.Errorf("expected arguments but had: %T", source)
-	}
-	args := toolbox.AsSlice(source)
-	if len(args) != 2 {
-		return nil, fmt.Errorf("expected 2 arguments but had: %v", len(args))
-	}
+	return indexOfUDF.Call(source, state)
+}
 
+var indexOfUDF = &Definition{
+	Name:    "IndexOf",
+	Params:  []*Param{{Name: "source", Type: AnyType}, {Name: "item", Type: AnyType}},
+	Doc:     Doc{Description: "returns index of item in text or slice or -1", Example: "$IndexOf($items, abc)"},
+	Handler: indexOf,
+}
+
+func indexOf(args []interface{}, state data.Map) (interface{}, error) {
 	if toolbox.IsString(args[0]) {
 		return strings.Index(toolbox.AsString(args[0]), toolbox.AsString(args[1])), nil
 	}
@@ -286,17 +289,22 @@ func Sum(xPath interface{}, state data.Map) (interface{}, error) {
 
 // Select returns all matched attributes from matched nodes, attributes can be alised with sourcePath:alias
 func Select(params interface{}, state data.Map) (interface{}, error) {
-	var arguments = make([]interface{}, 0)
-	if toolbox.IsSlice(params) {
-		arguments = toolbox.AsSlice(params)
-	} else {
-		arguments = append(arguments, params)
-	}
-	xPath := toolbox.AsString(arguments[0])
+	return selectUDF.Call(params, state)
+}
+
+var selectUDF = &Definition{
+	Name:    "Select",
+	Params:  []*Param{{Name: "path", Type: StringType}, {Name: "attributes", Type: StringType, Variadic: true}},
+	Doc:     Doc{Description: "selects attributes for matched path, attributes can be aliased with sourcePath:alias", Example: "$Select(\"node1/obj/*\", \"id\", \"name:product\")"},
+	Handler: selectAttributes,
+}
+
+func selectAttributes(args []interface{}, state data.Map) (interface{}, error) {
+	xPath := args[0].(string)
 	var result = make([]interface{}, 0)
 	attributes := make([]string, 0)
-	for i := 1; i < len(arguments); i++ {
-		attributes = append(attributes, toolbox.AsString(arguments[i]))
+	for i := 1; i < len(args); i++ {
+		attributes = append(attributes, args[i].(string))
 	}
 	err := matchPath(xPath, state, func(matched interface{}) error {
 		if len(attributes) == 0 {
@@ -483,43 +491,55 @@ func matchPath(xPath string, state data.Map, handler func(value interface{}) err
 	return nil
 }
 
-// Rand returns random
+// Rand returns random float or int within optional min and max range
 func Rand(params interface{}, state data.Map) (interface{}, error) {
+	return randUDF.Call(params, state)
+}
+
+var randUDF = &Definition{
+	Name:    "Rand",
+	Params:  []*Param{{Name: "min", Type: IntType, Optional: true}, {Name: "max", Type: IntType, Optional: true}},
+	Doc:     Doc{Description: "returns random float, or int if min and max are specified", Example: "$Rand(1, 10)"},
+	Handler: random,
+}
+
+func random(args []interface{}, state data.Map) (interface{}, error) {
 	source := rand.NewSource(time.Now().UnixNano())
 	generator := rand.New(source)
 	floatValue := generator.Float64()
-	if params == nil || !toolbox.IsSlice(params) {
-		return floatValue, nil
-	}
-	parameters := toolbox.AsSlice(params)
-	if len(parameters) != 2 {
+	if args[0] == nil || args[1] == nil {
 		return floatValue, nil
 	}
-	min := toolbox.AsInt(parameters[0])
-	max := toolbox.AsInt(parameters[1])
+	min := args[0].(int)
+	max := args[1].(int)
 	return min + int(float64(max-min)*floatValue), nil
 }
 
 // Concat concatenate supplied parameters, parameters
 func Concat(params interface{}, state data.Map) (interface{}, error) {
-	if params == nil || !toolbox.IsSlice(params) {
-		return nil, fmt.Errorf("invalid signature, expected: $Concat(arrayOrItem1, arrayOrItem2)")
-	}
+	return concatUDF.Call(params, state)
+}
+
+var concatUDF = &Definition{
+	Name:    "Concat",
+	Params:  []*Param{{Name: "items", Type: AnyType, Variadic: true}},
+	Doc:     Doc{Description: "concatenates strings if the first item is a string, otherwise items and slices elements into a slice", Example: "$Concat($array1, $array2)"},
+	Handler: concat,
+}
+
+func concat(args []interface{}, state data.Map) (interface{}, error) {
 	var result = make([]interface{}, 0)
-	parameters := toolbox.AsSlice(params)
-	if len(parameters) == 0 {
+	if len(args) == 0 {
 		return result, nil
 	}
-
-	if toolbox.IsString(parameters[0]) {
+	if toolbox.IsString(args[0]) {
 		result := ""
-		for _, item := range parameters {
+		for _, item := range args {
 			result += toolbox.AsString(item)
 		}
 		return result, nil
 	}
-
-	for _, item := range parameters {
+	for _, item := range args {
 		if toolbox.IsSlice(item) {
 			itemSlice := toolbox.AsSlice(item)
 			result = append(result, itemSlice...)
@@ -532,16 +552,20 @@ func Concat(params interface{}, state data.Map) (interface{}, error) {
 
 // Merge creates a new merged map for supplied maps,  (mapOrPath1, mapOrPath2, mapOrPathN)
 func Merge(params interface{}, state data.Map) (interface{}, error) {
-	if params == nil || !toolbox.IsSlice(params) {
-		return nil, fmt.Errorf("invalid signature, expected: $Merge(map1, map2, override)")
-	}
+	return mergeUDF.Call(params, state)
+}
+
+var mergeUDF = &Definition{
+	Name:    "Merge",
+	Params:  []*Param{{Name: "maps", Type: AnyType, Variadic: true}},
+	Doc:     Doc{Description: "creates a new map merging supplied maps or maps referenced by state path", Example: "$Merge($map1, map2Path)"},
+	Handler: merge,
+}
+
+func merge(args []interface{}, state data.Map) (interface{}, error) {
 	var result = make(map[string]interface{})
-	parameters := toolbox.AsSlice(params)
-	if len(parameters) == 0 {
-		return result, nil
-	}
 	var ok bool
-	for _, item := range parameters {
+	for _, item := range args {
 		if toolbox.IsString(item) && state != nil {
 			if item, ok = state.GetValue(toolbox.AsString(item)); !ok {
 				continue
@@ -2,7 +2,6 @@ package toolbox
 
 import (
 	"bytes"
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"gopkg.in/yaml.v2"
@@ -120,8 +119,7 @@ func (d *delimiterDecoder) Decode(target interface{}) error {
 	if err != nil {
 		return err
 	}
-	reader := csv.NewReader(bytes.NewReader(payload))
-	reader.Comma = rune(delimiter[0])
+	reader := newCSVReader(bytes.NewReader(payload), (&CSVOptions{Delimiter: rune(delimiter[0])}).init())
 	hasColumns := len(delimitedRecord.Columns) > 0
 	if !hasColumns {
 		delimitedRecord.Columns = make([]string, 0)
@@ -138,6 +136,9 @@ func (d *delimiterDecoder) Decode(target interface{}) error {
 			delimitedRecord.Columns = append(delimitedRecord.Columns, strings.TrimSpace(field))
 		}
 	} else {
+		if len(record) > len(delimitedRecord.Columns) {
+			return fmt.Errorf("expected at most %d fields, but had %d", len(delimitedRecord.Columns), len(record))
+		}
 		for i, field := range record {
 			delimitedRecord.Record[delimitedRecord.Columns[i]] = field
 		}
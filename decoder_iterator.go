@@ -0,0 +1,303 @@
+package toolbox
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+//SplitAction represents a decision how a stream line contributes to a record
+type SplitAction int
+
+const (
+	//SplitAppend appends line to the current record
+	SplitAppend SplitAction = iota
+	//SplitComplete appends line to the current record and completes it
+	SplitComplete
+	//SplitSeparator completes the current record, line itself is not part of any record
+	SplitSeparator
+	//SplitStart completes the current record, line starts the next record
+	SplitStart
+)
+
+//RecordSplitter decides how stream lines are grouped into records, record holds lines collected so far, line includes trailing new line
+type RecordSplitter func(record, line []byte) SplitAction
+
+//SplitLines treats every non blank line as a record, i.e. JSON lines (NDJSON)
+func SplitLines(record, line []byte) SplitAction {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return SplitSeparator
+	}
+	return SplitComplete
+}
+
+//SplitYAMLDocuments groups lines into YAML documents separated with --- or terminated with ..., content following --- starts the next document
+func SplitYAMLDocuments(record, line []byte) SplitAction {
+	text := strings.TrimRight(string(line), " \t\r\n")
+	if text == "---" || text == "..." {
+		return SplitSeparator
+	}
+	if strings.HasPrefix(text, "--- ") {
+		return SplitStart
+	}
+	return SplitAppend
+}
+
+//SplitDelimitedRecords groups lines into delimited (CSV) records, quoted values can span multiple lines
+func SplitDelimitedRecords(record, line []byte) SplitAction {
+	if len(record) == 0 && len(bytes.TrimSpace(line)) == 0 {
+		return SplitSeparator
+	}
+	if (bytes.Count(record, []byte(`"`))+bytes.Count(line, []byte(`"`)))%2 == 0 {
+		return SplitComplete
+	}
+	return SplitAppend
+}
+
+//StreamRecord represents a decoded record with its location in the stream
+type StreamRecord struct {
+	Line   int         //line number of the record first line, starting from 1
+	Offset int64       //byte offset of the record first byte
+	Value  interface{} //decoding target pointer, if nil or not a pointer (i.e. value set by previous Next) record is decoded into interface{}
+}
+
+//StreamDecodeError represents a record decoding error with its location in the stream
+type StreamDecodeError struct {
+	Line   int
+	Offset int64
+	Err    error
+}
+
+//Error returns error description with the record location
+func (e *StreamDecodeError) Error() string {
+	return fmt.Sprintf("failed to decode record at line %d (offset %d): %v", e.Line, e.Offset, e.Err)
+}
+
+//DecoderIterator represents a StreamIterator reading one record at a time and decoding it with a decoder created by DecoderFactory
+type DecoderIterator struct {
+	reader   *bufio.Reader
+	closer   io.Closer
+	factory  DecoderFactory
+	splitter RecordSplitter
+	header   func(payload []byte) error
+	decode   func(payload []byte, target interface{}) error
+	line     int
+	offset   int64
+	eof      bool
+	payload  []byte
+	fetched  bool
+	err      error
+	//line starting the next record, set by SplitStart
+	pending       []byte
+	pendingLine   int
+	pendingOffset int64
+	//location of the pending or the last returned record
+	recordLine   int
+	recordOffset int64
+}
+
+//Line returns line number of the last returned record
+func (i *DecoderIterator) Line() int {
+	return i.recordLine
+}
+
+//Offset returns byte offset of the last returned record
+func (i *DecoderIterator) Offset() int64 {
+	return i.recordOffset
+}
+
+//HasNext returns true if iterator has next element, it returns false on read error reported by Err
+func (i *DecoderIterator) HasNext() bool {
+	if i.fetched {
+		return true
+	}
+	if i.err != nil {
+		return false
+	}
+	for {
+		payload, err := i.readRecord()
+		if err != nil {
+			i.err = err
+			return false
+		}
+		if payload == nil {
+			return false
+		}
+		if i.header != nil {
+			header := i.header
+			i.header = nil
+			if err := header(payload); err != nil {
+				i.err = &StreamDecodeError{Line: i.recordLine, Offset: i.recordOffset, Err: err}
+				return false
+			}
+			continue
+		}
+		i.payload, i.fetched = payload, true
+		return true
+	}
+}
+
+//readRecord returns next non blank record payload or nil at the end of the stream
+func (i *DecoderIterator) readRecord() ([]byte, error) {
+	var record []byte
+	if i.pending != nil {
+		record, i.pending = i.pending, nil
+		i.recordLine, i.recordOffset = i.pendingLine, i.pendingOffset
+	}
+	for !i.eof {
+		line, err := i.reader.ReadBytes('\n')
+		if err == io.EOF {
+			i.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 {
+			break
+		}
+		i.line++
+		lineOffset := i.offset
+		i.offset += int64(len(line))
+		action := i.splitter(record, line)
+		if action == SplitStart {
+			if len(bytes.TrimSpace(record)) > 0 {
+				i.pending, i.pendingLine, i.pendingOffset = line, i.line, lineOffset
+				return record, nil
+			}
+			record, action = nil, SplitAppend
+		}
+		if action == SplitSeparator {
+			if len(bytes.TrimSpace(record)) > 0 {
+				return record, nil
+			}
+			record = nil
+			continue
+		}
+		if len(bytes.TrimSpace(record)) == 0 {
+			i.recordLine, i.recordOffset = i.line, lineOffset
+			if len(bytes.TrimSpace(line)) == 0 {
+				record = nil
+				continue
+			}
+		}
+		record = append(record, line...)
+		if action == SplitComplete {
+			return record, nil
+		}
+	}
+	if len(bytes.TrimSpace(record)) > 0 {
+		return record, nil
+	}
+	return nil, nil
+}
+
+//Next decodes next record into item pointer, if item pointer is *StreamRecord its Value is decoded and location is set.
+//Decoding error is returned as *StreamDecodeError, iteration can continue with the following record.
+func (i *DecoderIterator) Next(itemPointer interface{}) error {
+	if !i.HasNext() {
+		if i.err != nil {
+			return i.err
+		}
+		return io.EOF
+	}
+	payload := i.payload
+	i.payload, i.fetched = nil, false
+	target := itemPointer
+	record, isRecord := itemPointer.(*StreamRecord)
+	var value interface{}
+	decodeValue := false
+	if isRecord {
+		record.Line, record.Offset = i.recordLine, i.recordOffset
+		target = record.Value
+		if target == nil || reflect.TypeOf(target).Kind() != reflect.Ptr {
+			target, decodeValue = &value, true
+		}
+	}
+	if err := i.decode(payload, target); err != nil {
+		return &StreamDecodeError{Line: i.recordLine, Offset: i.recordOffset, Err: err}
+	}
+	if decodeValue {
+		record.Value = value
+	}
+	return nil
+}
+
+//Err returns read error, nil at regular end of the stream
+func (i *DecoderIterator) Err() error {
+	return i.err
+}
+
+//Close closes underlying reader if it implements io.Closer
+func (i *DecoderIterator) Close() error {
+	i.eof, i.fetched, i.payload, i.pending = true, false, nil, nil
+	if i.closer != nil {
+		closer := i.closer
+		i.closer = nil
+		return closer.Close()
+	}
+	return nil
+}
+
+func (i *DecoderIterator) decodeWithFactory(payload []byte, target interface{}) error {
+	return i.factory.Create(bytes.NewReader(payload)).Decode(target)
+}
+
+//NewDecoderIterator creates an iterator streaming records split by splitter and decoded with decoder created by factory
+func NewDecoderIterator(reader io.Reader, factory DecoderFactory, splitter RecordSplitter) *DecoderIterator {
+	result := &DecoderIterator{
+		reader:   bufio.NewReader(reader),
+		factory:  factory,
+		splitter: splitter,
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		result.closer = closer
+	}
+	result.decode = result.decodeWithFactory
+	return result
+}
+
+//NewJSONLinesIterator creates an iterator streaming JSON lines (NDJSON) records
+func NewJSONLinesIterator(reader io.Reader) *DecoderIterator {
+	return NewDecoderIterator(reader, NewJSONDecoderFactory(), SplitLines)
+}
+
+//NewYAMLDocumentIterator creates an iterator streaming multi document YAML
+func NewYAMLDocumentIterator(reader io.Reader) *DecoderIterator {
+	return NewDecoderIterator(reader, NewYamlDecoderFactory(), SplitYAMLDocuments)
+}
+
+//NewDelimitedIterator creates an iterator streaming delimited (CSV) records with DelimitedRecord decoder, the first record defines columns.
+//Records are decoded as map[string]interface{} and converted to the target type.
+func NewDelimitedIterator(reader io.Reader, delimiter string) *DecoderIterator {
+	result := NewDecoderIterator(reader, NewDelimiterDecoderFactory(), SplitDelimitedRecords)
+	var columns []string
+	result.header = func(payload []byte) error {
+		header := &DelimitedRecord{Delimiter: delimiter}
+		if err := result.decodeWithFactory(payload, header); err != nil {
+			return err
+		}
+		columns = header.Columns
+		return nil
+	}
+	result.decode = func(payload []byte, target interface{}) error {
+		record := &DelimitedRecord{Columns: columns, Delimiter: delimiter, Record: make(map[string]interface{})}
+		if delimitedRecord, ok := target.(*DelimitedRecord); ok {
+			record = delimitedRecord
+			record.Columns, record.Delimiter = columns, delimiter
+		}
+		if err := result.decodeWithFactory(payload, record); err != nil {
+			return err
+		}
+		if record == target {
+			return nil
+		}
+		if aPointer, ok := target.(*interface{}); ok {
+			*aPointer = record.Record
+			return nil
+		}
+		return DefaultConverter.AssignConverted(target, record.Record)
+	}
+	return result
+}
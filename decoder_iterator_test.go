@@ -0,0 +1,107 @@
+package toolbox_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+type streamItem struct {
+	ID   int
+	Name string
+}
+
+func TestNewJSONLinesIterator(t *testing.T) {
+	input := "{\"ID\":1,\"Name\":\"a\"}\n\n{\"ID\":2,\"Name\":\"b\"}\n{\"ID\":\"x\"}\n{\"ID\":4}"
+	iterator := toolbox.NewJSONLinesIterator(strings.NewReader(input))
+	defer iterator.Close()
+
+	var item streamItem
+	assert.True(t, iterator.HasNext())
+	assert.Nil(t, iterator.Next(&item))
+	assert.Equal(t, streamItem{ID: 1, Name: "a"}, item)
+	assert.Equal(t, 1, iterator.Line())
+	assert.EqualValues(t, 0, iterator.Offset())
+
+	record := &toolbox.StreamRecord{}
+	assert.Nil(t, iterator.Next(record))
+	assert.Equal(t, 3, record.Line)
+	assert.EqualValues(t, 21, record.Offset)
+	assert.Equal(t, "b", toolbox.AsMap(record.Value)["Name"])
+
+	err := iterator.Next(&streamItem{})
+	assert.NotNil(t, err)
+	decodeError, ok := err.(*toolbox.StreamDecodeError)
+	assert.True(t, ok)
+	assert.Equal(t, 4, decodeError.Line)
+
+	assert.Nil(t, iterator.Next(record))
+	assert.Equal(t, 5, record.Line)
+	assert.EqualValues(t, 4, toolbox.AsMap(record.Value)["ID"])
+	assert.False(t, iterator.HasNext())
+
+	item = streamItem{}
+	iterator = toolbox.NewJSONLinesIterator(strings.NewReader(input))
+	record.Value = &item
+	assert.Nil(t, iterator.Next(record))
+	assert.Equal(t, streamItem{ID: 1, Name: "a"}, item)
+	assert.Nil(t, iterator.Err())
+}
+
+func TestNewYAMLDocumentIterator(t *testing.T) {
+	input := "---\nid: 1\nname: a\n---\n\n---\nid: 2\n\nname: b\n...\n"
+	iterator := toolbox.NewYAMLDocumentIterator(strings.NewReader(input))
+	var actual = make([]streamItem, 0)
+	var lines = make([]int, 0)
+	for iterator.HasNext() {
+		var item streamItem
+		if !assert.Nil(t, iterator.Next(&item)) {
+			break
+		}
+		actual = append(actual, item)
+		lines = append(lines, iterator.Line())
+	}
+	assert.Nil(t, iterator.Err())
+	assert.Equal(t, []streamItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}, actual)
+	assert.Equal(t, []int{2, 7}, lines)
+}
+
+func TestNewYAMLDocumentIterator_InlineDocuments(t *testing.T) {
+	input := "--- {id: 1, name: a}\n--- {id: 2}\n---\nid: 3\n"
+	iterator := toolbox.NewYAMLDocumentIterator(strings.NewReader(input))
+	record := &toolbox.StreamRecord{}
+	var ids = make([]int, 0)
+	var lines = make([]int, 0)
+	for iterator.HasNext() {
+		if !assert.Nil(t, iterator.Next(record)) {
+			break
+		}
+		ids = append(ids, toolbox.AsInt(toolbox.AsMap(record.Value)["id"]))
+		lines = append(lines, record.Line)
+	}
+	assert.Nil(t, iterator.Err())
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.Equal(t, []int{1, 2, 4}, lines)
+}
+
+func TestNewDelimitedIterator(t *testing.T) {
+	input := "ID,Name\n1,a\n\n2,\"multi\nline\"\n3,c,extra\n"
+	iterator := toolbox.NewDelimitedIterator(strings.NewReader(input), ",")
+
+	var item streamItem
+	assert.Nil(t, iterator.Next(&item))
+	assert.Equal(t, streamItem{ID: 1, Name: "a"}, item)
+	assert.Equal(t, 2, iterator.Line())
+
+	var record interface{}
+	assert.Nil(t, iterator.Next(&record))
+	assert.Equal(t, map[string]interface{}{"ID": "2", "Name": "multi\nline"}, record)
+	assert.Equal(t, 4, iterator.Line())
+
+	err := iterator.Next(&record)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "line 6")
+	assert.False(t, iterator.HasNext())
+}
@@ -10,6 +10,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -30,6 +32,48 @@ type FieldInfo struct {
 	Tag                string
 	Comment            string
 	IsVariant          bool
+	Tags               map[string]string
+	TypeArguments      []string
+	TypeImportPath     string //import path of field type package, set by module loader
+	resolvedType       *TypeInfo
+}
+
+//ResolvedType returns type info of the field type (or its component/value type) resolved by module loader, nil if type is not declared in a module
+func (f *FieldInfo) ResolvedType() *TypeInfo {
+	return f.resolvedType
+}
+
+//TypeParamInfo represents a type parameter info
+type TypeParamInfo struct {
+	Name       string
+	Constraint string
+}
+
+//ConstInfo represents a package level constant info
+type ConstInfo struct {
+	Name     string
+	TypeName string      //declared or implicitly repeated type name
+	Expr     string      //declared or implicitly repeated expression
+	Value    interface{} //evaluated value: int64, uint64, float64, string or bool, nil if value could not be evaluated
+	Iota     int         //iota value within the const block
+	Block    int         //const block index within a file
+	Comment  string
+	expr     ast.Expr
+}
+
+//VarInfo represents a package level variable info
+type VarInfo struct {
+	Name     string
+	TypeName string
+	Expr     string
+	Comment  string
+}
+
+//ImportInfo represents an import info
+type ImportInfo struct {
+	Name  string //name used to reference imported package: alias or last path segment
+	Alias string //explicit alias, including "." and "_"
+	Path  string //unquoted import path
 }
 
 //NewFunctionInfoFromField creates a new function info.
@@ -96,8 +140,12 @@ func NewFieldInfoByIndex(field *ast.Field, index int) *FieldInfo {
 		result.Name = field.Names[index].Name
 	} else {
 		result.Name = strings.Replace(strings.Replace(result.TypeName, "[]", "", len(result.TypeName)), "*", "", len(result.TypeName))
+		if index := strings.Index(result.Name, "["); index != -1 {
+			result.Name = result.Name[:index]
+		}
 		result.IsAnonymous = true
 	}
+	result.TypeArguments = typeArguments(field.Type)
 	_, result.IsMap = field.Type.(*ast.MapType)
 	var arrayType *ast.ArrayType
 	if arrayType, result.IsSlice = field.Type.(*ast.ArrayType); result.IsSlice {
@@ -133,6 +181,7 @@ func NewFieldInfoByIndex(field *ast.Field, index int) *FieldInfo {
 
 	if field.Tag != nil {
 		result.Tag = field.Tag.Value
+		result.Tags = parseStructTag(field.Tag.Value)
 	}
 	if mapType, ok := field.Type.(*ast.MapType); ok {
 		result.KeyTypeName = types.ExprString(mapType.Key)
@@ -154,12 +203,90 @@ func NewFieldInfoByIndex(field *ast.Field, index int) *FieldInfo {
 	return result
 }
 
+//typeArguments returns type arguments of generic type instantiation, i.e. [K V] for *Map[K, V]
+func typeArguments(expr ast.Expr) []string {
+	if starExpr, ok := expr.(*ast.StarExpr); ok {
+		expr = starExpr.X
+	}
+	var result []string
+	switch actual := expr.(type) {
+	case *ast.IndexExpr:
+		result = append(result, types.ExprString(actual.Index))
+	case *ast.IndexListExpr:
+		for _, index := range actual.Indices {
+			result = append(result, types.ExprString(index))
+		}
+	}
+	return result
+}
+
+//parseStructTag parses struct tag into key value pairs
+func parseStructTag(tag string) map[string]string {
+	var result = make(map[string]string)
+	if unquoted, err := strconv.Unquote(tag); err == nil {
+		tag = unquoted
+	}
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		result[name] = value
+		tag = tag[i+1:]
+	}
+	return result
+}
+
+//toTypeParamInfos converts type parameters field list to type param infos
+func toTypeParamInfos(source *ast.FieldList) []*TypeParamInfo {
+	var result = make([]*TypeParamInfo, 0)
+	if source == nil {
+		return result
+	}
+	for _, field := range source.List {
+		for _, name := range field.Names {
+			result = append(result, &TypeParamInfo{Name: name.Name, Constraint: types.ExprString(field.Type)})
+		}
+	}
+	return result
+}
+
 //FunctionInfo represents a function info
 type FunctionInfo struct {
-	Name             string
-	ReceiverTypeName string
-	ParameterFields  []*FieldInfo
-	ResultsFields    []*FieldInfo
+	Name               string
+	ReceiverTypeName   string
+	IsPointerReceiver  bool
+	ReceiverTypeParams []string
+	TypeParams         []*TypeParamInfo
+	ParameterFields    []*FieldInfo
+	ResultsFields      []*FieldInfo
 	*FileInfo
 }
 
@@ -176,14 +303,25 @@ func NewFunctionInfo(funcDeclaration *ast.FuncDecl, owner *FileInfo) *FunctionIn
 	}
 	if funcDeclaration.Recv != nil {
 		receiverType := funcDeclaration.Recv.List[0].Type
-		if ident, ok := receiverType.(*ast.Ident); ok {
-			result.ReceiverTypeName = ident.Name
-		} else if startExpr, ok := receiverType.(*ast.StarExpr); ok {
-			if ident, ok := startExpr.X.(*ast.Ident); ok {
+		if startExpr, ok := receiverType.(*ast.StarExpr); ok {
+			result.IsPointerReceiver = true
+			receiverType = startExpr.X
+		}
+		switch actual := receiverType.(type) {
+		case *ast.Ident:
+			result.ReceiverTypeName = actual.Name
+		case *ast.IndexExpr:
+			if ident, ok := actual.X.(*ast.Ident); ok {
+				result.ReceiverTypeName = ident.Name
+			}
+		case *ast.IndexListExpr:
+			if ident, ok := actual.X.(*ast.Ident); ok {
 				result.ReceiverTypeName = ident.Name
 			}
 		}
+		result.ReceiverTypeParams = typeArguments(receiverType)
 	}
+	result.TypeParams = toTypeParamInfos(funcDeclaration.Type.TypeParams)
 	return result
 }
 
@@ -198,6 +336,10 @@ type TypeInfo struct {
 	IsStruct               bool
 	IsInterface            bool
 	IsDerived              bool
+	IsAlias                bool
+	TypeParams             []*TypeParamInfo
+	EmbeddedInterfaces     []string //interfaces embedded in an interface type
+	TypeSet                []string //type constraint terms, i.e. ~int, string
 	ComponentType          string
 	IsPointerComponentType bool
 	Derived                string
@@ -259,9 +401,15 @@ func (s *TypeInfo) AddReceivers(receivers ...*FunctionInfo) {
 	}
 }
 
+//IsGeneric returns true if type declares type parameters
+func (s *TypeInfo) IsGeneric() bool {
+	return len(s.TypeParams) > 0
+}
+
 //NewTypeInfo creates a new struct info
 func NewTypeInfo(name string) *TypeInfo {
 	return &TypeInfo{Name: name,
+		TypeParams:      make([]*TypeParamInfo, 0),
 		fields:          make([]*FieldInfo, 0),
 		receivers:       make([]*FunctionInfo, 0),
 		indexedReceiver: make(map[string]*FunctionInfo),
@@ -279,7 +427,152 @@ type FileInfo struct {
 	currentTypInfo      *TypeInfo
 	fileSet             *token.FileSet
 	currentFunctionInfo *FunctionInfo
+	currentTypeExpr     ast.Expr
 	Imports             map[string]string
+	imports             []*ImportInfo
+	consts              []*ConstInfo
+	vars                []*VarInfo
+}
+
+//ImportsInfo returns all imports with their aliases
+func (f *FileInfo) ImportsInfo() []*ImportInfo {
+	return f.imports
+}
+
+//Import returns import info for supplied package name or alias
+func (f *FileInfo) Import(name string) *ImportInfo {
+	for _, candidate := range f.imports {
+		if candidate.Name == name {
+			return candidate
+		}
+	}
+	return nil
+}
+
+//Consts returns package level constants declared in a file
+func (f *FileInfo) Consts() []*ConstInfo {
+	return f.consts
+}
+
+//Vars returns package level variables declared in a file
+func (f *FileInfo) Vars() []*VarInfo {
+	return f.vars
+}
+
+//readValueSpecs reads package level constants and variables
+func (f *FileInfo) readValueSpecs(file *ast.File) {
+	block := 0
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+			continue
+		}
+		var typeExpr ast.Expr
+		var values []ast.Expr
+		for iota, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			comment := declComment(genDecl, valueSpec)
+			if genDecl.Tok == token.VAR {
+				for i, name := range valueSpec.Names {
+					varInfo := &VarInfo{Name: name.Name, Comment: comment}
+					if valueSpec.Type != nil {
+						varInfo.TypeName = types.ExprString(valueSpec.Type)
+					}
+					if i < len(valueSpec.Values) {
+						varInfo.Expr = types.ExprString(valueSpec.Values[i])
+					}
+					f.vars = append(f.vars, varInfo)
+				}
+				continue
+			}
+			if len(valueSpec.Values) > 0 { //implicit repetition of the last non-empty expression list
+				typeExpr = valueSpec.Type
+				values = valueSpec.Values
+			}
+			for i, name := range valueSpec.Names {
+				constInfo := &ConstInfo{Name: name.Name, Iota: iota, Block: block, Comment: comment}
+				if typeExpr != nil {
+					constInfo.TypeName = types.ExprString(typeExpr)
+				}
+				if i < len(values) {
+					constInfo.expr = values[i]
+					constInfo.Expr = types.ExprString(values[i])
+				}
+				f.consts = append(f.consts, constInfo)
+			}
+		}
+		if genDecl.Tok == token.CONST {
+			block++
+		}
+	}
+}
+
+func declComment(genDecl *ast.GenDecl, valueSpec *ast.ValueSpec) string {
+	if valueSpec.Doc != nil {
+		return strings.TrimSpace(valueSpec.Doc.Text())
+	}
+	if valueSpec.Comment != nil {
+		return strings.TrimSpace(valueSpec.Comment.Text())
+	}
+	if genDecl.Doc != nil && len(genDecl.Specs) == 1 {
+		return strings.TrimSpace(genDecl.Doc.Text())
+	}
+	return ""
+}
+
+//readInterface reads interface methods, embedded interfaces and type constraints
+func (f *FileInfo) readInterface(typeInfo *TypeInfo, interfaceType *ast.InterfaceType) {
+	if interfaceType.Methods == nil {
+		return
+	}
+	for _, field := range interfaceType.Methods.List {
+		if _, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+			method := NewFunctionInfoFromField(field, f)
+			method.FileInfo = f
+			typeInfo.AddReceivers(method)
+			continue
+		}
+		switch actual := field.Type.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			typeInfo.EmbeddedInterfaces = append(typeInfo.EmbeddedInterfaces, types.ExprString(actual))
+		default:
+			typeInfo.TypeSet = append(typeInfo.TypeSet, unionTerms(field.Type)...)
+		}
+	}
+}
+
+//unionTerms returns terms of type constraint union, i.e. ~int | string
+func unionTerms(expr ast.Expr) []string {
+	if binaryExpr, ok := expr.(*ast.BinaryExpr); ok && binaryExpr.Op == token.OR {
+		return append(unionTerms(binaryExpr.X), unionTerms(binaryExpr.Y)...)
+	}
+	return []string{types.ExprString(expr)}
+}
+
+//importName returns default package name for supplied import path
+func importName(importPath string) string {
+	segments := strings.Split(importPath, "/")
+	name := segments[len(segments)-1]
+	if len(segments) > 1 && isMajorVersion(name) {
+		name = segments[len(segments)-2]
+	}
+	if index := strings.Index(name, ".v"); index != -1 && isMajorVersion(name[index+1:]) {
+		name = name[:index]
+	}
+	return name
+}
+
+func isMajorVersion(candidate string) bool {
+	if len(candidate) < 2 || candidate[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(candidate[1:])
+	return err == nil
+}
+
+//PackageName returns package name declared in a file
+func (f *FileInfo) PackageName() string {
+	return f.packageName
 }
 
 //Type returns a type info for passed in name
@@ -297,6 +590,11 @@ func (f *FileInfo) addFunction(funcion *FunctionInfo) {
 	f.functions[funcion.ReceiverTypeName] = append(f.functions[funcion.ReceiverTypeName], funcion)
 }
 
+//Functions returns package level functions declared in a file
+func (f *FileInfo) Functions() []*FunctionInfo {
+	return f.functions[""]
+}
+
 //Types returns all struct info
 func (f *FileInfo) Types() []*TypeInfo {
 	var result = make([]*TypeInfo, 0)
@@ -346,18 +644,6 @@ func toFieldInfoSlice(source *ast.FieldList) []*FieldInfo {
 	return result
 }
 
-//toFunctionInfos convers filedList to function info slice.
-func toFunctionInfos(source *ast.FieldList, owner *FileInfo) []*FunctionInfo {
-	var result = make([]*FunctionInfo, 0)
-	if source == nil || len(source.List) == 0 {
-		return result
-	}
-	for _, field := range source.List {
-		result = append(result, NewFunctionInfoFromField(field, owner))
-	}
-	return result
-}
-
 //Visit visits ast node to extract struct details from the passed file
 func (f *FileInfo) Visit(node ast.Node) ast.Visitor {
 	if node != nil {
@@ -366,11 +652,16 @@ func (f *FileInfo) Visit(node ast.Node) ast.Visitor {
 		}
 //TODO refactor this mess !!!!
 		switch value := node.(type) {
+		case *ast.File:
+			f.readValueSpecs(value)
 		case *ast.TypeSpec:
 			typeName := value.Name.Name
 			typeInfo := NewTypeInfo(typeName)
 			typeInfo.Package = f.packageName
 			typeInfo.FileName = f.filename
+			typeInfo.IsAlias = value.Assign.IsValid()
+			typeInfo.TypeParams = toTypeParamInfos(value.TypeParams)
+			f.currentTypeExpr = value.Type
 
 			switch typeValue := value.Type.(type) {
 			case *ast.ArrayType:
@@ -387,14 +678,18 @@ func (f *FileInfo) Visit(node ast.Node) ast.Visitor {
 				typeInfo.IsStruct = true
 			case *ast.InterfaceType:
 				typeInfo.IsInterface = true
+				f.readInterface(typeInfo, typeValue)
 			case *ast.Ident:
 				typeInfo.Derived = typeValue.Name
 				typeInfo.IsDerived = true
+			case *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+				typeInfo.Derived = types.ExprString(typeValue)
+				typeInfo.IsDerived = true
 			}
 			f.currentTypInfo = typeInfo
 			f.types[typeName] = typeInfo
 		case *ast.StructType:
-			if f.currentTypInfo != nil { //TODO fixme - understand why current type would be nil
+			if f.currentTypInfo != nil && value == f.currentTypeExpr { //only struct type declaration, not anonymous or nested struct
 				f.currentTypInfo.Comment = f.readComment(value.Pos())
 				f.currentTypInfo.AddFields(toFieldInfoSlice(value.Fields)...)
 			}
@@ -402,11 +697,9 @@ func (f *FileInfo) Visit(node ast.Node) ast.Visitor {
 			functionInfo := NewFunctionInfo(value, f)
 			functionInfo.FileInfo = f
 			f.currentFunctionInfo = functionInfo
-			if len(functionInfo.ReceiverTypeName) > 0 {
-				f.addFunction(functionInfo)
-			}
+			f.addFunction(functionInfo) //package level functions are indexed with empty receiver type name
 		case *ast.MapType:
-			if f.currentTypInfo == nil {
+			if f.currentTypInfo == nil || value != f.currentTypeExpr {
 				break
 			}
 			f.currentTypInfo.IsMap = true
@@ -455,19 +748,19 @@ func (f *FileInfo) Visit(node ast.Node) ast.Visitor {
 				}
 				f.currentFunctionInfo = nil
 			}
-		case *ast.FieldList:
-			if f.currentTypInfo != nil && f.currentTypInfo.IsInterface {
-				f.currentTypInfo.receivers = toFunctionInfos(value, f)
-				f.currentTypInfo = nil
-			}
 		case *ast.ImportSpec:
+			importInfo := &ImportInfo{Path: strings.Trim(value.Path.Value, `"`)}
+			importInfo.Name = importName(importInfo.Path)
 			if value.Name != nil && value.Name.String() != "" {
+				importInfo.Alias = value.Name.String()
+				importInfo.Name = importInfo.Alias
 				f.Imports[value.Name.String()] = value.Path.Value
 			} else {
 				_, name := path.Split(value.Path.Value)
 				name = strings.Replace(name, `"`, "", 2)
 				f.Imports[name] = value.Path.Value
 			}
+			f.imports = append(f.imports, importInfo)
 		}
 
 	}
@@ -483,6 +776,9 @@ func NewFileInfo(basePath, packageName, filename string, fileSet *token.FileSet)
 		types:       make(map[string]*TypeInfo),
 		functions:   make(map[string][]*FunctionInfo),
 		Imports:     make(map[string]string),
+		imports:     make([]*ImportInfo, 0),
+		consts:      make([]*ConstInfo, 0),
+		vars:        make([]*VarInfo, 0),
 		fileSet:     fileSet}
 	return result
 }
@@ -502,6 +798,87 @@ func (f *FileSetInfo) FilesInfo() map[string]*FileInfo {
 	return f.files
 }
 
+//Consts returns all package level constants
+func (f *FileSetInfo) Consts() []*ConstInfo {
+	var result = make([]*ConstInfo, 0)
+	for _, fileName := range f.fileNames() {
+		result = append(result, f.files[fileName].consts...)
+	}
+	return result
+}
+
+//Const returns constant info for supplied name
+func (f *FileSetInfo) Const(name string) *ConstInfo {
+	for _, candidate := range f.Consts() {
+		if candidate.Name == name {
+			return candidate
+		}
+	}
+	return nil
+}
+
+//Vars returns all package level variables
+func (f *FileSetInfo) Vars() []*VarInfo {
+	var result = make([]*VarInfo, 0)
+	for _, fileName := range f.fileNames() {
+		result = append(result, f.files[fileName].vars...)
+	}
+	return result
+}
+
+//Var returns variable info for supplied name
+func (f *FileSetInfo) Var(name string) *VarInfo {
+	for _, candidate := range f.Vars() {
+		if candidate.Name == name {
+			return candidate
+		}
+	}
+	return nil
+}
+
+//MethodSet returns methods of supplied type including methods of embedded interfaces and promoted methods of embedded local types
+func (f *FileSetInfo) MethodSet(typeName string) []*FunctionInfo {
+	var result = make([]*FunctionInfo, 0)
+	f.collectMethodSet(typeName, true, make(map[string]bool), make(map[string]bool), &result)
+	return result
+}
+
+//collectMethodSet collects methods of a type, value receiver methods only unless pointer is true
+func (f *FileSetInfo) collectMethodSet(typeName string, pointer bool, visited, methods map[string]bool, result *[]*FunctionInfo) {
+	if index := strings.Index(typeName, "["); index != -1 {
+		typeName = typeName[:index]
+	}
+	typeInfo := f.Type(typeName)
+	if typeInfo == nil || visited[typeInfo.Name] {
+		return
+	}
+	visited[typeInfo.Name] = true
+	for _, receiver := range typeInfo.Receivers() {
+		if methods[receiver.Name] || (receiver.IsPointerReceiver && !pointer) {
+			continue
+		}
+		methods[receiver.Name] = true
+		*result = append(*result, receiver)
+	}
+	for _, embedded := range typeInfo.EmbeddedInterfaces {
+		f.collectMethodSet(embedded, pointer, visited, methods, result)
+	}
+	for _, field := range typeInfo.Fields() {
+		if field.IsAnonymous {
+			f.collectMethodSet(field.Name, pointer || field.IsPointer, visited, methods, result)
+		}
+	}
+}
+
+func (f *FileSetInfo) fileNames() []string {
+	var result = make([]string, 0, len(f.files))
+	for fileName := range f.files {
+		result = append(result, fileName)
+	}
+	sort.Strings(result)
+	return result
+}
+
 //Type returns type info for passed in type  name.
 func (f *FileSetInfo) Type(name string) *TypeInfo {
 	if pointerIndex := strings.LastIndex(name, "*"); pointerIndex != -1 {
@@ -517,8 +894,12 @@ func (f *FileSetInfo) Type(name string) *TypeInfo {
 
 //NewFileSetInfo creates a new fileset info
 func NewFileSetInfo(baseDir string) (*FileSetInfo, error) {
+	return newFileSetInfo(baseDir, nil)
+}
+
+func newFileSetInfo(baseDir string, filter func(os.FileInfo) bool) (*FileSetInfo, error) {
 	fileSet := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fileSet, baseDir, nil, parser.ParseComments)
+	pkgs, err := parser.ParseDir(fileSet, baseDir, filter, parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse path %v: %v", baseDir, err)
 	}
@@ -539,11 +920,12 @@ func NewFileSetInfo(baseDir string) (*FileSetInfo, error) {
 
 		for k, functionsInfo := range fileInfo.functions {
 			typeInfo := result.Type(k)
-			if typeInfo != nil && typeInfo.IsStruct {
+			if typeInfo != nil && !typeInfo.IsInterface { //methods may be declared in any file of the package
 				typeInfo.AddReceivers(functionsInfo...)
 			}
 		}
 
 	}
+	result.evaluateConsts()
 	return result, nil
 }
@@ -0,0 +1,120 @@
+package toolbox
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+)
+
+// evaluateConsts evaluates constant expressions, constants may reference constants declared in other files
+func (f *FileSetInfo) evaluateConsts() {
+	var consts = f.Consts()
+	var values = make(map[string]constant.Value)
+	for resolved := true; resolved; {
+		resolved = false
+		for _, constInfo := range consts {
+			if constInfo.Value != nil || constInfo.expr == nil {
+				continue
+			}
+			value := safeEvalConstExpr(constInfo.expr, constInfo.Iota, values)
+			if value == nil || value.Kind() == constant.Unknown {
+				continue
+			}
+			if constInfo.Name != "_" {
+				values[constInfo.Name] = value
+			}
+			constInfo.Value = constantValue(value)
+			resolved = true
+		}
+	}
+}
+
+// safeEvalConstExpr evaluates constant expression, go/constant panics on operands mismatch which is reported as nil value
+func safeEvalConstExpr(expr ast.Expr, iota int, values map[string]constant.Value) (result constant.Value) {
+	defer func() {
+		if recover() != nil {
+			result = nil
+		}
+	}()
+	return evalConstExpr(expr, iota, values)
+}
+
+// evalConstExpr evaluates constant expression, it returns nil if expression can not be evaluated
+func evalConstExpr(expr ast.Expr, iota int, values map[string]constant.Value) constant.Value {
+	switch actual := expr.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(actual.Value, actual.Kind, 0)
+	case *ast.Ident:
+		switch actual.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota))
+		case "true", "false":
+			return constant.MakeBool(actual.Name == "true")
+		}
+		return values[actual.Name]
+	case *ast.ParenExpr:
+		return evalConstExpr(actual.X, iota, values)
+	case *ast.CallExpr: //type conversion, i.e. Kind(1)
+		if len(actual.Args) != 1 {
+			return nil
+		}
+		return evalConstExpr(actual.Args[0], iota, values)
+	case *ast.UnaryExpr:
+		x := evalConstExpr(actual.X, iota, values)
+		if x == nil {
+			return nil
+		}
+		return constant.UnaryOp(actual.Op, x, 0)
+	case *ast.BinaryExpr:
+		x := evalConstExpr(actual.X, iota, values)
+		y := evalConstExpr(actual.Y, iota, values)
+		if x == nil || y == nil {
+			return nil
+		}
+		switch actual.Op {
+		case token.SHL, token.SHR:
+			shift, ok := constant.Uint64Val(constant.ToInt(y))
+			if !ok {
+				return nil
+			}
+			return constant.Shift(x, actual.Op, uint(shift))
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return constant.MakeBool(constant.Compare(x, actual.Op, y))
+		case token.QUO:
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				if constant.Sign(y) == 0 {
+					return nil
+				}
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y) //integer division
+			}
+		case token.REM:
+			if constant.Sign(y) == 0 {
+				return nil
+			}
+		}
+		return constant.BinaryOp(x, actual.Op, y)
+	}
+	return nil
+}
+
+// constantValue converts constant value to its go counterpart
+func constantValue(value constant.Value) interface{} {
+	switch value.Kind() {
+	case constant.Bool:
+		return constant.BoolVal(value)
+	case constant.String:
+		return constant.StringVal(value)
+	case constant.Int:
+		if result, ok := constant.Int64Val(value); ok {
+			return result
+		}
+		if result, ok := constant.Uint64Val(value); ok {
+			return result
+		}
+		return value.ExactString()
+	case constant.Float:
+		result, _ := constant.Float64Val(value)
+		return result
+	}
+	return value.ExactString()
+}
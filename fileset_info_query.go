@@ -0,0 +1,140 @@
+package toolbox
+
+import (
+	"sort"
+	"strings"
+)
+
+// Implements returns true if a type implements supplied interface, if pointer is true the method set of a pointer to the type is used,
+// methods of embedded interfaces declared outside of the file set are not verified
+func (f *FileSetInfo) Implements(typeName, interfaceName string, pointer bool) bool {
+	interfaceInfo := f.Type(interfaceName)
+	if interfaceInfo == nil || !interfaceInfo.IsInterface || len(interfaceInfo.TypeSet) > 0 {
+		return false
+	}
+	typeInfo := f.Type(typeName)
+	if typeInfo == nil || typeInfo.IsInterface {
+		return false
+	}
+	var methodSet = make([]*FunctionInfo, 0)
+	f.collectMethodSet(typeName, pointer, make(map[string]bool), make(map[string]bool), &methodSet)
+	var methods = make(map[string]*FunctionInfo)
+	for _, method := range methodSet {
+		methods[method.Name] = method
+	}
+	for _, required := range f.MethodSet(interfaceName) {
+		method, ok := methods[required.Name]
+		if !ok || signature(method) != signature(required) {
+			return false
+		}
+	}
+	return true
+}
+
+// Implementations returns types whose pointer implements supplied interface, use Implements to check value method set
+func (f *FileSetInfo) Implementations(interfaceName string) []*TypeInfo {
+	var result = make([]*TypeInfo, 0)
+	for _, typeInfo := range f.sortedTypes() {
+		if f.Implements(typeInfo.Name, interfaceName, true) {
+			result = append(result, typeInfo)
+		}
+	}
+	return result
+}
+
+// FunctionsReturning returns package functions and methods returning supplied type, i.e. User matches User and *User results, *User matches *User result only
+func (f *FileSetInfo) FunctionsReturning(typeName string) []*FunctionInfo {
+	var result = make([]*FunctionInfo, 0)
+	for _, fileName := range f.fileNames() {
+		for receiverTypeName, functions := range f.files[fileName].functions {
+			if receiverTypeName != "" {
+				if typeInfo := f.Type(receiverTypeName); typeInfo == nil || typeInfo.IsInterface {
+					continue
+				}
+			}
+			for _, function := range functions {
+				if returnsType(function, typeName) {
+					result = append(result, function)
+				}
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].ReceiverTypeName != result[j].ReceiverTypeName {
+			return result[i].ReceiverTypeName < result[j].ReceiverTypeName
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// TypesEmbedding returns struct types with supplied embedded type and interfaces embedding supplied interface
+func (f *FileSetInfo) TypesEmbedding(typeName string) []*TypeInfo {
+	var result = make([]*TypeInfo, 0)
+	for _, typeInfo := range f.sortedTypes() {
+		if embeds(typeInfo, typeName) {
+			result = append(result, typeInfo)
+		}
+	}
+	return result
+}
+
+func embeds(typeInfo *TypeInfo, typeName string) bool {
+	for _, embedded := range typeInfo.EmbeddedInterfaces {
+		if baseTypeName(embedded) == typeName {
+			return true
+		}
+	}
+	for _, field := range typeInfo.Fields() {
+		if field.IsAnonymous && baseTypeName(field.TypeName) == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+func returnsType(function *FunctionInfo, typeName string) bool {
+	for _, result := range function.ResultsFields {
+		resultType := fieldTypeName(result)
+		if resultType == typeName || (!strings.HasPrefix(typeName, "*") && strings.TrimPrefix(resultType, "*") == typeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldTypeName returns field type as declared in the source, i.e. *User or ...string
+func fieldTypeName(field *FieldInfo) string {
+	typeName := field.TypeName
+	if field.IsPointer && !strings.HasPrefix(typeName, "*") {
+		typeName = "*" + typeName
+	}
+	if field.IsVariant {
+		typeName = "..." + strings.TrimPrefix(typeName, "[]")
+	}
+	return typeName
+}
+
+// signature returns function parameters and results types
+func signature(function *FunctionInfo) string {
+	var parameters = make([]string, len(function.ParameterFields))
+	for i, field := range function.ParameterFields {
+		parameters[i] = fieldTypeName(field)
+	}
+	var results = make([]string, len(function.ResultsFields))
+	for i, field := range function.ResultsFields {
+		results[i] = fieldTypeName(field)
+	}
+	return "(" + strings.Join(parameters, ",") + ")(" + strings.Join(results, ",") + ")"
+}
+
+func (f *FileSetInfo) sortedTypes() []*TypeInfo {
+	var result = make([]*TypeInfo, 0)
+	for _, fileInfo := range f.files {
+		result = append(result, fileInfo.Types()...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
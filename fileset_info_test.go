@@ -125,3 +125,158 @@ func TestNewFileSetInfoInfo(t *testing.T) {
 	assert.Equal(t, "time.Time", appointments.ComponentType)
 
 }
+
+func TestFileSetInfo_Model(t *testing.T) {
+	fileSetInfo, err := toolbox.NewFileSetInfo("./test/fileset_info/")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	list := fileSetInfo.Type("List")
+	if assert.NotNil(t, list) {
+		assert.True(t, list.IsGeneric())
+		assert.Equal(t, []*toolbox.TypeParamInfo{{Name: "T", Constraint: "any"}}, list.TypeParams)
+		items := list.Field("Items")
+		assert.Equal(t, map[string]string{"json": "items,omitempty", "yaml": "items"}, items.Tags)
+		assert.True(t, list.HasReceiver("Push")) //declared in generic_func.go
+		push := list.Receiver("Push")
+		assert.True(t, push.IsPointerReceiver)
+		assert.Equal(t, []string{"T"}, push.ReceiverTypeParams)
+	}
+
+	pair := fileSetInfo.Type("Pair")
+	if assert.NotNil(t, pair) {
+		assert.Equal(t, []*toolbox.TypeParamInfo{{Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "Number"}}, pair.TypeParams)
+		assert.True(t, pair.HasReceiver("Get"))
+		assert.False(t, pair.Receiver("Get").IsPointerReceiver)
+	}
+
+	holder := fileSetInfo.Type("Holder")
+	if assert.NotNil(t, holder) {
+		assert.Equal(t, 2, len(holder.Fields()))
+		embedded := holder.Field("List")
+		if assert.NotNil(t, embedded) {
+			assert.True(t, embedded.IsAnonymous)
+			assert.Equal(t, []string{"string"}, embedded.TypeArguments)
+		}
+		pairs := holder.Field("Pairs")
+		assert.Equal(t, []string{"string", "int"}, pairs.TypeArguments)
+		assert.Equal(t, "pairs", pairs.Tags["json"])
+		assert.Equal(t, []string{"Push"}, methodNames(fileSetInfo.MethodSet("Holder")))
+	}
+
+	kind := fileSetInfo.Type("Kind")
+	if assert.NotNil(t, kind) {
+		assert.True(t, kind.IsDerived)
+		assert.True(t, kind.HasReceiver("String")) //non struct type method declared in other file
+	}
+	assert.True(t, fileSetInfo.Type("Size").IsAlias)
+
+	number := fileSetInfo.Type("Number")
+	if assert.NotNil(t, number) {
+		assert.True(t, number.IsInterface)
+		assert.Equal(t, []string{"~int", "~int64", "float64"}, number.TypeSet)
+		assert.Equal(t, 0, len(number.Receivers()))
+	}
+
+	reader := fileSetInfo.Type("Reader")
+	if assert.NotNil(t, reader) {
+		assert.Equal(t, []string{"stdio.Closer", "Named"}, reader.EmbeddedInterfaces)
+		assert.Equal(t, 1, len(reader.Receivers()))
+		read := reader.Receiver("Read")
+		if assert.NotNil(t, read) {
+			assert.Equal(t, 2, len(read.ParameterFields))
+			assert.Equal(t, 2, len(read.ResultsFields))
+		}
+		assert.Equal(t, []string{"Read", "Name"}, methodNames(fileSetInfo.MethodSet("Reader")))
+	}
+
+	var constValues = map[string]interface{}{}
+	for _, constInfo := range fileSetInfo.Consts() {
+		constValues[constInfo.Name] = constInfo.Value
+	}
+	assert.Equal(t, map[string]interface{}{
+		"KindUnknown": int64(0),
+		"KindFile":    int64(1),
+		"KindDir":     int64(2),
+		"_":           int64(0),
+		"KindLink":    int64(12),
+		"KB":          int64(1024),
+		"MB":          int64(1048576),
+		"GB":          int64(1073741824),
+		"Prefix":      "pre",
+		"Label":       "prefix",
+		"Ratio":       1.5,
+		"Enabled":     true,
+		"Limit":       int64(536870912),
+	}, constValues)
+	kindFile := fileSetInfo.Const("KindFile")
+	if assert.NotNil(t, kindFile) {
+		assert.Equal(t, "Kind", kindFile.TypeName)
+		assert.Equal(t, "iota", kindFile.Expr)
+		assert.Equal(t, 1, kindFile.Iota)
+	}
+	assert.Equal(t, "unknown kind", fileSetInfo.Const("KindUnknown").Comment)
+
+	defaultList := fileSetInfo.Var("DefaultList")
+	if assert.NotNil(t, defaultList) {
+		assert.Equal(t, "&List[int]{}", defaultList.Expr)
+		assert.Equal(t, "DefaultList represents default list", defaultList.Comment)
+	}
+	assert.Equal(t, "int", fileSetInfo.Var("counter").TypeName)
+	assert.Equal(t, "yaml.Marshal", fileSetInfo.Var("encoder").Expr)
+
+	fileInfo := fileSetInfo.FileInfo("generic.go")
+	assert.Equal(t, 4, len(fileInfo.ImportsInfo()))
+	assert.Equal(t, &toolbox.ImportInfo{Name: "stdio", Alias: "stdio", Path: "io"}, fileInfo.Import("stdio"))
+	assert.Equal(t, &toolbox.ImportInfo{Name: "context", Path: "context"}, fileInfo.Import("context"))
+	assert.Equal(t, "_", fileInfo.Import("_").Alias)
+}
+
+func methodNames(methods []*toolbox.FunctionInfo) []string {
+	var result = make([]string, 0)
+	for _, method := range methods {
+		result = append(result, method.Name)
+	}
+	return result
+}
+
+func TestFileSetInfo_Queries(t *testing.T) {
+	fileSetInfo, err := toolbox.NewFileSetInfo("./test/fileset_info/")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"memoryStore", "readOnlyStore", "sharedStore"}, typeNames(fileSetInfo.Implementations("Store")))
+	assert.True(t, fileSetInfo.Implements("sharedStore", "Store", false))
+	assert.False(t, fileSetInfo.Implements("readOnlyStore", "Store", false))
+	assert.True(t, fileSetInfo.Implements("readOnlyStore", "Store", true))
+	assert.False(t, fileSetInfo.Implements("memoryStore", "Store", false))
+	assert.False(t, fileSetInfo.Implements("badStore", "Store", true))
+	assert.True(t, fileSetInfo.Implements("badStore", "Named", false))
+	assert.False(t, fileSetInfo.Implements("Kind", "Number", false))
+	assert.False(t, fileSetInfo.Implements("User", "Missing", false))
+
+	returning := fileSetInfo.FunctionsReturning("User")
+	var names = make([]string, 0)
+	for _, function := range returning {
+		names = append(names, function.ReceiverTypeName+"."+function.Name)
+	}
+	assert.Equal(t, []string{".NewUser", "badStore.Get", "memoryStore.First", "memoryStore.Get"}, names)
+	assert.Equal(t, 3, len(fileSetInfo.FunctionsReturning("*User")))
+	assert.Equal(t, 1, len(fileSetInfo.FunctionsReturning("[]*User")))
+	assert.Equal(t, 1, len(fileSetInfo.FunctionsReturning("Store")))
+	assert.Equal(t, 3, len(fileSetInfo.FileInfo("store.go").Functions()))
+
+	assert.Equal(t, []string{"readOnlyStore", "sharedStore"}, typeNames(fileSetInfo.TypesEmbedding("memoryStore")))
+	assert.Equal(t, []string{"Reader", "Store"}, typeNames(fileSetInfo.TypesEmbedding("Named")))
+	assert.Equal(t, []string{"Address"}, typeNames(fileSetInfo.TypesEmbedding("Country")))
+	assert.Equal(t, []string{"Holder"}, typeNames(fileSetInfo.TypesEmbedding("List")))
+}
+
+func typeNames(typesInfo []*toolbox.TypeInfo) []string {
+	var result = make([]string, 0)
+	for _, typeInfo := range typesInfo {
+		result = append(result, typeInfo.Name)
+	}
+	return result
+}
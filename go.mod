@@ -0,0 +1,18 @@
+module github.com/viant/toolbox
+
+go 1.18
+
+require (
+	cloud.google.com/go/storage v1.27.0
+	github.com/aws/aws-sdk-go v1.45.27
+	github.com/go-errors/errors v1.5.1
+	github.com/lunixbochs/vtclean v1.0.0
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.8.1
+	github.com/viant/xunsafe v0.9.3-0.20240530173106-69808f27713b
+	golang.org/x/crypto v0.24.0
+	golang.org/x/net v0.26.0
+	golang.org/x/oauth2 v0.21.0
+	google.golang.org/api v0.98.0
+	gopkg.in/yaml.v2 v2.4.0
+)
@@ -1,6 +1,7 @@
 package toolbox
 
 import (
+	"io"
 	"reflect"
 	"time"
 )
@@ -14,11 +15,56 @@ type Iterator interface {
 	Next(itemPointer interface{}) error
 }
 
+//StreamIterator represents an iterator that can fail while reading ahead and holds underlying resources.
+//HasNext returns false on read error, Err reports it.
+type StreamIterator interface {
+	Iterator
+
+	//Err returns the first error encountered while reading ahead, nil at regular end of the stream
+	Err() error
+
+	//Close releases underlying resources
+	Close() error
+}
+
+type streamIterator struct {
+	Iterator
+}
+
+//Err returns nil, wrapped iterator can only report errors with Next
+func (i *streamIterator) Err() error {
+	return nil
+}
+
+//Close closes wrapped iterator if it implements io.Closer
+func (i *streamIterator) Close() error {
+	if closer, ok := i.Iterator.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+//AsStreamIterator returns supplied iterator as StreamIterator, iterator not implementing Err and Close gets wrapped
+func AsStreamIterator(iterator Iterator) StreamIterator {
+	if result, ok := iterator.(StreamIterator); ok {
+		return result
+	}
+	return &streamIterator{Iterator: iterator}
+}
+
 type sliceIterator struct {
 	sliceValue reflect.Value
 	index      int
 }
 
+func (i *sliceIterator) Err() error {
+	return nil
+}
+
+func (i *sliceIterator) Close() error {
+	return nil
+}
+
 func (i *sliceIterator) HasNext() bool {
 	return i.index < i.sliceValue.Len()
 }
@@ -36,6 +82,14 @@ type stringSliceIterator struct {
 	index      int
 }
 
+func (i *stringSliceIterator) Err() error {
+	return nil
+}
+
+func (i *stringSliceIterator) Close() error {
+	return nil
+}
+
 func (i *stringSliceIterator) HasNext() bool {
 	return i.index < len(i.sliceValue)
 }
@@ -57,6 +111,14 @@ type interfaceSliceIterator struct {
 	index      int
 }
 
+func (i *interfaceSliceIterator) Err() error {
+	return nil
+}
+
+func (i *interfaceSliceIterator) Close() error {
+	return nil
+}
+
 func (i *interfaceSliceIterator) HasNext() bool {
 	return i.index < len(i.sliceValue)
 }
@@ -0,0 +1,134 @@
+package iterator
+
+import (
+	"fmt"
+
+	"github.com/viant/toolbox"
+)
+
+// Map returns iterator of source items transformed by mapper
+func Map(source toolbox.Iterator, mapper func(item interface{}) (interface{}, error)) toolbox.StreamIterator {
+	return newLazy(func() (interface{}, bool, error) {
+		item, ok, err := read(source)
+		if !ok || err != nil {
+			return nil, false, err
+		}
+		mapped, err := mapper(item)
+		return mapped, err == nil, err
+	}, source)
+}
+
+// Filter returns iterator of source items matching predicate
+func Filter(source toolbox.Iterator, predicate func(item interface{}) (bool, error)) toolbox.StreamIterator {
+	return newLazy(func() (interface{}, bool, error) {
+		for {
+			item, ok, err := read(source)
+			if !ok || err != nil {
+				return nil, false, err
+			}
+			matched, err := predicate(item)
+			if err != nil {
+				return nil, false, err
+			}
+			if matched {
+				return item, true, nil
+			}
+		}
+	}, source)
+}
+
+// Take returns iterator of at most count first source items
+func Take(source toolbox.Iterator, count int) toolbox.StreamIterator {
+	var taken = 0
+	return newLazy(func() (interface{}, bool, error) {
+		if taken >= count {
+			return nil, false, nil
+		}
+		taken++
+		return read(source)
+	}, source)
+}
+
+// Skip returns iterator of source items without count first items
+func Skip(source toolbox.Iterator, count int) toolbox.StreamIterator {
+	var skipped = 0
+	return newLazy(func() (interface{}, bool, error) {
+		for ; skipped < count; skipped++ {
+			if _, ok, err := read(source); !ok || err != nil {
+				return nil, false, err
+			}
+		}
+		return read(source)
+	}, source)
+}
+
+// Chunk returns iterator of []interface{} chunks with size source items, the last chunk can be smaller
+func Chunk(source toolbox.Iterator, size int) toolbox.StreamIterator {
+	return newLazy(func() (interface{}, bool, error) {
+		if size <= 0 {
+			return nil, false, fmt.Errorf("invalid chunk size: %v", size)
+		}
+		var chunk = make([]interface{}, 0, size)
+		for len(chunk) < size {
+			item, ok, err := read(source)
+			if err != nil {
+				return nil, false, err
+			}
+			if !ok {
+				break
+			}
+			chunk = append(chunk, item)
+		}
+		return chunk, len(chunk) > 0, nil
+	}, source)
+}
+
+// Zip returns iterator of []interface{} pairs with left and right items, iteration stops with the shorter source
+func Zip(left, right toolbox.Iterator) toolbox.StreamIterator {
+	return newLazy(func() (interface{}, bool, error) {
+		leftItem, ok, err := read(left)
+		if !ok || err != nil {
+			return nil, false, err
+		}
+		rightItem, ok, err := read(right)
+		if !ok || err != nil {
+			return nil, false, err
+		}
+		return []interface{}{leftItem, rightItem}, true, nil
+	}, left, right)
+}
+
+// MergeSorted returns iterator merging items of sorted sources, ties are taken from the earlier source
+func MergeSorted(less func(left, right interface{}) bool, sources ...toolbox.Iterator) toolbox.StreamIterator {
+	var heads = make([]interface{}, len(sources))
+	var hasHead = make([]bool, len(sources))
+	var initialised = false
+	return newLazy(func() (interface{}, bool, error) {
+		if !initialised {
+			initialised = true
+			for i, source := range sources {
+				item, ok, err := read(source)
+				if err != nil {
+					return nil, false, err
+				}
+				heads[i], hasHead[i] = item, ok
+			}
+		}
+		var selected = -1
+		for i := range sources {
+			if hasHead[i] && (selected == -1 || less(heads[i], heads[selected])) {
+				selected = i
+			}
+		}
+		if selected == -1 {
+			return nil, false, nil
+		}
+		result := heads[selected]
+		item, ok, err := read(sources[selected])
+		if err != nil {
+			return nil, false, err
+		}
+		heads[selected], hasHead[selected] = item, ok
+		return result, true, nil
+	}, sources...)
+}
@@ -0,0 +1,133 @@
+package iterator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+type failingIterator struct {
+	items  []interface{}
+	index  int
+	err    error
+	closed bool
+}
+
+func (i *failingIterator) HasNext() bool {
+	return i.index < len(i.items)
+}
+
+func (i *failingIterator) Next(itemPointer interface{}) error {
+	*(itemPointer.(*interface{})) = i.items[i.index]
+	i.index++
+	return nil
+}
+
+func (i *failingIterator) Err() error {
+	return i.err
+}
+
+func (i *failingIterator) Close() error {
+	i.closed = true
+	return nil
+}
+
+func TestCombinators(t *testing.T) {
+	double := func(item interface{}) (interface{}, error) { return item.(int) * 2, nil }
+	odd := func(item interface{}) (bool, error) { return item.(int)%2 == 1, nil }
+	numbers := func() toolbox.Iterator { return toolbox.NewSliceIterator([]interface{}{1, 2, 3, 4, 5}) }
+
+	var useCases = []struct {
+		description string
+		iterator    toolbox.StreamIterator
+		expect      []interface{}
+	}{
+		{description: "map", iterator: Map(numbers(), double), expect: []interface{}{2, 4, 6, 8, 10}},
+		{description: "filter", iterator: Filter(numbers(), odd), expect: []interface{}{1, 3, 5}},
+		{description: "take", iterator: Take(numbers(), 2), expect: []interface{}{1, 2}},
+		{description: "take more", iterator: Take(numbers(), 10), expect: []interface{}{1, 2, 3, 4, 5}},
+		{description: "skip", iterator: Skip(numbers(), 3), expect: []interface{}{4, 5}},
+		{description: "skip all", iterator: Skip(numbers(), 7), expect: []interface{}{}},
+		{description: "chunk", iterator: Chunk(numbers(), 2), expect: []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}, []interface{}{5}}},
+		{
+			description: "zip",
+			iterator:    Zip(numbers(), toolbox.NewSliceIterator([]string{"a", "b"})),
+			expect:      []interface{}{[]interface{}{1, "a"}, []interface{}{2, "b"}},
+		},
+		{
+			description: "merge sorted",
+			iterator: MergeSorted(func(left, right interface{}) bool { return left.(int) < right.(int) },
+				toolbox.NewSliceIterator([]interface{}{1, 4, 9}),
+				toolbox.NewSliceIterator([]interface{}{}),
+				toolbox.NewSliceIterator([]interface{}{2, 3, 10})),
+			expect: []interface{}{1, 2, 3, 4, 9, 10},
+		},
+		{
+			description: "pipeline",
+			iterator:    Take(Map(Filter(Skip(numbers(), 1), odd), double), 1),
+			expect:      []interface{}{6},
+		},
+	}
+	for _, useCase := range useCases {
+		actual, err := Collect(useCase.iterator)
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestLazy_Next(t *testing.T) {
+	iterator := Map(toolbox.NewSliceIterator([]interface{}{"1", "2"}), func(item interface{}) (interface{}, error) {
+		return item, nil
+	})
+	var value int
+	assert.Nil(t, iterator.Next(&value))
+	assert.Equal(t, 1, value)
+	var text string
+	assert.Nil(t, iterator.Next(&text))
+	assert.Equal(t, "2", text)
+	assert.False(t, iterator.HasNext())
+	assert.Equal(t, ErrNoMoreItems, iterator.Next(&text))
+	assert.Nil(t, iterator.Err())
+}
+
+func TestLazy_Err(t *testing.T) {
+	source := &failingIterator{items: []interface{}{1, 2}, err: errors.New("read failed")}
+	iterator := Chunk(source, 5)
+	assert.False(t, iterator.HasNext())
+	assert.EqualError(t, iterator.Err(), "read failed")
+	assert.Nil(t, iterator.Close())
+	assert.True(t, source.closed)
+
+	mapped := Map(toolbox.NewSliceIterator([]interface{}{1}), func(item interface{}) (interface{}, error) {
+		return nil, errors.New("map failed")
+	})
+	_, err := Collect(mapped)
+	assert.EqualError(t, err, "map failed")
+}
+
+func TestCompactedSliceIterator(t *testing.T) {
+	collection := data.NewCompactedSlice(false, true)
+	for i := 0; i < 5; i++ {
+		collection.Add(map[string]interface{}{"id": i, "name": "n"})
+	}
+	ids := Map(Filter(collection.Iterator(), func(item interface{}) (bool, error) {
+		return toolbox.AsMap(item)["id"].(int) > 2, nil
+	}), func(item interface{}) (interface{}, error) {
+		return toolbox.AsMap(item)["id"], nil
+	})
+	actual, err := Collect(ids)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{3, 4}, actual)
+}
+
+func TestDecoderIterator(t *testing.T) {
+	input := "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"
+	chunks := Chunk(Skip(toolbox.NewJSONLinesIterator(strings.NewReader(input)), 1), 5)
+	actual, err := Collect(chunks)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{[]interface{}{map[string]interface{}{"id": 2.0}, map[string]interface{}{"id": 3.0}}}, actual)
+}
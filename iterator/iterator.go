@@ -0,0 +1,134 @@
+// Package iterator provides lazy combinators over toolbox.Iterator, i.e. NewSliceIterator, CompactedSlice.Iterator or decoder backed streams.
+// Combinators read source items into interface{}, read errors stop the pipeline and are reported by Err.
+package iterator
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/viant/toolbox"
+)
+
+// ErrNoMoreItems is returned by Next when iterator has no more items
+var ErrNoMoreItems = errors.New("no more items")
+
+// fetcher returns next item, false at the end of the stream
+type fetcher func() (interface{}, bool, error)
+
+// lazy represents read ahead iterator backed by fetcher
+type lazy struct {
+	fetch   fetcher
+	closers []toolbox.StreamIterator
+	item    interface{}
+	fetched bool
+	done    bool
+	err     error
+}
+
+// HasNext returns true if iterator has next element, it returns false on read error
+func (i *lazy) HasNext() bool {
+	if i.fetched {
+		return true
+	}
+	if i.done {
+		return false
+	}
+	item, ok, err := i.fetch()
+	if err != nil || !ok {
+		i.err = err
+		i.done = true
+		return false
+	}
+	i.item, i.fetched = item, true
+	return true
+}
+
+// Next sets item pointer with next element
+func (i *lazy) Next(itemPointer interface{}) error {
+	if !i.HasNext() {
+		if i.err != nil {
+			return i.err
+		}
+		return ErrNoMoreItems
+	}
+	item := i.item
+	i.item, i.fetched = nil, false
+	return assign(itemPointer, item)
+}
+
+// Err returns the first read error
+func (i *lazy) Err() error {
+	return i.err
+}
+
+// Close closes all source iterators, the first error is returned
+func (i *lazy) Close() error {
+	i.done, i.fetched, i.item = true, false, nil
+	var result error
+	for _, closer := range i.closers {
+		if err := closer.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func newLazy(fetch fetcher, sources ...toolbox.Iterator) *lazy {
+	var closers = make([]toolbox.StreamIterator, len(sources))
+	for i, source := range sources {
+		closers[i] = toolbox.AsStreamIterator(source)
+	}
+	return &lazy{fetch: fetch, closers: closers}
+}
+
+// read returns next source item, false at the end of the source, source Err is returned once source is exhausted
+func read(source toolbox.Iterator) (interface{}, bool, error) {
+	if !source.HasNext() {
+		return nil, false, toolbox.AsStreamIterator(source).Err()
+	}
+	var item interface{}
+	if err := source.Next(&item); err != nil {
+		return nil, false, err
+	}
+	return item, true, nil
+}
+
+// assign sets item pointer with the item, converting it if needed
+func assign(itemPointer interface{}, item interface{}) error {
+	if pointer, ok := itemPointer.(*interface{}); ok {
+		*pointer = item
+		return nil
+	}
+	pointerValue := reflect.ValueOf(itemPointer)
+	if pointerValue.Kind() != reflect.Ptr || pointerValue.IsNil() {
+		return errors.New("expected non nil item pointer")
+	}
+	target := pointerValue.Elem()
+	if item == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	value := reflect.ValueOf(item)
+	if value.Type().AssignableTo(target.Type()) {
+		target.Set(value)
+		return nil
+	}
+	return toolbox.DefaultConverter.AssignConverted(itemPointer, item)
+}
+
+// Collect reads all remaining items and closes the iterator
+func Collect(source toolbox.Iterator) ([]interface{}, error) {
+	stream := toolbox.AsStreamIterator(source)
+	defer stream.Close()
+	var result = make([]interface{}, 0)
+	for {
+		item, ok, err := read(stream)
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			return result, nil
+		}
+		result = append(result, item)
+	}
+}
@@ -98,3 +98,13 @@ func TestSliceIterator(t *testing.T) {
 	}
 
 }
+
+func TestAsStreamIterator(t *testing.T) {
+	iterator := toolbox.AsStreamIterator(toolbox.NewSliceIterator([]int{1, 2}))
+	var value int
+	assert.True(t, iterator.HasNext())
+	assert.Nil(t, iterator.Next(&value))
+	assert.Equal(t, 1, value)
+	assert.Nil(t, iterator.Err())
+	assert.Nil(t, iterator.Close())
+}
@@ -0,0 +1,335 @@
+package toolbox
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ModuleInfo represents a source model of all packages of a go module
+type ModuleInfo struct {
+	Path      string //module path declared in go.mod
+	GoVersion string //go directive declared in go.mod
+	BaseDir   string //module root directory
+	packages  map[string]*PackageInfo
+}
+
+// PackageInfo represents a module package source model
+type PackageInfo struct {
+	Name       string
+	ImportPath string
+	Dir        string
+	Imports    []string //import paths used by package files
+	*FileSetInfo
+	module *ModuleInfo
+}
+
+// Package returns package info for supplied import path
+func (m *ModuleInfo) Package(importPath string) *PackageInfo {
+	return m.packages[importPath]
+}
+
+// Packages returns all module packages sorted by import path
+func (m *ModuleInfo) Packages() []*PackageInfo {
+	var result = make([]*PackageInfo, 0, len(m.packages))
+	for _, importPath := range m.importPaths() {
+		result = append(result, m.packages[importPath])
+	}
+	return result
+}
+
+// Type returns type info for supplied package import path and type name
+func (m *ModuleInfo) Type(importPath, typeName string) *TypeInfo {
+	pkg := m.Package(importPath)
+	if pkg == nil {
+		return nil
+	}
+	return pkg.Type(typeName)
+}
+
+// Graph returns module package graph: package import path with import paths of module packages it depends on
+func (m *ModuleInfo) Graph() map[string][]string {
+	var result = make(map[string][]string)
+	for importPath, pkg := range m.packages {
+		result[importPath] = pkg.ModuleImports()
+	}
+	return result
+}
+
+// Dependents returns import paths of module packages importing supplied package
+func (m *ModuleInfo) Dependents(importPath string) []string {
+	var result = make([]string, 0)
+	for _, candidate := range m.importPaths() {
+		for _, imported := range m.packages[candidate].Imports {
+			if imported == importPath {
+				result = append(result, candidate)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (m *ModuleInfo) importPaths() []string {
+	var result = make([]string, 0, len(m.packages))
+	for importPath := range m.packages {
+		result = append(result, importPath)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ModuleImports returns import paths of module packages used by this package
+func (p *PackageInfo) ModuleImports() []string {
+	var result = make([]string, 0)
+	for _, importPath := range p.Imports {
+		if _, ok := p.module.packages[importPath]; ok {
+			result = append(result, importPath)
+		}
+	}
+	return result
+}
+
+// ResolveType returns type info with its package for a type name as referenced in supplied file of this package,
+// i.e. data.Map, *Item, []*model.User or map[string]model.User, it returns nil if type is not declared in the module
+func (p *PackageInfo) ResolveType(fileName, typeName string) (*TypeInfo, *PackageInfo) {
+	typeInfo, pkg, _ := p.resolveType(fileName, typeName)
+	return typeInfo, pkg
+}
+
+func (p *PackageInfo) resolveType(fileName, typeName string) (*TypeInfo, *PackageInfo, string) {
+	typeName = baseTypeName(typeName)
+	fileInfo := p.FileInfo(fileName)
+	if index := strings.Index(typeName, "."); index != -1 {
+		if fileInfo == nil {
+			return nil, nil, ""
+		}
+		importInfo := fileInfo.Import(typeName[:index])
+		if importInfo == nil {
+			return nil, nil, ""
+		}
+		pkg := p.module.Package(importInfo.Path)
+		if pkg == nil {
+			return nil, nil, importInfo.Path
+		}
+		if typeInfo := pkg.Type(typeName[index+1:]); typeInfo != nil {
+			return typeInfo, pkg, pkg.ImportPath
+		}
+		return nil, nil, importInfo.Path
+	}
+	if typeInfo := p.Type(typeName); typeInfo != nil {
+		return typeInfo, p, p.ImportPath
+	}
+	if fileInfo == nil {
+		return nil, nil, ""
+	}
+	for _, importInfo := range fileInfo.ImportsInfo() { //dot imports
+		if importInfo.Alias != "." {
+			continue
+		}
+		if pkg := p.module.Package(importInfo.Path); pkg != nil {
+			if typeInfo := pkg.Type(typeName); typeInfo != nil {
+				return typeInfo, pkg, pkg.ImportPath
+			}
+		}
+	}
+	return nil, nil, ""
+}
+
+// linkFields resolves field types of all package types and functions
+func (p *PackageInfo) linkFields() {
+	for fileName, fileInfo := range p.FilesInfo() {
+		for _, typeInfo := range fileInfo.Types() {
+			p.linkFieldInfos(fileName, typeInfo.Fields())
+			for _, receiver := range typeInfo.Receivers() {
+				receiverFileName := fileName
+				if receiver.FileInfo != nil {
+					receiverFileName = receiver.FileInfo.filename
+				}
+				p.linkFieldInfos(receiverFileName, receiver.ParameterFields)
+				p.linkFieldInfos(receiverFileName, receiver.ResultsFields)
+			}
+		}
+		for _, function := range fileInfo.Functions() {
+			p.linkFieldInfos(fileName, function.ParameterFields)
+			p.linkFieldInfos(fileName, function.ResultsFields)
+		}
+	}
+}
+
+func (p *PackageInfo) linkFieldInfos(fileName string, fields []*FieldInfo) {
+	for _, field := range fields {
+		field.resolvedType, _, field.TypeImportPath = p.resolveType(fileName, field.TypeName)
+	}
+}
+
+// baseTypeName returns named type referenced by type expression, i.e. model.User for []*model.User or map[string]model.User
+func baseTypeName(typeName string) string {
+	for {
+		typeName = strings.TrimSpace(typeName)
+		switch {
+		case strings.HasPrefix(typeName, "*"):
+			typeName = typeName[1:]
+		case strings.HasPrefix(typeName, "..."):
+			typeName = typeName[3:]
+		case strings.HasPrefix(typeName, "<-chan "):
+			typeName = typeName[7:]
+		case strings.HasPrefix(typeName, "chan<- "):
+			typeName = typeName[7:]
+		case strings.HasPrefix(typeName, "chan "):
+			typeName = typeName[5:]
+		case strings.HasPrefix(typeName, "map["):
+			typeName = typeName[closingBracket(typeName, 3)+1:]
+		case strings.HasPrefix(typeName, "["):
+			typeName = typeName[closingBracket(typeName, 0)+1:]
+		default:
+			if index := strings.Index(typeName, "["); index != -1 { //type arguments
+				typeName = typeName[:index]
+			}
+			return typeName
+		}
+	}
+}
+
+func closingBracket(text string, from int) int {
+	depth := 0
+	for i := from; i < len(text); i++ {
+		switch text[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(text) - 1
+}
+
+// NewModuleInfo loads all packages of a go module, baseDir can be module root or any directory within a module,
+// test files, testdata, vendor, hidden directories and nested modules are skipped
+func NewModuleInfo(baseDir string) (*ModuleInfo, error) {
+	moduleDir, err := findModuleDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	result := &ModuleInfo{BaseDir: moduleDir, packages: make(map[string]*PackageInfo)}
+	if err = result.readGoMod(); err != nil {
+		return nil, err
+	}
+	err = filepath.Walk(moduleDir, func(dir string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if dir != moduleDir {
+			name := info.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+				return filepath.SkipDir
+			}
+			if FileExists(path.Join(dir, "go.mod")) {
+				return filepath.SkipDir
+			}
+		}
+		return result.loadPackage(dir)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range result.packages {
+		pkg.linkFields()
+	}
+	return result, nil
+}
+
+func (m *ModuleInfo) loadPackage(dir string) error {
+	fileSetInfo, err := newFileSetInfo(dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	})
+	if err != nil {
+		return err
+	}
+	if len(fileSetInfo.FilesInfo()) == 0 {
+		return nil
+	}
+	relative, err := filepath.Rel(m.BaseDir, dir)
+	if err != nil {
+		return err
+	}
+	importPath := m.Path
+	if relative != "." {
+		importPath = m.Path + "/" + filepath.ToSlash(relative)
+	}
+	pkg := &PackageInfo{ImportPath: importPath, Dir: dir, FileSetInfo: fileSetInfo, module: m}
+	var imports = make(map[string]bool)
+	for _, fileInfo := range fileSetInfo.FilesInfo() {
+		pkg.Name = fileInfo.PackageName()
+		for _, importInfo := range fileInfo.ImportsInfo() {
+			imports[importInfo.Path] = true
+		}
+	}
+	pkg.Imports = make([]string, 0, len(imports))
+	for importPath := range imports {
+		pkg.Imports = append(pkg.Imports, importPath)
+	}
+	sort.Strings(pkg.Imports)
+	m.packages[importPath] = pkg
+	return nil
+}
+
+func (m *ModuleInfo) readGoMod() error {
+	file, err := os.Open(path.Join(m.BaseDir, "go.mod"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, "//"); index != -1 {
+			line = line[:index]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			m.Path = strings.Trim(fields[1], "\"`")
+		case "go":
+			m.GoVersion = fields[1]
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	if m.Path == "" {
+		return fmt.Errorf("module path was empty in %v", path.Join(m.BaseDir, "go.mod"))
+	}
+	return nil
+}
+
+// findModuleDir returns the closest directory with go.mod file
+func findModuleDir(baseDir string) (string, error) {
+	dir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if FileExists(path.Join(dir, "go.mod")) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("failed to locate go.mod for %v", baseDir)
+		}
+		dir = parent
+	}
+}
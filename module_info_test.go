@@ -0,0 +1,107 @@
+package toolbox_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+func TestNewModuleInfo(t *testing.T) {
+	moduleInfo, err := toolbox.NewModuleInfo("./test/module/model")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "example.com/shop", moduleInfo.Path)
+	assert.Equal(t, "1.21", moduleInfo.GoVersion)
+
+	var importPaths = make([]string, 0)
+	for _, pkg := range moduleInfo.Packages() {
+		importPaths = append(importPaths, pkg.ImportPath)
+	}
+	assert.Equal(t, []string{"example.com/shop", "example.com/shop/catalog", "example.com/shop/model", "example.com/shop/model/customer"}, importPaths)
+
+	model := moduleInfo.Package("example.com/shop/model")
+	if !assert.NotNil(t, model) {
+		return
+	}
+	assert.Equal(t, "model", model.Name)
+	assert.Nil(t, model.Type("Fixture"), "test files should be skipped")
+
+	order := model.Type("Order")
+	if !assert.NotNil(t, order) {
+		return
+	}
+	var useCases = []struct {
+		field      string
+		importPath string
+		typeName   string
+	}{
+		{field: "ID"},
+		{field: "Customer", importPath: "example.com/shop/model/customer", typeName: "Customer"},
+		{field: "Items", importPath: "example.com/shop/catalog", typeName: "Product"},
+		{field: "Lines", importPath: "example.com/shop/catalog", typeName: "Product"},
+		{field: "Status", importPath: "example.com/shop/model", typeName: "Status"},
+		{field: "Created", importPath: "time"},
+	}
+	for _, useCase := range useCases {
+		field := order.Field(useCase.field)
+		if !assert.NotNil(t, field, useCase.field) {
+			continue
+		}
+		assert.Equal(t, useCase.importPath, field.TypeImportPath, useCase.field)
+		if useCase.typeName == "" {
+			assert.Nil(t, field.ResolvedType(), useCase.field)
+			continue
+		}
+		if assert.NotNil(t, field.ResolvedType(), useCase.field) {
+			assert.Equal(t, useCase.typeName, field.ResolvedType().Name, useCase.field)
+		}
+	}
+
+	discount := order.Receiver("Total").ParameterFields[0]
+	assert.Equal(t, "example.com/shop/catalog", discount.TypeImportPath)
+
+	var newOrder *toolbox.FunctionInfo
+	for _, fileInfo := range model.FilesInfo() {
+		for _, function := range fileInfo.Functions() {
+			if function.Name == "NewOrder" {
+				newOrder = function
+			}
+		}
+	}
+	if assert.NotNil(t, newOrder) {
+		assert.Equal(t, "example.com/shop/model/customer", newOrder.ParameterFields[0].TypeImportPath)
+		if items := newOrder.ParameterFields[1]; assert.NotNil(t, items.ResolvedType()) {
+			assert.Equal(t, "Product", items.ResolvedType().Name)
+		}
+		if result := newOrder.ResultsFields[0]; assert.NotNil(t, result.ResolvedType()) {
+			assert.Equal(t, "example.com/shop/model", result.TypeImportPath)
+		}
+	}
+
+	product := moduleInfo.Type("example.com/shop/catalog", "Product")
+	if assert.NotNil(t, product) {
+		owner := product.Field("Owner")
+		assert.Equal(t, "example.com/shop/model/customer", owner.TypeImportPath, "dot import")
+	}
+	typeInfo, pkg := model.ResolveType("order.go", "[]*cat.Product")
+	if assert.NotNil(t, typeInfo) {
+		assert.Equal(t, "Product", typeInfo.Name)
+		assert.Equal(t, "example.com/shop/catalog", pkg.ImportPath)
+	}
+	typeInfo, _ = model.ResolveType("order.go", "map[string][]customer.Customer")
+	assert.NotNil(t, typeInfo)
+
+	assert.Equal(t, map[string][]string{
+		"example.com/shop":                {"example.com/shop/model"},
+		"example.com/shop/catalog":        {"example.com/shop/model/customer"},
+		"example.com/shop/model":          {"example.com/shop/catalog", "example.com/shop/model/customer"},
+		"example.com/shop/model/customer": {},
+	}, moduleInfo.Graph())
+	assert.Equal(t, []string{"example.com/shop/catalog", "example.com/shop/model"}, moduleInfo.Dependents("example.com/shop/model/customer"))
+	assert.Equal(t, []string{"example.com/shop/model", "fmt"}, moduleInfo.Package("example.com/shop").Imports)
+
+	_, err = toolbox.NewModuleInfo("/")
+	assert.NotNil(t, err)
+}
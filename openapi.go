@@ -0,0 +1,506 @@
+package toolbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	openAPIVersion         = "3.0.3"
+	openAPIComponentPrefix = "#/components/schemas/"
+	yamlContentType        = "application/yaml"
+)
+
+//OpenAPIOptions represents OpenAPI document info options
+type OpenAPIOptions struct {
+	Title       string
+	Description string
+	Version     string
+	Servers     []string //server URLs
+}
+
+//OpenAPIDocument represents OpenAPI 3 document
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi" yaml:"openapi"`
+	Info       *OpenAPIInfo                            `json:"info" yaml:"info"`
+	Servers    []*OpenAPIServer                        `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths" yaml:"paths"` //path template to lower case HTTP method operation
+	Components *OpenAPIComponents                      `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+//OpenAPIInfo represents OpenAPI document info
+type OpenAPIInfo struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+//OpenAPIServer represents OpenAPI server
+type OpenAPIServer struct {
+	URL string `json:"url" yaml:"url"`
+}
+
+//OpenAPIOperation represents OpenAPI path operation
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses" yaml:"responses"`
+}
+
+//OpenAPIParameter represents OpenAPI path or query parameter
+type OpenAPIParameter struct {
+	Name     string         `json:"name" yaml:"name"`
+	In       string         `json:"in" yaml:"in"`
+	Required bool           `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   *OpenAPISchema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+//OpenAPIRequestBody represents OpenAPI request body
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content" yaml:"content"`
+}
+
+//OpenAPIResponse represents OpenAPI response
+type OpenAPIResponse struct {
+	Description string                       `json:"description" yaml:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+//OpenAPIMediaType represents OpenAPI media type
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+//OpenAPIComponents represents OpenAPI reusable components
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
+
+//OpenAPISchema represents OpenAPI schema
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string                    `json:"format,omitempty" yaml:"format,omitempty"`
+	Description          string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+}
+
+//JSON returns JSON encoded document
+func (d *OpenAPIDocument) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+//YAML returns YAML encoded document
+func (d *OpenAPIDocument) YAML() ([]byte, error) {
+	return yaml.Marshal(d)
+}
+
+//openAPISchemas builds component schemas for struct types
+type openAPISchemas struct {
+	schemas map[string]*OpenAPISchema
+	types   map[reflect.Type]string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+//schema returns schema for the type, named struct types are defined as components and referenced
+func (s *openAPISchemas) schema(sourceType reflect.Type) *OpenAPISchema {
+	for sourceType.Kind() == reflect.Ptr {
+		sourceType = sourceType.Elem()
+	}
+	if sourceType == timeType {
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	}
+	switch sourceType.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if sourceType.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: s.schema(sourceType.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: s.schema(sourceType.Elem())}
+	case reflect.Struct:
+		if sourceType.Name() == "" {
+			return s.structSchema(sourceType)
+		}
+		name, ok := s.types[sourceType]
+		if !ok {
+			name = s.componentName(sourceType)
+			s.types[sourceType] = name
+			s.schemas[name] = &OpenAPISchema{}
+			*s.schemas[name] = *s.structSchema(sourceType)
+		}
+		return &OpenAPISchema{Ref: openAPIComponentPrefix + name}
+	}
+	return &OpenAPISchema{}
+}
+
+func (s *openAPISchemas) componentName(sourceType reflect.Type) string {
+	name := sourceType.Name()
+	if _, taken := s.schemas[name]; !taken {
+		return name
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, sourceType.PkgPath()) + "_" + name
+}
+
+//structSchema returns object schema with struct fields discovered with GetStructMeta, embedded struct fields are flattened by ProcessStruct
+func (s *openAPISchemas) structSchema(structType reflect.Type) *OpenAPISchema {
+	var result = &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	meta := GetStructMeta(reflect.New(structType).Interface())
+	for _, fieldMeta := range meta.Fields {
+		field, ok := structType.FieldByName(fieldMeta.Name)
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+		name := fieldMeta.Name
+		if jsonName := strings.Split(field.Tag.Get("json"), ",")[0]; jsonName != "" {
+			name = jsonName
+		}
+		if _, defined := result.Properties[name]; defined {
+			continue
+		}
+		fieldSchema := s.schema(field.Type)
+		if fieldSchema.Ref == "" {
+			fieldSchema.Description = fieldMeta.Description
+			applyValidationTags(fieldSchema, field.Tag)
+		}
+		result.Properties[name] = fieldSchema
+		if fieldMeta.Required {
+			result.Required = append(result.Required, name)
+		}
+	}
+	sort.Strings(result.Required)
+	return result
+}
+
+//applyValidationTags sets schema constraints from min, max, pattern and oneof tags
+func applyValidationTags(schema *OpenAPISchema, tag reflect.StructTag) {
+	for _, bound := range []string{ValidationMin, ValidationMax} {
+		value, ok := tag.Lookup(bound)
+		if !ok {
+			continue
+		}
+		number, err := ToFloat(value)
+		if err != nil {
+			continue
+		}
+		length := int(number)
+		switch schema.Type {
+		case "string":
+			if bound == ValidationMin {
+				schema.MinLength = &length
+			} else {
+				schema.MaxLength = &length
+			}
+		case "array":
+			if bound == ValidationMin {
+				schema.MinItems = &length
+			} else {
+				schema.MaxItems = &length
+			}
+		case "integer", "number":
+			if bound == ValidationMin {
+				schema.Minimum = &number
+			} else {
+				schema.Maximum = &number
+			}
+		}
+	}
+	if value, ok := tag.Lookup(ValidationPattern); ok {
+		schema.Pattern = value
+	}
+	if value, ok := tag.Lookup(ValidationOneOf); ok {
+		schema.Enum = strings.Split(value, ",")
+	}
+}
+
+//openAPIPath returns OpenAPI path template and path parameter schemas for routing URI
+func openAPIPath(URI string) (string, map[string]*OpenAPISchema, []string) {
+	var schemas = make(map[string]*OpenAPISchema)
+	var names = make([]string, 0)
+	segments := splitRoutePath(URI)
+	for i, segment := range segments {
+		node, err := newRouteNode(segment, i+1 == len(segments))
+		if err != nil {
+			continue
+		}
+		switch node.kind {
+		case segmentCatchAll, segmentParam:
+			segments[i] = "{" + node.name + "}"
+			names = append(names, node.name)
+		case segmentTyped:
+			segments[i] = "{" + node.name + "}"
+			names = append(names, node.name)
+			typeName := segment[len(node.name)+2 : len(segment)-1]
+			switch typeName {
+			case "int":
+				schemas[node.name] = &OpenAPISchema{Type: "integer", Format: "int64"}
+			case "float":
+				schemas[node.name] = &OpenAPISchema{Type: "number", Format: "double"}
+			case "bool":
+				schemas[node.name] = &OpenAPISchema{Type: "boolean"}
+			case "uuid":
+				schemas[node.name] = &OpenAPISchema{Type: "string", Format: "uuid"}
+			case "string":
+				schemas[node.name] = &OpenAPISchema{Type: "string"}
+			default:
+				schemas[node.name] = &OpenAPISchema{Type: "string", Pattern: "^(?:" + typeName + ")$"}
+			}
+		case segmentTemplate:
+			names = append(names, node.templateNames()...)
+		}
+	}
+	return "/" + strings.Join(segments, "/"), schemas, names
+}
+
+//openAPIOperationID returns operation ID composed of HTTP method and static path segments, i.e. getV1UsersByID
+func openAPIOperationID(method, path string) string {
+	var result = strings.ToLower(method)
+	for _, segment := range splitRoutePath(path) {
+		prefix := ""
+		if strings.HasPrefix(segment, "{") {
+			prefix = "By"
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			result += prefix + strings.ToUpper(word[:1]) + word[1:]
+			prefix = ""
+		}
+	}
+	return result
+}
+
+func contentTypes(defaultContentType string, factories ...map[string]bool) []string {
+	var unique = map[string]bool{defaultContentType: true}
+	for _, factory := range factories {
+		for contentType := range factory {
+			unique[contentType] = true
+		}
+	}
+	var result = make([]string, 0, len(unique))
+	for contentType := range unique {
+		result = append(result, contentType)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (sr *ServiceRouting) requestContentTypes() []string {
+	var keys = make(map[string]bool)
+	for contentType := range sr.ContentTypeDecoders {
+		keys[contentType] = true
+	}
+	return contentTypes(jsonContentType, keys)
+}
+
+func (sr *ServiceRouting) responseContentTypes() []string {
+	var keys = make(map[string]bool)
+	for contentType := range sr.ContentTypeEncoders {
+		keys[contentType] = true
+	}
+	return contentTypes(jsonContentType, keys)
+}
+
+func newOpenAPIContent(contentTypes []string, schema *OpenAPISchema) map[string]*OpenAPIMediaType {
+	var result = make(map[string]*OpenAPIMediaType)
+	for _, contentType := range contentTypes {
+		result[contentType] = &OpenAPIMediaType{Schema: schema}
+	}
+	return result
+}
+
+func isOpenAPIBodyType(sourceType reflect.Type) bool {
+	for sourceType.Kind() == reflect.Ptr {
+		sourceType = sourceType.Elem()
+	}
+	switch sourceType.Kind() {
+	case reflect.Struct:
+		return sourceType != timeType
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+//operation returns OpenAPI operation for the routing
+func (s *openAPISchemas) operation(routing *ServiceRouting, path string, pathSchemas map[string]*OpenAPISchema, pathNames []string) *OpenAPIOperation {
+	var result = &OpenAPIOperation{
+		OperationID: openAPIOperationID(routing.HTTPMethod, path),
+		Responses:   make(map[string]*OpenAPIResponse),
+	}
+	var signature []reflect.Type
+	var handlerType reflect.Type
+	if routing.Handler != nil && reflect.TypeOf(routing.Handler).Kind() == reflect.Func {
+		signature = GetFuncSignature(routing.Handler)
+		handlerType = reflect.TypeOf(routing.Handler)
+	}
+	var parameterTypes = make(map[string]reflect.Type)
+	for i, name := range routing.Parameters {
+		if i < len(signature) {
+			parameterTypes[name] = signature[i]
+		}
+	}
+	for _, name := range pathNames {
+		schema, ok := pathSchemas[name]
+		if !ok {
+			schema = &OpenAPISchema{Type: "string"}
+			if parameterType, has := parameterTypes[name]; has {
+				schema = s.schema(parameterType)
+			}
+		}
+		result.Parameters = append(result.Parameters, &OpenAPIParameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	var hasParameters = len(pathNames) > 0
+	for _, name := range routing.Parameters {
+		if strings.HasPrefix(name, "@") || HasSliceAnyElements(pathNames, name) {
+			continue
+		}
+		hasParameters = true
+		parameterType, ok := parameterTypes[name]
+		if !ok {
+			result.Parameters = append(result.Parameters, &OpenAPIParameter{Name: name, In: "query", Schema: &OpenAPISchema{Type: "string"}})
+			continue
+		}
+		if result.RequestBody == nil && isOpenAPIBodyType(parameterType) {
+			result.RequestBody = &OpenAPIRequestBody{Required: true, Content: newOpenAPIContent(routing.requestContentTypes(), s.schema(parameterType))}
+			continue
+		}
+		result.Parameters = append(result.Parameters, &OpenAPIParameter{Name: name, In: "query", Schema: s.schema(parameterType)})
+	}
+
+	var success = &OpenAPIResponse{Description: http.StatusText(http.StatusOK)}
+	result.Responses["200"] = success
+	if handlerType == nil {
+		return result
+	}
+	outputs := handlerType.NumOut()
+	returnsError := outputs > 0 && handlerType.Out(outputs-1).Implements(errorType)
+	if returnsError {
+		outputs--
+	}
+	if outputs > 0 {
+		success.Content = newOpenAPIContent(routing.responseContentTypes(), s.schema(handlerType.Out(0)))
+	}
+	errorContent := newOpenAPIContent([]string{jsonContentType}, s.schema(reflect.TypeOf(ErrorResponse{})))
+	if hasParameters {
+		result.Responses["400"] = &OpenAPIResponse{Description: http.StatusText(http.StatusBadRequest), Content: errorContent}
+	}
+	if returnsError {
+		result.Responses["default"] = &OpenAPIResponse{Description: "Error", Content: errorContent}
+	}
+	return result
+}
+
+//OpenAPI returns OpenAPI 3 document describing router service routings
+func (r *ServiceRouter) OpenAPI(options *OpenAPIOptions) *OpenAPIDocument {
+	if options == nil {
+		options = &OpenAPIOptions{}
+	}
+	var result = &OpenAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info:    &OpenAPIInfo{Title: options.Title, Description: options.Description, Version: options.Version},
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+	}
+	if result.Info.Title == "" {
+		result.Info.Title = "API"
+	}
+	if result.Info.Version == "" {
+		result.Info.Version = "1.0.0"
+	}
+	for _, URL := range options.Servers {
+		result.Servers = append(result.Servers, &OpenAPIServer{URL: URL})
+	}
+	schemas := &openAPISchemas{schemas: make(map[string]*OpenAPISchema), types: make(map[reflect.Type]string)}
+	for _, routing := range r.serviceRouting {
+		if routing.openAPI {
+			continue
+		}
+		path, pathSchemas, pathNames := openAPIPath(routing.URI)
+		method := strings.ToLower(routing.HTTPMethod)
+		if _, ok := result.Paths[path]; !ok {
+			result.Paths[path] = make(map[string]*OpenAPIOperation)
+		}
+		if _, ok := result.Paths[path][method]; ok { //the first routing describes the operation
+			continue
+		}
+		result.Paths[path][method] = schemas.operation(routing, path, pathSchemas, pathNames)
+	}
+	if len(schemas.schemas) > 0 {
+		result.Components = &OpenAPIComponents{Schemas: schemas.schemas}
+	}
+	return result
+}
+
+//ServeOpenAPI adds GET routing serving router OpenAPI document at URI, the document is YAML encoded
+//if URI ends with .yaml or .yml or YAML is accepted by the client, otherwise JSON encoded.
+//ServeOpenAPI modifies router routings, thus it has to be called before the router starts serving requests, it is not safe to call it concurrently with Route.
+func (r *ServiceRouter) ServeOpenAPI(URI string, options *OpenAPIOptions) error {
+	routing := &ServiceRouting{
+		HTTPMethod: MethodGet,
+		URI:        URI,
+		Handler:    func() *OpenAPIDocument { return r.OpenAPI(options) },
+		openAPI:    true,
+	}
+	routing.HandlerInvoker = func(serviceRouting *ServiceRouting, request *http.Request, response http.ResponseWriter, parameters map[string]interface{}) error {
+		document := r.OpenAPI(options)
+		var content []byte
+		var err error
+		contentType := jsonContentType
+		if strings.HasSuffix(URI, ".yaml") || strings.HasSuffix(URI, ".yml") || strings.Contains(request.Header.Get("Accept"), yamlContentTypeSuffix) {
+			contentType = yamlContentType
+			content, err = document.YAML()
+		} else {
+			content, err = document.JSON()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to encode OpenAPI document: %v", err)
+		}
+		response.Header().Set(contentTypeHeader, contentType)
+		_, err = response.Write(content)
+		return err
+	}
+	if err := r.tree.add(routing); err != nil {
+		return err
+	}
+	r.serviceRouting = append(r.serviceRouting, routing)
+	return nil
+}
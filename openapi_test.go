@@ -0,0 +1,121 @@
+package toolbox_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+	"gopkg.in/yaml.v2"
+)
+
+type OpenAPIAudit struct {
+	Created time.Time `json:"created"`
+}
+
+type openAPIOwner struct {
+	OwnerID string `json:"ownerId" required:"true"`
+	Name    string `json:"name"`
+}
+
+type openAPIUser struct {
+	OpenAPIAudit
+	*openAPIOwner
+	ID      int               `json:"id"`
+	Name    string            `json:"name" required:"true" description:"user name" max:"32"`
+	Role    string            `json:"role,omitempty" oneof:"admin,user"`
+	Friends []*openAPIUser    `json:"friends,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func TestServiceRouter_OpenAPI(t *testing.T) {
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/users/{id:int}",
+			Handler: func(id int, verbose bool) (*openAPIUser, error) {
+				return &openAPIUser{ID: id}, nil
+			},
+			Parameters: []string{"id", "verbose"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "POST",
+			URI:        "/v1/users",
+			Handler: func(user *openAPIUser, requestID string) *openAPIUser {
+				return user
+			},
+			Parameters:          []string{"user", "@requestID"},
+			ContentTypeDecoders: map[string]toolbox.DecoderFactory{"text/csv": toolbox.CSVDefaultDecoderFactory},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/files/*path",
+			Handler: func(path string) []byte {
+				return nil
+			},
+			Parameters: []string{"path"},
+		},
+	)
+	assert.Nil(t, router.ServeOpenAPI("/openapi.json", &toolbox.OpenAPIOptions{Title: "Users", Version: "2.0.0"}))
+	assert.Nil(t, router.ServeOpenAPI("/openapi.yaml", &toolbox.OpenAPIOptions{Title: "Users", Version: "2.0.0"}))
+
+	document := router.OpenAPI(&toolbox.OpenAPIOptions{Title: "Users", Version: "2.0.0", Servers: []string{"http://localhost:8080"}})
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+	assert.Equal(t, 3, len(document.Paths), "OpenAPI document routes should be excluded")
+
+	getUser := document.Paths["/v1/users/{id}"]["get"]
+	if assert.NotNil(t, getUser) {
+		assert.Equal(t, "getV1UsersById", getUser.OperationID)
+		assert.EqualValues(t, []*toolbox.OpenAPIParameter{
+			{Name: "id", In: "path", Required: true, Schema: &toolbox.OpenAPISchema{Type: "integer", Format: "int64"}},
+			{Name: "verbose", In: "query", Schema: &toolbox.OpenAPISchema{Type: "boolean"}},
+		}, getUser.Parameters)
+		assert.Equal(t, "#/components/schemas/openAPIUser", getUser.Responses["200"].Content["application/json"].Schema.Ref)
+		assert.NotNil(t, getUser.Responses["400"])
+		assert.Equal(t, "#/components/schemas/ErrorResponse", getUser.Responses["default"].Content["application/json"].Schema.Ref)
+	}
+
+	createUser := document.Paths["/v1/users"]["post"]
+	if assert.NotNil(t, createUser) {
+		assert.Equal(t, 0, len(createUser.Parameters))
+		assert.Equal(t, 2, len(createUser.RequestBody.Content))
+		assert.Equal(t, "#/components/schemas/openAPIUser", createUser.RequestBody.Content["text/csv"].Schema.Ref)
+		assert.Nil(t, createUser.Responses["default"])
+	}
+	getFile := document.Paths["/v1/files/{path}"]["get"]
+	if assert.NotNil(t, getFile) {
+		assert.Equal(t, "path", getFile.Parameters[0].In)
+		assert.Equal(t, "byte", getFile.Responses["200"].Content["application/json"].Schema.Format)
+	}
+
+	user := document.Components.Schemas["openAPIUser"]
+	if assert.NotNil(t, user) {
+		assert.Equal(t, []string{"name", "ownerId"}, user.Required)
+		assert.Equal(t, "string", user.Properties["ownerId"].Type)
+		assert.Equal(t, &toolbox.OpenAPISchema{Type: "string", Description: "user name", MaxLength: intPointer(32)}, user.Properties["name"])
+		assert.Equal(t, []string{"admin", "user"}, user.Properties["role"].Enum)
+		assert.Equal(t, "#/components/schemas/openAPIUser", user.Properties["friends"].Items.Ref)
+		assert.Equal(t, "string", user.Properties["labels"].AdditionalProperties.Type)
+		assert.Equal(t, "date-time", user.Properties["created"].Format)
+	}
+
+	response := httptest.NewRecorder()
+	assert.Nil(t, router.Route(response, httptest.NewRequest("GET", "/openapi.json", nil)))
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	var decoded map[string]interface{}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &decoded))
+	assert.Equal(t, "Users", decoded["info"].(map[string]interface{})["title"])
+
+	response = httptest.NewRecorder()
+	assert.Nil(t, router.Route(response, httptest.NewRequest("GET", "/openapi.yaml", nil)))
+	assert.Equal(t, "application/yaml", response.Header().Get("Content-Type"))
+	decoded = nil
+	assert.Nil(t, yaml.Unmarshal(response.Body.Bytes(), &decoded))
+	assert.Equal(t, "3.0.3", decoded["openapi"])
+}
+
+func intPointer(value int) *int {
+	return &value
+}
@@ -191,7 +191,14 @@ func NewComparablePredicate(operator string, leftOperand interface{}) Predicate
 type nilPredicate struct{}
 
 func (p *nilPredicate) Apply(value interface{}) bool {
-	return value == nil || reflect.ValueOf(value).IsNil()
+	if value == nil {
+		return true
+	}
+	switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return reflectValue.IsNil()
+	}
+	return false
 }
 
 //NewNilPredicate returns a new nil predicate
@@ -0,0 +1,289 @@
+package toolbox
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//route segment kinds in matching priority order
+const (
+	segmentStatic = iota
+	segmentTyped
+	segmentParam
+	segmentTemplate
+	segmentCatchAll
+)
+
+var uuidExpression = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+//routeParameter converts and validates a typed path parameter value
+type routeParameter func(value string) (interface{}, bool)
+
+var routeParameterTypes = map[string]routeParameter{
+	"int": func(value string) (interface{}, bool) {
+		result, err := strconv.Atoi(value)
+		return result, err == nil
+	},
+	"float": func(value string) (interface{}, bool) {
+		result, err := strconv.ParseFloat(value, 64)
+		return result, err == nil
+	},
+	"bool": func(value string) (interface{}, bool) {
+		result, err := strconv.ParseBool(value)
+		return result, err == nil
+	},
+	"uuid": func(value string) (interface{}, bool) {
+		return value, uuidExpression.MatchString(value)
+	},
+	"string": func(value string) (interface{}, bool) {
+		return value, value != ""
+	},
+}
+
+//routeNode represents a path segment of the route tree
+type routeNode struct {
+	kind      int
+	segment   string //static segment or parameter template
+	name      string //parameter name
+	parameter routeParameter
+	static    map[string]*routeNode
+	dynamic   []*routeNode //parameter, template and catch-all children sorted by kind
+	routes    map[string][]*ServiceRouting
+}
+
+//routeMatch represents a node matching request path with extracted parameters
+type routeMatch struct {
+	node       *routeNode
+	parameters map[string]interface{}
+}
+
+//parseRouteParameter returns typed parameter name and converter, {id:int} or {code:[A-Z]{3}} regular expression
+func parseRouteParameter(segment string) (string, routeParameter, error) {
+	body := segment[1 : len(segment)-1]
+	index := strings.Index(body, ":")
+	if index == -1 {
+		return body, nil, nil
+	}
+	name, typeName := body[:index], body[index+1:]
+	if parameter, ok := routeParameterTypes[typeName]; ok {
+		return name, parameter, nil
+	}
+	expression, err := regexp.Compile("^(?:" + typeName + ")$")
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid parameter %v type: %v", segment, err)
+	}
+	return name, func(value string) (interface{}, bool) {
+		return value, expression.MatchString(value)
+	}, nil
+}
+
+//isRouteParameter returns true if the whole segment is a single {name} or {name:type} parameter
+func isRouteParameter(segment string) bool {
+	if !strings.HasPrefix(segment, "{") {
+		return false
+	}
+	depth := 0
+	for i, char := range segment {
+		switch char {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i == len(segment)-1
+			}
+		}
+	}
+	return false
+}
+
+func newRouteNode(segment string, isLast bool) (*routeNode, error) {
+	switch {
+	case strings.HasPrefix(segment, "*"):
+		if !isLast {
+			return nil, fmt.Errorf("catch-all %v has to be the last segment", segment)
+		}
+		return &routeNode{kind: segmentCatchAll, segment: segment, name: segment[1:]}, nil
+	case isRouteParameter(segment):
+		name, parameter, err := parseRouteParameter(segment)
+		if err != nil {
+			return nil, err
+		}
+		if parameter != nil {
+			return &routeNode{kind: segmentTyped, segment: segment, name: name, parameter: parameter}, nil
+		}
+		return &routeNode{kind: segmentParam, segment: segment, name: name}, nil
+	case strings.Contains(segment, "{"):
+		return &routeNode{kind: segmentTemplate, segment: segment}, nil
+	}
+	return &routeNode{kind: segmentStatic, segment: segment}, nil
+}
+
+//child returns existing or a new child node for the segment
+func (n *routeNode) child(segment string, isLast bool) (*routeNode, error) {
+	if result, ok := n.static[segment]; ok {
+		return result, nil
+	}
+	for _, candidate := range n.dynamic {
+		if candidate.segment == segment {
+			return candidate, nil
+		}
+	}
+	result, err := newRouteNode(segment, isLast)
+	if err != nil {
+		return nil, err
+	}
+	if result.kind == segmentStatic {
+		if n.static == nil {
+			n.static = make(map[string]*routeNode)
+		}
+		n.static[segment] = result
+		return result, nil
+	}
+	n.dynamic = append(n.dynamic, result)
+	sort.SliceStable(n.dynamic, func(i, j int) bool {
+		return n.dynamic[i].kind < n.dynamic[j].kind
+	})
+	return result, nil
+}
+
+//add adds routing to the tree
+func (n *routeNode) add(routing *ServiceRouting) error {
+	node := n
+	segments := splitRoutePath(routing.URI)
+	for i, segment := range segments {
+		var err error
+		if node, err = node.child(segment, i+1 == len(segments)); err != nil {
+			return fmt.Errorf("invalid route %v %v: %v", routing.HTTPMethod, routing.URI, err)
+		}
+	}
+	if node.routes == nil {
+		node.routes = make(map[string][]*ServiceRouting)
+	}
+	method := strings.ToUpper(routing.HTTPMethod)
+	node.routes[method] = append(node.routes[method], routing)
+	return nil
+}
+
+//match returns nodes with routes matching path in priority order
+func (n *routeNode) match(path string) []*routeMatch {
+	var result = make([]*routeMatch, 0)
+	n.collect(splitRequestPath(path), map[string]interface{}{}, &result)
+	return result
+}
+
+func (n *routeNode) collect(segments []string, parameters map[string]interface{}, result *[]*routeMatch) {
+	if len(segments) == 0 {
+		if len(n.routes) > 0 {
+			*result = append(*result, &routeMatch{node: n, parameters: copyRouteParameters(parameters)})
+		}
+		for _, candidate := range n.dynamic { //catch-all matches empty remainder
+			if candidate.kind == segmentCatchAll && len(candidate.routes) > 0 {
+				matched := copyRouteParameters(parameters)
+				matched[candidate.name] = ""
+				*result = append(*result, &routeMatch{node: candidate, parameters: matched})
+			}
+		}
+		return
+	}
+	segment := segments[0]
+	if candidate, ok := n.static[segment]; ok {
+		candidate.collect(segments[1:], parameters, result)
+	}
+	value, err := url.PathUnescape(segment)
+	if err != nil {
+		value = segment
+	}
+	for _, candidate := range n.dynamic {
+		switch candidate.kind {
+		case segmentTyped:
+			typed, ok := candidate.parameter(value)
+			if !ok {
+				continue
+			}
+			parameters[candidate.name] = typed
+		case segmentParam:
+			if value == "" { //empty trailing parameter can be supplied by request body
+				break
+			}
+			parameters[candidate.name] = value
+		case segmentTemplate:
+			extracted, ok := ExtractURIParameters(candidate.segment, value)
+			if !ok {
+				continue
+			}
+			for k, v := range extracted {
+				parameters[k] = v
+			}
+		case segmentCatchAll:
+			if len(candidate.routes) == 0 {
+				continue
+			}
+			remainder := strings.Join(segments, "/")
+			if unescaped, err := url.PathUnescape(remainder); err == nil {
+				remainder = unescaped
+			}
+			matched := copyRouteParameters(parameters)
+			matched[candidate.name] = remainder
+			*result = append(*result, &routeMatch{node: candidate, parameters: matched})
+			continue
+		}
+		candidate.collect(segments[1:], parameters, result)
+		if candidate.kind == segmentTemplate {
+			for _, k := range candidate.templateNames() {
+				delete(parameters, k)
+			}
+			continue
+		}
+		delete(parameters, candidate.name)
+	}
+}
+
+//templateNames returns template segment parameter names in order
+func (n *routeNode) templateNames() []string {
+	var result = make([]string, 0)
+	for _, part := range strings.Split(n.segment, "{")[1:] {
+		if index := strings.Index(part, "}"); index != -1 {
+			result = append(result, part[:index])
+		}
+	}
+	return result
+}
+
+//methods returns HTTP methods of node routes
+func (n *routeNode) methods() []string {
+	var result = make([]string, 0, len(n.routes))
+	for method := range n.routes {
+		result = append(result, method)
+	}
+	return result
+}
+
+func copyRouteParameters(parameters map[string]interface{}) map[string]interface{} {
+	var result = make(map[string]interface{}, len(parameters))
+	for k, v := range parameters {
+		result[k] = v
+	}
+	return result
+}
+
+//splitRequestPath returns request path segments, query string is removed
+func splitRequestPath(path string) []string {
+	if index := strings.Index(path, "?"); index != -1 {
+		path = path[:index]
+	}
+	return splitRoutePath(path)
+}
+
+//splitRoutePath returns route template path segments
+func splitRoutePath(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,143 @@
+package toolbox_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+func TestServiceRouter_Tree(t *testing.T) {
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/users/{id:int}",
+			Handler: func(id int) string {
+				return "int:" + toolbox.AsString(id)
+			},
+			Parameters: []string{"id"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/users/{name}",
+			Handler: func(name string) string {
+				return "name:" + name
+			},
+			Parameters: []string{"name"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/users/me",
+			Handler: func() string {
+				return "me"
+			},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "DELETE",
+			URI:        "/v1/users/{id:int}",
+			Handler: func(id int) string {
+				return "deleted"
+			},
+			Parameters: []string{"id"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/codes/{code:[A-Z]{3}}",
+			Handler: func(code string) string {
+				return "code:" + code
+			},
+			Parameters: []string{"code"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/tags/{tag:ab?}",
+			Handler: func(tag string) string {
+				return "tag:" + tag
+			},
+			Parameters: []string{"tag"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/files/*path",
+			Handler: func(path string) string {
+				return "file:" + path
+			},
+			Parameters: []string{"path"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/files/{name}",
+			Handler: func(name string) string {
+				return "low"
+			},
+			Parameters: []string{"name"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/files/{name}",
+			Handler: func(name string) string {
+				return "high:" + name
+			},
+			Parameters: []string{"name"},
+			Priority:   1,
+		},
+	)
+
+	var useCases = []struct {
+		description string
+		method      string
+		uri         string
+		status      int
+		body        string
+		allow       string
+	}{
+		{description: "static segment first", method: "GET", uri: "/v1/users/me", status: 200, body: `"me"`},
+		{description: "typed parameter", method: "GET", uri: "/v1/users/12?verbose=true", status: 200, body: `"int:12"`},
+		{description: "plain parameter", method: "GET", uri: "/v1/users/bob", status: 200, body: `"name:bob"`},
+		{description: "regular expression parameter", method: "GET", uri: "/v1/codes/ABC", status: 200, body: `"code:ABC"`},
+		{description: "optional regular expression character", method: "GET", uri: "/v1/tags/a?x=1", status: 200, body: `"tag:a"`},
+		{description: "catch-all", method: "GET", uri: "/v1/files/a/b%20c.txt", status: 200, body: `"file:a/b c.txt"`},
+		{description: "route priority", method: "GET", uri: "/v1/files/a.txt", status: 200, body: `"high:a.txt"`},
+		{description: "method not allowed", method: "PUT", uri: "/v1/users/12", status: 405, allow: "DELETE, GET, HEAD, OPTIONS"},
+		{description: "automatic options", method: "OPTIONS", uri: "/v1/users/12", status: 204, allow: "DELETE, GET, HEAD, OPTIONS"},
+		{description: "automatic head", method: "HEAD", uri: "/v1/users/12", status: 200, body: ""},
+	}
+	for _, useCase := range useCases {
+		request := httptest.NewRequest(useCase.method, useCase.uri, nil)
+		response := httptest.NewRecorder()
+		err := router.Route(response, request)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.status, response.Code, useCase.description)
+		assert.Equal(t, useCase.allow, response.Header().Get("Allow"), useCase.description)
+		if useCase.status == http.StatusOK {
+			assert.Equal(t, useCase.body, trimNewLine(response.Body.String()), useCase.description)
+		}
+	}
+
+	{ //regular expression mismatch and unknown path report error
+		response := httptest.NewRecorder()
+		assert.NotNil(t, router.Route(response, httptest.NewRequest("GET", "/v1/codes/abcd", nil)))
+		assert.NotNil(t, router.Route(response, httptest.NewRequest("GET", "/v2/users", nil)))
+	}
+
+	for _, URI := range []string{"/v1/*path/x", "/v1/{id:[}"} {
+		_, err := toolbox.NewServiceRouterWithError(toolbox.ServiceRouting{HTTPMethod: "GET", URI: URI})
+		assert.NotNil(t, err, URI)
+		invalid := toolbox.NewServiceRouter(toolbox.ServiceRouting{HTTPMethod: "GET", URI: URI}, toolbox.ServiceRouting{HTTPMethod: "GET", URI: "/v1/ping", Handler: func() string { return "pong" }})
+		response := httptest.NewRecorder()
+		if assert.Nil(t, invalid.Route(response, httptest.NewRequest("GET", "/v1/ping", nil)), URI) {
+			assert.Equal(t, `"pong"`, trimNewLine(response.Body.String()), URI)
+		}
+	}
+}
+
+func trimNewLine(text string) string {
+	for len(text) > 0 && text[len(text)-1] == '\n' {
+		text = text[:len(text)-1]
+	}
+	return text
+}
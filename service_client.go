@@ -0,0 +1,122 @@
+package toolbox
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//ClientResponse represents a typed client response, JSON body is decoded into Value or ErrorResponse for error status code
+type ClientResponse struct {
+	Value      interface{} //response value pointer, nil if response has no body
+	StatusCode int
+	Error      *ErrorResponse
+}
+
+//SetStatusCode sets response status code
+func (r *ClientResponse) SetStatusCode(code int) {
+	r.StatusCode = code
+}
+
+func (r *ClientResponse) setErrorResponse(errorResponse *ErrorResponse) {
+	r.Error = errorResponse
+}
+
+//UnmarshalJSON decodes response body into Value or ErrorResponse if status code is 400 or higher
+func (r *ClientResponse) UnmarshalJSON(data []byte) error {
+	if r.StatusCode >= http.StatusBadRequest {
+		r.Error = &ErrorResponse{}
+		if err := json.Unmarshal(data, r.Error); err != nil {
+			r.Error = &ErrorResponse{Status: r.StatusCode, Message: string(data)}
+		}
+		return nil
+	}
+	if r.Value == nil {
+		return nil
+	}
+	return json.Unmarshal(data, r.Value)
+}
+
+//Check returns *HTTPError for error status code, request error otherwise, empty body error is ignored if response has no value
+func (r *ClientResponse) Check(err error) error {
+	if r.StatusCode >= http.StatusBadRequest {
+		result := &HTTPError{StatusCode: r.StatusCode, Message: http.StatusText(r.StatusCode), Err: err}
+		if r.Error == nil && err != nil { //non JSON server error body is returned as request error
+			result.Message = err.Error()
+		}
+		if r.Error != nil {
+			result.Code, result.Message = r.Error.Code, r.Error.Message
+		}
+		return result
+	}
+	if r.Value == nil && r.StatusCode > 0 && errors.Is(err, ErrEmptyResponseBody) {
+		return nil
+	}
+	return err
+}
+
+//formatClientParameter returns parameter text value, slice items are comma separated
+func formatClientParameter(value interface{}) string {
+	if IsSlice(value) {
+		var items = make([]string, 0)
+		for _, item := range AsSlice(value) {
+			items = append(items, AsString(item))
+		}
+		return strings.Join(items, ",")
+	}
+	return AsString(value)
+}
+
+//ClientURL returns service routing URL for base URL and routing URI template, path parameters are substituted and escaped,
+//non nil query parameters are encoded, slice values are comma separated.
+func ClientURL(baseURL, URI string, pathParameters, queryParameters map[string]interface{}) string {
+	segments := splitRoutePath(URI)
+	for i, segment := range segments {
+		node, err := newRouteNode(segment, i+1 == len(segments))
+		if err != nil {
+			continue
+		}
+		switch node.kind {
+		case segmentParam, segmentTyped:
+			segments[i] = url.PathEscape(formatClientParameter(pathParameters[node.name]))
+		case segmentCatchAll:
+			var parts = strings.Split(formatClientParameter(pathParameters[node.name]), "/")
+			for j := range parts {
+				parts[j] = url.PathEscape(parts[j])
+			}
+			segments[i] = strings.Join(parts, "/")
+		case segmentTemplate:
+			for _, name := range node.templateNames() {
+				segments[i] = strings.Replace(segments[i], "{"+name+"}", url.PathEscape(formatClientParameter(pathParameters[name])), 1)
+			}
+		}
+	}
+	result := strings.TrimRight(baseURL, "/") + "/" + strings.Join(segments, "/")
+	var query = url.Values{}
+	var names = make([]string, 0, len(queryParameters))
+	for name := range queryParameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := queryParameters[name]
+		if value == nil {
+			continue
+		}
+		switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			if reflectValue.IsNil() {
+				continue
+			}
+		}
+		query.Set(name, formatClientParameter(DereferenceValue(value)))
+	}
+	if len(query) > 0 {
+		result += "?" + query.Encode()
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package toolbox_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+func TestClientURL(t *testing.T) {
+	var ids []int
+	var useCases = []struct {
+		description string
+		URI         string
+		path        map[string]interface{}
+		query       map[string]interface{}
+		expect      string
+	}{
+		{
+			description: "path and query parameters",
+			URI:         "/v1/users/{id:int}",
+			path:        map[string]interface{}{"id": 12},
+			query:       map[string]interface{}{"verbose": true, "fields": []string{"id", "name"}},
+			expect:      "http://localhost:8080/v1/users/12?fields=id%2Cname&verbose=true",
+		},
+		{
+			description: "escaped path parameter",
+			URI:         "/v1/users/{name}",
+			path:        map[string]interface{}{"name": "a b/c"},
+			expect:      "http://localhost:8080/v1/users/a%20b%2Fc",
+		},
+		{
+			description: "catch all parameter",
+			URI:         "/v1/files/*path",
+			path:        map[string]interface{}{"path": "docs/a b.txt"},
+			expect:      "http://localhost:8080/v1/files/docs/a%20b.txt",
+		},
+		{
+			description: "template segment",
+			URI:         "/v1/reports/{year}-{month}.csv",
+			path:        map[string]interface{}{"year": 2019, "month": "03"},
+			expect:      "http://localhost:8080/v1/reports/2019-03.csv",
+		},
+		{
+			description: "nil query parameters",
+			URI:         "/v1/users",
+			query:       map[string]interface{}{"ids": ids, "role": nil},
+			expect:      "http://localhost:8080/v1/users",
+		},
+	}
+	for _, useCase := range useCases {
+		actual := toolbox.ClientURL("http://localhost:8080/", useCase.URI, useCase.path, useCase.query)
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestClientResponse_Check(t *testing.T) {
+	{
+		var value map[string]interface{}
+		response := &toolbox.ClientResponse{Value: &value}
+		response.SetStatusCode(http.StatusOK)
+		assert.Nil(t, response.UnmarshalJSON([]byte(`{"k":1}`)))
+		assert.Nil(t, response.Check(nil))
+		assert.EqualValues(t, 1, value["k"])
+	}
+	{
+		response := &toolbox.ClientResponse{}
+		response.SetStatusCode(http.StatusBadRequest)
+		assert.Nil(t, response.UnmarshalJSON([]byte(`{"status":400,"code":"validation_failed","message":"validation failed"}`)))
+		err := response.Check(nil)
+		if httpError, ok := err.(*toolbox.HTTPError); assert.True(t, ok) {
+			assert.Equal(t, http.StatusBadRequest, httpError.StatusCode)
+			assert.Equal(t, "validation_failed", httpError.Code)
+			assert.Equal(t, "validation failed", httpError.Message)
+		}
+	}
+	{
+		response := &toolbox.ClientResponse{}
+		response.SetStatusCode(http.StatusBadGateway)
+		err := response.Check(errors.New("upstream failed"))
+		if httpError, ok := err.(*toolbox.HTTPError); assert.True(t, ok) {
+			assert.Equal(t, http.StatusBadGateway, httpError.StatusCode)
+			assert.Equal(t, "upstream failed", httpError.Message)
+		}
+	}
+	{
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		response := &toolbox.ClientResponse{}
+		err := toolbox.RouteToService("delete", server.URL, nil, response)
+		assert.True(t, errors.Is(err, toolbox.ErrEmptyResponseBody))
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		assert.Nil(t, response.Check(err))
+		assert.NotNil(t, response.Check(errors.New("response body was empty")), "only ErrEmptyResponseBody is ignored")
+	}
+}
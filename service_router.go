@@ -8,6 +8,8 @@ import (
 	"net"
 	"net/http"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -57,6 +59,18 @@ var YamlDefaultEncoderFactory = NewYamlEncoderFactory()
 //YamlDefaultDecoderFactory - NewYamlDecoderFactory
 var YamlDefaultDecoderFactory = NewFlexYamlDecoderFactory()
 
+//CSVDefaultEncoderFactory - NewCSVEncoderFactory used for text/csv content type
+var CSVDefaultEncoderFactory = NewCSVEncoderFactory(nil)
+
+//CSVDefaultDecoderFactory - NewCSVDecoderFactory used for text/csv content type
+var CSVDefaultDecoderFactory = NewCSVDecoderFactory(nil)
+
+//TSVDefaultEncoderFactory - NewTSVEncoderFactory used for text/tab-separated-values content type
+var TSVDefaultEncoderFactory = NewTSVEncoderFactory()
+
+//TSVDefaultDecoderFactory - NewTSVDecoderFactory used for text/tab-separated-values content type
+var TSVDefaultDecoderFactory = NewTSVDecoderFactory()
+
 //ServiceRouting represents a simple web services routing rule, which is matched with http request
 type ServiceRouting struct {
 	URI                 string      //matching uri
@@ -66,6 +80,10 @@ type ServiceRouting struct {
 	ContentTypeEncoders map[string]EncoderFactory //content type encoder factory
 	ContentTypeDecoders map[string]DecoderFactory //content type decoder factory
 	HandlerInvoker      HandlerInvoker            //optional function that will be used instead of reflection to invoke a handler.
+	Priority            int                       //optional priority, routes with higher priority are tried first when more than one route matches a request
+	Middlewares         []Middleware              //optional routing middlewares, applied after router global middlewares
+	Validate            bool                      //when set, struct handler parameters are validated with ValidateStruct before the handler is called
+	openAPI             bool                      //true for routing serving OpenAPI document
 }
 
 func (sr ServiceRouting) getDecoderFactory(contentType string) DecoderFactory {
@@ -77,11 +95,17 @@ func (sr ServiceRouting) getDecoderFactory(contentType string) DecoderFactory {
 	if strings.HasSuffix(contentType, yamlContentTypeSuffix) {
 		return YamlDefaultDecoderFactory
 	}
+	if strings.HasPrefix(contentType, csvContentType) {
+		return CSVDefaultDecoderFactory
+	}
+	if strings.HasPrefix(contentType, tsvContentType) {
+		return TSVDefaultDecoderFactory
+	}
 	return DefaultDecoderFactory
 }
 
 func (sr ServiceRouting) getEncoderFactory(contentType string) EncoderFactory {
-	if sr.ContentTypeDecoders != nil {
+	if sr.ContentTypeEncoders != nil {
 		if factory, found := sr.ContentTypeEncoders[contentType]; found {
 			return factory
 		}
@@ -89,6 +113,12 @@ func (sr ServiceRouting) getEncoderFactory(contentType string) EncoderFactory {
 	if strings.HasSuffix(contentType, yamlContentTypeSuffix) {
 		return YamlDefaultEncoderFactory
 	}
+	if strings.HasPrefix(contentType, csvContentType) {
+		return CSVDefaultEncoderFactory
+	}
+	if strings.HasPrefix(contentType, tsvContentType) {
+		return TSVDefaultEncoderFactory
+	}
 	return DefaultEncoderFactory
 }
 
@@ -120,25 +150,28 @@ func (sr ServiceRouting) extractParameterFromBody(parameterName string, targetTy
 	return targetValuePointer.Interface(), nil
 }
 
-func (sr ServiceRouting) extractParameters(request *http.Request, response http.ResponseWriter) (map[string]interface{}, error) {
+func (sr ServiceRouting) extractParameters(request *http.Request, response http.ResponseWriter, uriParameters map[string]interface{}) (map[string]interface{}, error) {
 	var result = make(map[string]interface{})
 	_ = request.ParseForm()
 	functionSignature := GetFuncSignature(sr.Handler)
-	uriParameters, _ := ExtractURIParameters(sr.URI, request.RequestURI)
-	for _, name := range sr.Parameters {
-		value, found := uriParameters[name]
+	for i, name := range sr.Parameters {
+		uriValue, found := uriParameters[name]
 		if found {
-			if strings.Contains(value, ",") {
+			if value, ok := uriValue.(string); ok && strings.Contains(value, ",") {
 				result[name] = strings.Split(value, ",")
 			} else {
-				result[name] = value
+				result[name] = uriValue
 			}
 			continue
 		}
 
-		value = request.Form.Get(name)
+		value := request.Form.Get(name)
 		if len(value) > 0 {
-			result[name] = value
+			if i < len(functionSignature) && functionSignature[i].Kind() == reflect.Slice && functionSignature[i].Elem().Kind() != reflect.Uint8 {
+				result[name] = strings.Split(value, ",")
+			} else {
+				result[name] = value
+			}
 		} else {
 			continue
 		}
@@ -152,6 +185,9 @@ func (sr ServiceRouting) extractParameters(request *http.Request, response http.
 
 	if request.ContentLength > 0 {
 		for i, parameter := range sr.Parameters {
+			if strings.HasPrefix(parameter, "@") { //parameters supplied by router or middleware
+				continue
+			}
 			if _, found := result[parameter]; !found {
 				value, err := sr.extractParameterFromBody(parameter, functionSignature[i], request)
 				if err != nil {
@@ -168,19 +204,58 @@ func (sr ServiceRouting) extractParameters(request *http.Request, response http.
 //ServiceRouter represents routing rule
 type ServiceRouter struct {
 	serviceRouting []*ServiceRouting
+	tree           *routeNode
+	middlewares    []Middleware
 }
 
-func (r *ServiceRouter) match(request *http.Request) []*ServiceRouting {
-	var result = make([]*ServiceRouting, 0)
-	for _, candidate := range r.serviceRouting {
-		if candidate.HTTPMethod == request.Method {
-			_, matched := ExtractURIParameters(candidate.URI, request.RequestURI)
-			if matched {
-				result = append(result, candidate)
-			}
+//routeCandidate represents a service routing matching a request with extracted uri parameters
+type routeCandidate struct {
+	*ServiceRouting
+	parameters map[string]interface{}
+}
+
+//match returns routes matching request path and method ordered by priority, and HTTP methods allowed for the path
+func (r *ServiceRouter) match(request *http.Request) ([]*routeCandidate, []string) {
+	path := request.RequestURI
+	if request.URL != nil {
+		path = request.URL.EscapedPath()
+	}
+	var result = make([]*routeCandidate, 0)
+	var allowed = make(map[string]bool)
+	matches := r.tree.match(path)
+	for _, matched := range matches {
+		for _, method := range matched.node.methods() {
+			allowed[method] = true
 		}
+		for _, routing := range matched.node.routes[request.Method] {
+			result = append(result, &routeCandidate{ServiceRouting: routing, parameters: matched.parameters})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Priority > result[j].Priority
+	})
+	if len(allowed) == 0 {
+		return result, nil
+	}
+	if allowed[MethodGet] {
+		allowed[MethodHead] = true
 	}
-	return result
+	allowed[MethodOptions] = true
+	var methods = make([]string, 0, len(allowed))
+	for method := range allowed {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return result, methods
+}
+
+//headResponseWriter discards response body for automatically handled HEAD requests
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w *headResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
 }
 
 func getContentTypeOrJSONContentType(contentType string) string {
@@ -190,51 +265,157 @@ func getContentTypeOrJSONContentType(contentType string) string {
 	return contentType
 }
 
+//getAcceptedDelimitedContentType returns text/csv or text/tab-separated-values if result is tabular and the client prefers it to JSON,
+//media ranges are weighted by q parameter, on equal weight the earlier listed type wins, wildcards count for JSON only
+func getAcceptedDelimitedContentType(accept string, result interface{}) string {
+	if accept == "" || !isTabularResult(result) {
+		return ""
+	}
+	var delimited, delimitedQuality, delimitedIndex = "", 0.0, 0
+	var jsonQuality, jsonIndex = 0.0, -1
+	for i, mediaRange := range strings.Split(accept, ",") {
+		mediaType, quality := parseMediaRange(mediaRange)
+		switch mediaType {
+		case csvContentType, tsvContentType:
+			if quality > delimitedQuality {
+				delimited, delimitedQuality, delimitedIndex = mediaType, quality, i
+			}
+		case jsonContentType:
+			if quality > jsonQuality || (quality == jsonQuality && jsonIndex == -1) {
+				jsonQuality, jsonIndex = quality, i
+			}
+		case "application/*", "*/*":
+			if quality > jsonQuality {
+				jsonQuality = quality
+			}
+		}
+	}
+	if delimitedQuality == 0 || delimitedQuality < jsonQuality {
+		return ""
+	}
+	if delimitedQuality == jsonQuality && jsonIndex != -1 && jsonIndex < delimitedIndex {
+		return ""
+	}
+	return delimited
+}
+
+//parseMediaRange returns lower case media type and its q parameter weight, 1 by default
+func parseMediaRange(mediaRange string) (string, float64) {
+	var parameters = strings.Split(mediaRange, ";")
+	var quality = 1.0
+	for _, parameter := range parameters[1:] {
+		if pair := strings.SplitN(strings.TrimSpace(parameter), "=", 2); len(pair) == 2 && strings.ToLower(pair[0]) == "q" {
+			if value, err := strconv.ParseFloat(pair[1], 64); err == nil {
+				quality = value
+			}
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(parameters[0])), quality
+}
+
+//isTabularResult returns true if result can be encoded as delimited records: an Iterator, struct, map or slice of structs or maps
+func isTabularResult(result interface{}) bool {
+	if _, ok := result.(Iterator); ok {
+		return true
+	}
+	value := reflect.ValueOf(result)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct, reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			item := value.Index(i)
+			for (item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface) && !item.IsNil() {
+				item = item.Elem()
+			}
+			if item.Kind() != reflect.Struct && item.Kind() != reflect.Map {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 //Route matches  service routing by http method , and number of parameters, then it call routing method, and sent back its response.
+//HEAD request without matching route is handled by GET route without response body, OPTIONS request without matching route
+//responds with Allow header, if path matches routes with other HTTP methods only 405 status with Allow header is sent.
+//Global middlewares wrap the whole routing attempt including parameter extraction and error response, routing middlewares wrap handler invocation,
+//automatic OPTIONS and 405 responses pass through global middlewares only.
 func (r *ServiceRouter) Route(response http.ResponseWriter, request *http.Request) error {
-	candidates := r.match(request)
+	candidates, allowed := r.match(request)
+	if len(candidates) == 0 && request.Method == MethodHead && len(allowed) > 0 {
+		getRequest := *request
+		getRequest.Method = MethodGet
+		candidates, _ = r.match(&getRequest)
+		response = &headResponseWriter{ResponseWriter: response}
+	}
+	writer := &routeResponseWriter{ResponseWriter: response}
 	if len(candidates) == 0 {
+		if len(allowed) > 0 {
+			context := &RouteContext{Request: request, Response: writer, Allowed: allowed, writer: writer}
+			return chainMiddlewares(writeAllowedMethods, r.middlewares)(context)
+		}
 		var uriTemplates = make([]string, 0)
 		for _, routing := range r.serviceRouting {
 			uriTemplates = append(uriTemplates, routing.URI)
 		}
 		return fmt.Errorf("failed to route request - unable to match %v with one of %v", request.RequestURI, strings.Join(uriTemplates, ","))
 	}
-	var finalError error
-
-	for _, serviceRouting := range candidates {
+	first := candidates[0]
+	context := &RouteContext{
+		Routing:    first.ServiceRouting,
+		Request:    request,
+		Response:   writer,
+		Parameters: make(map[string]interface{}),
+		Allowed:    allowed,
+		writer:     writer,
+		candidate:  first,
+	}
+	return chainMiddlewares(func(context *RouteContext) error {
+		return routeCandidates(context, candidates)
+	}, r.middlewares)(context)
+}
 
-		parameterValues, err := serviceRouting.extractParameters(request, response)
-		if err != nil {
-			finalError = fmt.Errorf("unable to extract parameters due to %v", err)
+//routeCandidates invokes matched routings wrapped with routing middlewares until one handles the request, routing parameters
+//are extracted after global middlewares unless a global middleware already called ExtractParameters, @ prefixed parameters set by global middlewares
+//are passed to all routings. If no routing succeeds, the last failed attempt is written as 400 response.
+func routeCandidates(context *RouteContext, candidates []*routeCandidate) error {
+	var finalError error
+	var lastAttempt *routeAttemptError
+	var globalParameters = context.Parameters
+	for i, candidate := range candidates {
+		serviceRouting := candidate.ServiceRouting
+		if i > 0 {
+			context.Routing = serviceRouting
+			context.Parameters = globalParameters
+			context.candidate, context.extracted, context.extractErr = candidate, false, nil
+		}
+		if extractErr := context.ExtractParameters(); extractErr != nil {
+			lastAttempt = &routeAttemptError{err: fmt.Errorf("unable to extract parameters due to %v", extractErr), statusCode: http.StatusBadRequest, routing: serviceRouting}
+			finalError = lastAttempt.err
 			continue
 		}
-
-		if serviceRouting.HandlerInvoker != nil {
-			err := serviceRouting.HandlerInvoker(serviceRouting, request, response, parameterValues)
-			if err != nil {
-				finalError = fmt.Errorf("unable to extract parameters due to %v", err)
-			}
+		context.Result = nil
+		context.proceed = false
+		err := chainMiddlewares(invokeServiceRouting, serviceRouting.Middlewares)(context)
+		if attemptErr, ok := err.(*routeAttemptError); ok {
+			attemptErr.routing = serviceRouting
+			lastAttempt, finalError = attemptErr, attemptErr.err
 			continue
 		}
-
-		functionParameters, err := BuildFunctionParameters(serviceRouting.Handler, serviceRouting.Parameters, parameterValues)
 		if err != nil {
-			finalError = fmt.Errorf("unable to build function parameters %T due to %v", serviceRouting.Handler, err)
-			continue
+			return err
 		}
-
-		result := CallFunction(serviceRouting.Handler, functionParameters...)
-		if len(result) > 0 {
-			err = WriteServiceRoutingResponse(response, request, serviceRouting, result[0])
-			if err != nil {
-				return fmt.Errorf("failed to write response response %v, due to %v", result[0], err)
-			}
+		if !context.proceed {
 			return nil
 		}
-		if response.Header().Get(contentTypeHeader) == "" {
-			response.Header().Set(contentTypeHeader, textPlainContentType)
-		}
+	}
+	if lastAttempt != nil && lastAttempt.statusCode > 0 && !context.Written() {
+		return WriteServiceRoutingError(context.Response, context.Request, lastAttempt.routing, &HTTPError{StatusCode: lastAttempt.statusCode, Code: "invalid_request", Message: finalError.Error(), Err: finalError})
 	}
 	if finalError != nil {
 		return fmt.Errorf("failed to route request - %v", finalError)
@@ -242,6 +423,82 @@ func (r *ServiceRouter) Route(response http.ResponseWriter, request *http.Reques
 	return nil
 }
 
+//invokeServiceRouting calls routing handler invoker or handler with context parameters, it writes handler result
+func invokeServiceRouting(context *RouteContext) error {
+	serviceRouting := context.Routing
+	if _, ok := context.Parameters["@httpRequest"]; ok {
+		context.Parameters["@httpRequest"] = context.Request
+	}
+	if _, ok := context.Parameters["@httpResponseWriter"]; ok {
+		context.Parameters["@httpResponseWriter"] = context.Response
+	}
+	if serviceRouting.HandlerInvoker != nil {
+		context.proceed = true
+		err := serviceRouting.HandlerInvoker(serviceRouting, context.Request, context.Response, context.Parameters)
+		if err != nil {
+			return &routeAttemptError{err: fmt.Errorf("unable to extract parameters due to %v", err)}
+		}
+		return nil
+	}
+
+	functionParameters, err := BuildFunctionParameters(serviceRouting.Handler, serviceRouting.Parameters, context.Parameters)
+	if err != nil {
+		return &routeAttemptError{err: fmt.Errorf("unable to build function parameters %T due to %v", serviceRouting.Handler, err), statusCode: http.StatusBadRequest}
+	}
+	for i, parameter := range functionParameters {
+		if !serviceRouting.Validate {
+			break
+		}
+		if i < len(serviceRouting.Parameters) && strings.HasPrefix(serviceRouting.Parameters[i], "@") {
+			continue
+		}
+		if err := ValidateStruct(parameter); err != nil {
+			context.Result = err
+			return WriteServiceRoutingError(context.Response, context.Request, serviceRouting, err)
+		}
+	}
+
+	result := CallFunction(serviceRouting.Handler, functionParameters...)
+	if handlerErr := getHandlerError(serviceRouting.Handler, result); handlerErr != nil {
+		context.Result = handlerErr
+		return WriteServiceRoutingError(context.Response, context.Request, serviceRouting, handlerErr)
+	}
+	if len(result) > 0 {
+		context.Result = result[0]
+		err = WriteServiceRoutingResponse(context.Response, context.Request, serviceRouting, result[0])
+		if err != nil {
+			return fmt.Errorf("failed to write response response %v, due to %v", result[0], err)
+		}
+		return nil
+	}
+	if context.Response.Header().Get(contentTypeHeader) == "" {
+		context.Response.Header().Set(contentTypeHeader, textPlainContentType)
+	}
+	context.proceed = true
+	return nil
+}
+
+//getHandlerError returns non nil error returned by a handler as the last result
+func getHandlerError(handler interface{}, result []interface{}) error {
+	handlerType := reflect.TypeOf(handler)
+	if len(result) == 0 || handlerType.NumOut() != len(result) || !handlerType.Out(len(result)-1).Implements(errorType) {
+		return nil
+	}
+	err, _ := result[len(result)-1].(error)
+	return err
+}
+
+//writeAllowedMethods writes Allow header with 204 status for OPTIONS request or 405 status otherwise
+func writeAllowedMethods(context *RouteContext) error {
+	context.Response.Header().Set("Allow", strings.Join(context.Allowed, ", "))
+	if context.Request.Method == MethodOptions {
+		context.Response.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	context.Response.WriteHeader(http.StatusMethodNotAllowed)
+	return nil
+}
+
 //WriteServiceRoutingResponse writes service router response
 func WriteServiceRoutingResponse(response http.ResponseWriter, request *http.Request, serviceRouting *ServiceRouting, result interface{}) error {
 	if result == nil {
@@ -260,6 +517,9 @@ func WriteServiceRoutingResponse(response http.ResponseWriter, request *http.Req
 	if ok {
 		responseContentType = contentTypeAccessor.GetContentType()
 	}
+	if responseContentType == "" {
+		responseContentType = getAcceptedDelimitedContentType(request.Header.Get("Accept"), result)
+	}
 	if responseContentType == "" {
 		requestContentType := request.Header.Get(contentTypeHeader)
 		responseContentType = getContentTypeOrJSONContentType(requestContentType)
@@ -291,13 +551,36 @@ func (r *ServiceRouter) WriteResponse(encoderFactory EncoderFactory, response in
 	return nil
 }
 
-//NewServiceRouter creates a new service router, is takes list of service routing as arguments
+//NewServiceRouter creates a new service router, is takes list of service routing as arguments.
+//Routing URI segments can be static, {name} parameter, {name:type} typed parameter (int, float, bool, uuid, string or regular expression),
+//or *name catch-all as the last segment; static segments take precedence over typed parameters, then parameters and catch-all.
+//Routing with invalid URI is never matched, use NewServiceRouterWithError to get the URI error.
 func NewServiceRouter(serviceRouting ...ServiceRouting) *ServiceRouter {
+	router, _ := newServiceRouter(serviceRouting)
+	return router
+}
+
+//NewServiceRouterWithError creates a new service router, it returns an error if any routing URI is invalid
+func NewServiceRouterWithError(serviceRouting ...ServiceRouting) (*ServiceRouter, error) {
+	router, err := newServiceRouter(serviceRouting)
+	if err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+//newServiceRouter creates a new service router with the first routing URI error, routing with invalid URI is never matched
+func newServiceRouter(serviceRouting []ServiceRouting) (*ServiceRouter, error) {
 	var routings = make([]*ServiceRouting, 0)
+	var tree = &routeNode{}
+	var err error
 	for i := range serviceRouting {
 		routings = append(routings, &serviceRouting[i])
+		if addErr := tree.add(&serviceRouting[i]); addErr != nil && err == nil {
+			err = addErr
+		}
 	}
-	return &ServiceRouter{routings}
+	return &ServiceRouter{serviceRouting: routings, tree: tree}, err
 }
 
 //RouteToService calls web service url, with passed in json request, and encodes http json response into passed response
@@ -455,9 +738,12 @@ func (c *ToolboxHTTPClient) Request(method, url string, request, response interf
 			return fmt.Errorf("%v unable read body %v", errorPrefix, err)
 		}
 		if len(body) == 0 {
-			return fmt.Errorf("%v response body was empty", errorPrefix)
+			return fmt.Errorf("%v%w", errorPrefix, ErrEmptyResponseBody)
 		}
 
+		if serverResponse.StatusCode >= http.StatusBadRequest {
+			updateErrorCode(body, response, decoderFactory)
+		}
 		if serverResponse.StatusCode == http.StatusNotFound {
 			updateResponse(serverResponse, response)
 			return nil
@@ -475,6 +761,9 @@ func (c *ToolboxHTTPClient) Request(method, url string, request, response interf
 	return nil
 }
 
+//ErrEmptyResponseBody is wrapped by RouteToService error if server responds without a body, check it with errors.Is
+var ErrEmptyResponseBody = errors.New("response body was empty")
+
 //StatucCodeMutator client side reponse optional interface
 type StatucCodeMutator interface {
 	SetStatusCode(code int)
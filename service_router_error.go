@@ -0,0 +1,132 @@
+package toolbox
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+//ErrorCodeAccessor server side error accessor providing a machine readable error code, used with StatucCodeAccessor by ServiceRouter
+type ErrorCodeAccessor interface {
+	GetErrorCode() string
+}
+
+//ErrorCodeMutator client side reponse optional interface, it is set with error response code
+type ErrorCodeMutator interface {
+	SetErrorCode(code string)
+}
+
+//HTTPError represents a handler error with HTTP status and machine readable error code
+type HTTPError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Err        error //optional underlying error
+}
+
+//Error returns an error message
+func (e *HTTPError) Error() string {
+	if e.Message == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+//Unwrap returns underlying error
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+//GetStatusCode returns HTTP status code
+func (e *HTTPError) GetStatusCode() int {
+	return e.StatusCode
+}
+
+//GetErrorCode returns error code
+func (e *HTTPError) GetErrorCode() string {
+	return e.Code
+}
+
+//NewHTTPError creates a new HTTP error
+func NewHTTPError(statusCode int, code, message string) *HTTPError {
+	return &HTTPError{StatusCode: statusCode, Code: code, Message: message}
+}
+
+//ErrorResponse represents service router error response body
+type ErrorResponse struct {
+	Status  int           `json:"status"`
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Errors  []*FieldError `json:"errors,omitempty"`
+}
+
+//NewErrorResponse creates an error response, status, code and message are taken from StatucCodeAccessor and ErrorCodeAccessor errors,
+//otherwise 500 status, code and message derived from status text (i.e. internal_server_error) are used, so that internal error details are not exposed.
+func NewErrorResponse(err error) *ErrorResponse {
+	var result = &ErrorResponse{Status: http.StatusInternalServerError}
+	statusCodeAccessor, hasStatusCode := err.(StatucCodeAccessor)
+	if hasStatusCode && statusCodeAccessor.GetStatusCode() > 0 {
+		result.Status = statusCodeAccessor.GetStatusCode()
+	}
+	errorCodeAccessor, hasErrorCode := err.(ErrorCodeAccessor)
+	if hasErrorCode {
+		result.Code = errorCodeAccessor.GetErrorCode()
+	}
+	result.Message = http.StatusText(result.Status)
+	if hasStatusCode || hasErrorCode {
+		result.Message = err.Error()
+	}
+	if result.Code == "" {
+		result.Code = strings.Replace(strings.ToLower(http.StatusText(result.Status)), " ", "_", -1)
+	}
+	if validationError, ok := err.(*ValidationError); ok {
+		result.Errors = validationError.Errors
+	}
+	return result
+}
+
+//WriteServiceRoutingError writes error response with status and code from the error, the response is encoded with service routing encoder
+func WriteServiceRoutingError(response http.ResponseWriter, request *http.Request, serviceRouting *ServiceRouting, err error) error {
+	errorResponse := NewErrorResponse(err)
+	responseContentType := getContentTypeOrJSONContentType(request.Header.Get(contentTypeHeader))
+	if strings.HasPrefix(responseContentType, csvContentType) || strings.HasPrefix(responseContentType, tsvContentType) {
+		responseContentType = jsonContentType
+	}
+	var encoderFactory = DefaultEncoderFactory
+	if serviceRouting != nil {
+		encoderFactory = serviceRouting.getEncoderFactory(responseContentType)
+	}
+	response.Header().Set(contentTypeHeader, responseContentType)
+	response.WriteHeader(errorResponse.Status)
+	if err := encoderFactory.Create(response).Encode(errorResponse); err != nil {
+		return fmt.Errorf("failed to encode error response %v, due to %v", errorResponse, err)
+	}
+	return nil
+}
+
+//errorResponseMutator client side response optional interface, it is set with decoded error response
+type errorResponseMutator interface {
+	setErrorResponse(errorResponse *ErrorResponse)
+}
+
+//updateErrorCode updates response error code or error response from error response body if applicable
+func updateErrorCode(body []byte, response interface{}, decoderFactory DecoderFactory) {
+	errorCodeMutator, hasCodeMutator := response.(ErrorCodeMutator)
+	responseMutator, hasResponseMutator := response.(errorResponseMutator)
+	if !hasCodeMutator && !hasResponseMutator {
+		return
+	}
+	var errorResponse = &ErrorResponse{}
+	if err := decoderFactory.Create(strings.NewReader(string(body))).Decode(errorResponse); err != nil {
+		return
+	}
+	if hasResponseMutator {
+		responseMutator.setErrorResponse(errorResponse)
+	}
+	if hasCodeMutator && errorResponse.Code != "" {
+		errorCodeMutator.SetErrorCode(errorResponse.Code)
+	}
+}
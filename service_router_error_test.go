@@ -0,0 +1,107 @@
+package toolbox_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+type errorCodeResponse struct {
+	Status    int
+	ErrorCode string
+}
+
+func (r *errorCodeResponse) SetStatusCode(code int) {
+	r.Status = code
+}
+
+func (r *errorCodeResponse) SetErrorCode(code string) {
+	r.ErrorCode = code
+}
+
+func TestServiceRouter_Errors(t *testing.T) {
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod: "POST",
+			URI:        "/v1/orders",
+			Handler: func(order *validatedOrder) (*validatedOrder, error) {
+				if order.ID == "XX1" {
+					return nil, toolbox.NewHTTPError(http.StatusConflict, "order_exists", "order XX1 already exists")
+				}
+				if order.ID == "XX2" {
+					return nil, errors.New("storage is down")
+				}
+				return order, nil
+			},
+			Parameters: []string{"order"},
+			Validate:   true,
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/orders/{id:int}",
+			Handler: func(id int, limit int) int {
+				return id + limit
+			},
+			Parameters: []string{"id", "limit"},
+		},
+	)
+	var useCases = []struct {
+		description string
+		method      string
+		uri         string
+		body        string
+		status      int
+		expect      string
+	}{
+		{description: "valid request", method: "POST", uri: "/v1/orders", body: `{"id":"AB1","status":"new","items":[{"name":"desk","quantity":1,"Price":2}]}`, status: 200, expect: `"id":"AB1"`},
+		{description: "validation error", method: "POST", uri: "/v1/orders", body: `{"id":"AB1","status":"x","items":[{"name":"desk","quantity":1,"Price":2}]}`, status: 400,
+			expect: `{"status":400,"code":"validation_failed","message":"validation failed: status: must be one of new, paid, shipped","errors":[{"field":"status","rule":"oneof","message":"must be one of new, paid, shipped","value":"x"}]}`},
+		{description: "handler HTTP error", method: "POST", uri: "/v1/orders", body: `{"id":"XX1","status":"new","items":[{"name":"desk","quantity":1,"Price":2}]}`, status: 409,
+			expect: `{"status":409,"code":"order_exists","message":"order XX1 already exists"}`},
+		{description: "handler error", method: "POST", uri: "/v1/orders", body: `{"id":"XX2","status":"new","items":[{"name":"desk","quantity":1,"Price":2}]}`, status: 500,
+			expect: `{"status":500,"code":"internal_server_error","message":"Internal Server Error"}`},
+		{description: "invalid body", method: "POST", uri: "/v1/orders", body: `{"id":`, status: 400, expect: `"code":"invalid_request"`},
+		{description: "invalid query parameter", method: "GET", uri: "/v1/orders/1?limit=abc", status: 400, expect: `"code":"invalid_request"`},
+		{description: "valid query parameter", method: "GET", uri: "/v1/orders/1?limit=2", status: 200, expect: `3`},
+	}
+	unvalidated := toolbox.NewServiceRouter(toolbox.ServiceRouting{
+		HTTPMethod: "POST",
+		URI:        "/v1/orders",
+		Handler: func(order *validatedOrder) *validatedOrder {
+			return order
+		},
+		Parameters: []string{"order"},
+	})
+	{
+		request := httptest.NewRequest("POST", "/v1/orders", strings.NewReader(`{"id":"AB1","status":"x"}`))
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		assert.Nil(t, unvalidated.Route(response, request))
+		assert.Equal(t, 200, response.Code, "validation is opt-in")
+	}
+	for _, useCase := range useCases {
+		request := httptest.NewRequest(useCase.method, useCase.uri, strings.NewReader(useCase.body))
+		if useCase.body != "" {
+			request.Header.Set("Content-Type", "application/json")
+		}
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, request), useCase.description)
+		assert.Equal(t, useCase.status, response.Code, useCase.description)
+		assert.Contains(t, response.Body.String(), useCase.expect, useCase.description)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_ = router.Route(writer, request)
+	}))
+	defer server.Close()
+	var response = &errorCodeResponse{}
+	err := toolbox.RouteToService("post", server.URL+"/v1/orders", map[string]interface{}{"id": "XX1", "status": "new", "items": []interface{}{map[string]interface{}{"name": "a", "quantity": 1, "Price": 1}}}, response)
+	assert.Nil(t, err)
+	assert.Equal(t, 409, response.Status)
+	assert.Equal(t, "order_exists", response.ErrorCode)
+}
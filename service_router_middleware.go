@@ -0,0 +1,349 @@
+package toolbox
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//RequestIDHeader default request ID header
+const RequestIDHeader = "X-Request-Id"
+
+//RouteContext represents a request matched with service routing, it is passed through middleware chain
+type RouteContext struct {
+	Routing    *ServiceRouting        //matched routing, the first matched one in global middleware, nil for automatic OPTIONS or 405 response
+	Request    *http.Request          //request, middleware can replace it before calling next handler
+	Response   http.ResponseWriter    //response writer, middleware can wrap it before calling next handler
+	Parameters map[string]interface{} //decoded handler parameters, middleware can add @ prefixed parameters, i.e. @requestID
+	Result     interface{}            //handler result, available once next handler returns
+	Allowed    []string               //HTTP methods allowed for the request path
+	writer     *routeResponseWriter
+	proceed    bool //true if router should try the following matched routing
+	candidate  *routeCandidate
+	extracted  bool
+	extractErr error
+}
+
+//ExtractParameters decodes matched routing parameters, including request body, into Parameters keeping @ prefixed ones.
+//Parameters are extracted after global middlewares call next handler, thus a global middleware rejecting the request,
+//i.e. NewAuthMiddleware, prevents body decoding; global middleware that needs parameters before calling next handler has to call this method.
+func (c *RouteContext) ExtractParameters() error {
+	if c.candidate == nil || c.extracted {
+		return c.extractErr
+	}
+	c.extracted = true
+	parameters, err := c.candidate.extractParameters(c.Request, c.Response, c.candidate.parameters)
+	if c.extractErr = err; err != nil {
+		return err
+	}
+	if parameters == nil {
+		parameters = make(map[string]interface{})
+	}
+	for name, value := range c.Parameters {
+		if _, has := parameters[name]; !has && strings.HasPrefix(name, "@") {
+			parameters[name] = value
+		}
+	}
+	c.Parameters = parameters
+	return nil
+}
+
+//StatusCode returns response status code, 0 if nothing has been written yet
+func (c *RouteContext) StatusCode() int {
+	if c.writer.statusCode == 0 && c.writer.size > 0 {
+		return http.StatusOK
+	}
+	return c.writer.statusCode
+}
+
+//Size returns number of written response body bytes
+func (c *RouteContext) Size() int {
+	return c.writer.size
+}
+
+//Written returns true if response status or body has been written
+func (c *RouteContext) Written() bool {
+	return c.writer.written()
+}
+
+//RouteHandler represents a routed request handler
+type RouteHandler func(context *RouteContext) error
+
+//Middleware wraps a route handler, it can act before and after calling next handler or respond without calling it
+type Middleware func(next RouteHandler) RouteHandler
+
+//Use adds global middlewares, the first added middleware is the outermost one
+func (r *ServiceRouter) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+func chainMiddlewares(handler RouteHandler, middlewares []Middleware) RouteHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+//routeAttemptError represents an error of a matched routing invocation, router tries the following matched routing
+type routeAttemptError struct {
+	err        error
+	statusCode int //optional response status if no other matched routing succeeds
+	routing    *ServiceRouting
+}
+
+func (e *routeAttemptError) Error() string {
+	return e.err.Error()
+}
+
+//routeResponseWriter tracks response status code and size
+type routeResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int
+}
+
+func (w *routeResponseWriter) written() bool {
+	return w.statusCode != 0 || w.size > 0
+}
+
+func (w *routeResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *routeResponseWriter) Write(data []byte) (int, error) {
+	written, err := w.ResponseWriter.Write(data)
+	w.size += written
+	return written, err
+}
+
+//Flush flushes underlying response writer if supported
+func (w *routeResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+//NewRecoveryMiddleware creates a middleware recovering handler panic into 500 response, optional onPanic is notified with recovered value
+func NewRecoveryMiddleware(onPanic func(context *RouteContext, recovered interface{})) Middleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(context *RouteContext) (err error) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if onPanic != nil {
+					onPanic(context, recovered)
+				}
+				if !context.Written() {
+					http.Error(context.Response, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+				context.proceed = false
+				err = nil
+			}()
+			return next(context)
+		}
+	}
+}
+
+//NewRequestIDMiddleware creates a middleware reusing or generating request ID, the ID is set on request and response header and as @requestID parameter.
+//If header is empty X-Request-Id is used.
+func NewRequestIDMiddleware(header string) Middleware {
+	if header == "" {
+		header = RequestIDHeader
+	}
+	return func(next RouteHandler) RouteHandler {
+		return func(context *RouteContext) error {
+			requestID := context.Request.Header.Get(header)
+			if requestID == "" {
+				requestID = newRequestID()
+				context.Request.Header.Set(header, requestID)
+			}
+			context.Response.Header().Set(header, requestID)
+			if context.Parameters != nil {
+				context.Parameters["@requestID"] = requestID
+			}
+			return next(context)
+		}
+	}
+}
+
+func newRequestID() string {
+	var data = make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(data)
+}
+
+//NewAuthMiddleware creates a middleware responding with 401 status if authenticate returns an error, automatic OPTIONS and 405 responses are not authenticated
+func NewAuthMiddleware(authenticate func(context *RouteContext) error) Middleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(context *RouteContext) error {
+			if context.Routing == nil {
+				return next(context)
+			}
+			if err := authenticate(context); err != nil {
+				http.Error(context.Response, err.Error(), http.StatusUnauthorized)
+				return nil
+			}
+			return next(context)
+		}
+	}
+}
+
+//NewAccessLogMiddleware creates a middleware logging method, URI, final status, response size and duration of each request, i.e. with log.Printf.
+//Request failing with an error without written response is logged with 500 status.
+func NewAccessLogMiddleware(logf func(format string, args ...interface{})) Middleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(context *RouteContext) error {
+			started := time.Now()
+			err := next(context)
+			status := context.StatusCode()
+			if status == 0 {
+				status = http.StatusOK
+				if err != nil {
+					status = http.StatusInternalServerError
+				}
+			}
+			logf("%v %v %d %d %v", context.Request.Method, context.Request.RequestURI, status, context.Size(), time.Since(started))
+			return err
+		}
+	}
+}
+
+//CORSOptions represents cross origin resource sharing options
+type CORSOptions struct {
+	AllowedOrigins   []string //allowed origins, * allows any origin
+	AllowedHeaders   []string //allowed request headers, if empty preflight requested headers are allowed
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int //preflight cache duration in seconds
+}
+
+func (o *CORSOptions) isOriginAllowed(origin string) bool {
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+//NewCORSMiddleware creates a middleware setting CORS headers for allowed origins, preflight requests are answered with allowed route methods
+func NewCORSMiddleware(options *CORSOptions) Middleware {
+	if options == nil {
+		options = &CORSOptions{AllowedOrigins: []string{"*"}}
+	}
+	return func(next RouteHandler) RouteHandler {
+		return func(context *RouteContext) error {
+			origin := context.Request.Header.Get("Origin")
+			if origin == "" || !options.isOriginAllowed(origin) {
+				return next(context)
+			}
+			header := context.Response.Header()
+			header.Add("Vary", "Origin")
+			header.Set("Access-Control-Allow-Origin", origin)
+			if options.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+			if len(options.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(options.ExposedHeaders, ", "))
+			}
+			requestedMethod := context.Request.Header.Get("Access-Control-Request-Method")
+			if context.Request.Method != MethodOptions || requestedMethod == "" {
+				return next(context)
+			}
+			header.Set("Access-Control-Allow-Methods", strings.Join(context.Allowed, ", "))
+			if len(options.AllowedHeaders) > 0 {
+				header.Set("Access-Control-Allow-Headers", strings.Join(options.AllowedHeaders, ", "))
+			} else if requested := context.Request.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				header.Set("Access-Control-Allow-Headers", requested)
+			}
+			if options.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", AsString(options.MaxAge))
+			}
+			return next(context)
+		}
+	}
+}
+
+//gzipResponseWriter compresses response body unless status has no body or response is already encoded
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer      *gzip.Writer
+	level       int
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	header := w.Header()
+	if statusCode != http.StatusNoContent && statusCode != http.StatusNotModified && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.writer, _ = gzip.NewWriterLevel(w.ResponseWriter, w.level)
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.writer == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.writer.Write(data)
+}
+
+//Flush flushes compressed data and underlying response writer if supported
+func (w *gzipResponseWriter) Flush() {
+	if w.writer != nil {
+		_ = w.writer.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) Close() error {
+	if w.writer == nil {
+		return nil
+	}
+	return w.writer.Close()
+}
+
+//NewCompressionMiddleware creates a middleware compressing response with gzip if accepted by the client, level uses compress/gzip levels, 0 uses default compression
+func NewCompressionMiddleware(level int) Middleware {
+	if level == 0 || level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	return func(next RouteHandler) RouteHandler {
+		return func(context *RouteContext) error {
+			if context.Routing == nil || context.Request.Method == MethodHead || !strings.Contains(context.Request.Header.Get("Accept-Encoding"), "gzip") {
+				return next(context)
+			}
+			context.Response.Header().Add("Vary", "Accept-Encoding")
+			response := context.Response
+			writer := &gzipResponseWriter{ResponseWriter: response, level: level}
+			context.Response = writer
+			err := next(context)
+			context.Response = response
+			if closeErr := writer.Close(); err == nil && closeErr != nil {
+				err = closeErr
+			}
+			return err
+		}
+	}
+}
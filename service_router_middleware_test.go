@@ -0,0 +1,249 @@
+package toolbox_test
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+func TestServiceRouter_Middleware(t *testing.T) {
+	var trace []string
+	var traceMiddleware = func(name string) toolbox.Middleware {
+		return func(next toolbox.RouteHandler) toolbox.RouteHandler {
+			return func(context *toolbox.RouteContext) error {
+				trace = append(trace, fmt.Sprintf("%v:before:%v", name, context.Parameters["ids"]))
+				err := next(context)
+				trace = append(trace, fmt.Sprintf("%v:after:%v", name, context.Result))
+				return err
+			}
+		}
+	}
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod:  "GET",
+			URI:         "/v1/reverse/{ids}",
+			Handler:     ReverseService{}.Reverse,
+			Parameters:  []string{"ids"},
+			Middlewares: []toolbox.Middleware{traceMiddleware("route")},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/request/id",
+			Handler: func(requestID string) string {
+				return requestID
+			},
+			Parameters: []string{"@requestID"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "POST",
+			URI:        "/v1/reverse",
+			Handler:    ReverseService{}.Reverse,
+			Parameters: []string{"ids"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/panic",
+			Handler: func() string {
+				panic("test")
+			},
+		},
+	)
+	var logged []string
+	var recovered interface{}
+	router.Use(
+		toolbox.NewRecoveryMiddleware(func(context *toolbox.RouteContext, value interface{}) {
+			recovered = value
+		}),
+		toolbox.NewAccessLogMiddleware(func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}),
+		toolbox.NewRequestIDMiddleware(""),
+		toolbox.NewCORSMiddleware(nil),
+		traceMiddleware("global"),
+	)
+
+	{ //global middleware wraps routing middleware, parameters are extracted after global middlewares
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, httptest.NewRequest("GET", "/v1/reverse/1,7,3", nil)))
+		assert.Equal(t, "[3,7,1]", trimNewLine(response.Body.String()))
+		assert.Equal(t, []string{
+			"global:before:<nil>",
+			"route:before:[1 7 3]",
+			"route:after:[3 7 1]",
+			"global:after:[3 7 1]",
+		}, trace)
+		assert.Equal(t, 1, len(logged))
+		assert.Regexp(t, `^GET /v1/reverse/1,7,3 200 8 `, logged[0])
+		assert.Equal(t, 32, len(response.Header().Get(toolbox.RequestIDHeader)))
+	}
+	{ //request ID parameter
+		request := httptest.NewRequest("GET", "/v1/request/id", nil)
+		request.Header.Set(toolbox.RequestIDHeader, "abc")
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, request))
+		assert.Equal(t, `"abc"`, trimNewLine(response.Body.String()))
+		assert.Equal(t, "abc", response.Header().Get(toolbox.RequestIDHeader))
+	}
+	{ //panic recovery
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, httptest.NewRequest("GET", "/v1/panic", nil)))
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+		assert.Equal(t, "test", recovered)
+	}
+	{ //automatic 405 response passes through global middleware
+		logged = nil
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, httptest.NewRequest("POST", "/v1/panic", nil)))
+		assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+		assert.Regexp(t, `^POST /v1/panic 405 0 `, logged[0])
+	}
+	{ //parameter extraction error response passes through global middleware
+		logged = nil
+		request := httptest.NewRequest("POST", "/v1/reverse", strings.NewReader(`[1,`))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Origin", "http://example.com")
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, request))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), `"code":"invalid_request"`)
+		assert.Equal(t, 32, len(response.Header().Get(toolbox.RequestIDHeader)))
+		assert.Equal(t, "http://example.com", response.Header().Get("Access-Control-Allow-Origin"))
+		if assert.Equal(t, 1, len(logged)) {
+			assert.Regexp(t, `^POST /v1/reverse 400 `, logged[0])
+		}
+	}
+}
+
+func TestServiceRouter_GlobalMiddlewareParameters(t *testing.T) {
+	var extracted []interface{}
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod: "POST",
+			URI:        "/v1/reverse",
+			Handler:    ReverseService{}.Reverse,
+			Parameters: []string{"ids"},
+		},
+	)
+	router.Use(
+		toolbox.NewAuthMiddleware(func(context *toolbox.RouteContext) error {
+			if context.Request.Header.Get("Authorization") != "Bearer secret" {
+				return errors.New("invalid token")
+			}
+			return nil
+		}),
+		func(next toolbox.RouteHandler) toolbox.RouteHandler {
+			return func(context *toolbox.RouteContext) error {
+				if err := context.ExtractParameters(); err == nil {
+					extracted = append(extracted, context.Parameters["ids"])
+				}
+				return next(context)
+			}
+		},
+	)
+	var useCases = []struct {
+		description   string
+		authorization string
+		body          string
+		expectCode    int
+		expectBody    string
+		expectIDs     []interface{}
+	}{
+		{description: "unauthorized request body is not decoded", body: `[1,`, expectCode: http.StatusUnauthorized},
+		{description: "invalid body", authorization: "Bearer secret", body: `[1,`, expectCode: http.StatusBadRequest},
+		{description: "parameters extracted by global middleware", authorization: "Bearer secret", body: `[1,7,3]`, expectCode: http.StatusOK, expectBody: "[3,7,1]", expectIDs: []interface{}{&[]int{1, 7, 3}}},
+	}
+	for _, useCase := range useCases {
+		extracted = nil
+		request := httptest.NewRequest("POST", "/v1/reverse", strings.NewReader(useCase.body))
+		request.Header.Set("Content-Type", "application/json")
+		if useCase.authorization != "" {
+			request.Header.Set("Authorization", useCase.authorization)
+		}
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, request), useCase.description)
+		assert.Equal(t, useCase.expectCode, response.Code, useCase.description)
+		assert.EqualValues(t, useCase.expectIDs, extracted, useCase.description)
+		if useCase.expectBody != "" {
+			assert.Equal(t, useCase.expectBody, trimNewLine(response.Body.String()), useCase.description)
+		}
+	}
+}
+
+func TestServiceRouter_AuthCORSCompression(t *testing.T) {
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/items",
+			Handler: func() []string {
+				return []string{"a", "b"}
+			},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "DELETE",
+			URI:        "/v1/items",
+			Handler: func() string {
+				return "deleted"
+			},
+			Middlewares: []toolbox.Middleware{toolbox.NewAuthMiddleware(func(context *toolbox.RouteContext) error {
+				if context.Request.Header.Get("Authorization") != "Bearer secret" {
+					return errors.New("invalid token")
+				}
+				return nil
+			})},
+		},
+	)
+	router.Use(
+		toolbox.NewCORSMiddleware(&toolbox.CORSOptions{AllowedOrigins: []string{"http://example.com"}, MaxAge: 60}),
+		toolbox.NewCompressionMiddleware(0),
+	)
+
+	{ //unauthorized
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, httptest.NewRequest("DELETE", "/v1/items", nil)))
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+	}
+	{ //authorized
+		request := httptest.NewRequest("DELETE", "/v1/items", nil)
+		request.Header.Set("Authorization", "Bearer secret")
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, request))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `"deleted"`, trimNewLine(response.Body.String()))
+	}
+	{ //CORS preflight
+		request := httptest.NewRequest("OPTIONS", "/v1/items", nil)
+		request.Header.Set("Origin", "http://example.com")
+		request.Header.Set("Access-Control-Request-Method", "DELETE")
+		request.Header.Set("Access-Control-Request-Headers", "Authorization")
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, request))
+		assert.Equal(t, http.StatusNoContent, response.Code)
+		assert.Equal(t, "http://example.com", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "DELETE, GET, HEAD, OPTIONS", response.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization", response.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "60", response.Header().Get("Access-Control-Max-Age"))
+	}
+	{ //gzip compression with disallowed origin
+		request := httptest.NewRequest("GET", "/v1/items", nil)
+		request.Header.Set("Origin", "http://other.com")
+		request.Header.Set("Accept-Encoding", "gzip, deflate")
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, request))
+		assert.Equal(t, "", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "gzip", response.Header().Get("Content-Encoding"))
+		reader, err := gzip.NewReader(response.Body)
+		if assert.Nil(t, err) {
+			body, err := ioutil.ReadAll(reader)
+			assert.Nil(t, err)
+			assert.Equal(t, `["a","b"]`, trimNewLine(string(body)))
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/viant/toolbox"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -230,3 +231,91 @@ func TestServiceRouter(t *testing.T) {
 	}
 
 }
+
+type acceptedProduct struct {
+	ID   int    `csv:"id" json:"id"`
+	Name string `csv:"name" json:"name"`
+}
+
+func TestServiceRouter_AcceptDelimited(t *testing.T) {
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/products",
+			Handler: func() []*acceptedProduct {
+				return []*acceptedProduct{{ID: 1, Name: "desk"}}
+			},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/count",
+			Handler: func() int {
+				return 3
+			},
+		},
+	)
+	var useCases = []struct {
+		description string
+		uri         string
+		accept      string
+		contentType string
+		expect      string
+	}{
+		{description: "csv", uri: "/v1/products", accept: "text/csv", contentType: "text/csv", expect: "id,name\r\n1,desk"},
+		{description: "tsv", uri: "/v1/products", accept: "text/tab-separated-values", contentType: "text/tab-separated-values", expect: "id\tname\r\n1\tdesk"},
+		{description: "csv preferred by q", uri: "/v1/products", accept: "application/json;q=0.5, text/csv", contentType: "text/csv", expect: "id,name\r\n1,desk"},
+		{description: "json preferred by q", uri: "/v1/products", accept: "application/json, text/csv;q=0.1", contentType: "application/json", expect: `[{"id":1,"name":"desk"}]`},
+		{description: "json listed first", uri: "/v1/products", accept: "application/json, text/csv", contentType: "application/json", expect: `[{"id":1,"name":"desk"}]`},
+		{description: "csv excluded", uri: "/v1/products", accept: "text/csv;q=0", contentType: "application/json", expect: `[{"id":1,"name":"desk"}]`},
+		{description: "non tabular result", uri: "/v1/count", accept: "text/csv", contentType: "application/json", expect: "3"},
+	}
+	for _, useCase := range useCases {
+		request := httptest.NewRequest("GET", useCase.uri, nil)
+		request.Header.Set("Accept", useCase.accept)
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, request), useCase.description)
+		assert.Equal(t, 200, response.Code, useCase.description)
+		assert.Equal(t, useCase.contentType, response.Header().Get("Content-Type"), useCase.description)
+		assert.Equal(t, useCase.expect, strings.TrimSpace(response.Body.String()), useCase.description)
+	}
+}
+
+func TestServiceRouter_SliceQueryParameters(t *testing.T) {
+	router := toolbox.NewServiceRouter(
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/sum",
+			Handler: func(ids []int) int {
+				var result = 0
+				for _, id := range ids {
+					result += id
+				}
+				return result
+			},
+			Parameters: []string{"ids"},
+		},
+		toolbox.ServiceRouting{
+			HTTPMethod: "GET",
+			URI:        "/v1/echo",
+			Handler: func(text string) string {
+				return text
+			},
+			Parameters: []string{"text"},
+		},
+	)
+	var useCases = []struct {
+		description string
+		uri         string
+		expect      string
+	}{
+		{description: "comma separated slice", uri: "/v1/sum?ids=1,2,3", expect: "6"},
+		{description: "single item slice", uri: "/v1/sum?ids=4", expect: "4"},
+		{description: "string with coma is not split", uri: "/v1/echo?text=a,b", expect: `"a,b"`},
+	}
+	for _, useCase := range useCases {
+		response := httptest.NewRecorder()
+		assert.Nil(t, router.Route(response, httptest.NewRequest("GET", useCase.uri, nil)), useCase.description)
+		assert.Equal(t, 200, response.Code, useCase.description)
+		assert.Equal(t, useCase.expect, strings.TrimSpace(response.Body.String()), useCase.description)
+	}
+}
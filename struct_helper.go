@@ -5,6 +5,7 @@ import (
 	"github.com/go-errors/errors"
 	"reflect"
 	"strings"
+	"unsafe"
 )
 
 const (
@@ -12,9 +13,11 @@ const (
 	anonymousKey  = "anonymous"
 	fieldIndexKey = "fieldIndex"
 	defaultKey    = "default"
+	requiredKey   = "required"
+	converterKey  = "converter"
 )
 
-var columnMapping = []string{"column", "dateLayout", "dateFormat", "autoincrement", "primaryKey", "sequence", "valueMap", defaultKey, anonymousKey}
+var columnMapping = []string{"column", "dateLayout", "dateFormat", "autoincrement", "primaryKey", "sequence", "valueMap", defaultKey, anonymousKey, requiredKey, converterKey}
 
 // ScanStructFunc scan supplied struct methods
 func ScanStructMethods(structOrItsType interface{}, depth int, handler func(method reflect.Method) error) error {
@@ -120,26 +123,33 @@ func ProcessStruct(aStruct interface{}, handler func(fieldType reflect.StructFie
 		}
 		field := structValue.Field(i)
 		if !IsStruct(field) {
-			fields[fieldType.Name] = &StructField{Type: fieldType, Value: field, Owner: structValue}
+			if fieldType.PkgPath == "" {
+				fields[fieldType.Name] = &StructField{Type: fieldType, Value: field, Owner: structValue}
+			}
 			continue
 		}
+		if fieldType.PkgPath != "" { //exported fields of unexported embedded struct are promoted as with encoding/json
+			if !structValue.CanAddr() {
+				addressable := reflect.New(structType).Elem()
+				addressable.Set(structValue)
+				structValue = addressable
+			}
+			field = reflect.NewAt(fieldType.Type, unsafe.Pointer(structValue.Field(i).UnsafeAddr())).Elem()
+		}
 		var aStruct interface{}
 		if fieldType.Type.Kind() == reflect.Ptr {
 			if field.IsNil() {
 				if !field.CanSet() {
 					continue
 				}
-				structValue.Field(i).Set(reflect.New(fieldType.Type.Elem()))
+				field.Set(reflect.New(fieldType.Type.Elem()))
 			}
 			aStruct = field.Interface()
 		} else {
-
 			if field.CanAddr() {
 				aStruct = field.Addr().Interface()
-			} else if field.CanInterface() {
-				aStruct = field.Interface()
 			} else {
-				continue
+				aStruct = field.Interface()
 			}
 		}
 		if err := ProcessStruct(aStruct, func(fieldType reflect.StructField, field reflect.Value) error {
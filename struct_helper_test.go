@@ -38,6 +38,55 @@ func TestProcessStruct(t *testing.T) {
 	assert.Equal(t, "!@#", userMap["Other"])
 }
 
+type processedAudit struct {
+	Created string
+}
+
+func TestProcessStruct_UnexportedEmbedded(t *testing.T) {
+	type Super struct {
+		Parent int
+	}
+	type User struct {
+		Super
+		processedAudit
+		Name string
+	}
+	type Account struct {
+		*processedAudit
+		Name string
+	}
+	var useCases = []struct {
+		description string
+		source      interface{}
+		expect      interface{}
+	}{
+		{description: "embedded struct pointer", source: &User{Super: Super{Parent: 12}, processedAudit: processedAudit{Created: "now"}, Name: "foo"}, expect: "now"},
+		{description: "embedded struct value", source: User{processedAudit: processedAudit{Created: "now"}, Name: "foo"}, expect: "now"},
+		{description: "embedded pointer", source: &Account{processedAudit: &processedAudit{Created: "now"}, Name: "foo"}, expect: "now"},
+		{description: "nil embedded pointer", source: &Account{Name: "foo"}, expect: ""},
+	}
+	for _, useCase := range useCases {
+		var fields = make(map[string]interface{})
+		err := toolbox.ProcessStruct(useCase.source, func(fieldType reflect.StructField, field reflect.Value) error {
+			fields[fieldType.Name] = field.Interface()
+			return nil
+		})
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, "foo", fields["Name"], useCase.description)
+		assert.Equal(t, useCase.expect, fields["Created"], "exported fields of unexported embedded struct are promoted: "+useCase.description)
+	}
+
+	user := &User{}
+	err := toolbox.ProcessStruct(user, func(fieldType reflect.StructField, field reflect.Value) error {
+		if fieldType.Name == "Created" {
+			field.SetString("set")
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "set", user.Created)
+}
+
 func TestBuildTagMapping(t *testing.T) {
 
 	type User struct {
@@ -0,0 +1,78 @@
+package fileset_info
+
+import (
+	"context"
+	yaml "gopkg.in/yaml.v2"
+	stdio "io"
+	_ "net/http/pprof"
+)
+
+// Number represents a numeric type constraint
+type Number interface {
+	~int | ~int64 | float64
+}
+
+// Reader represents a test interface
+type Reader interface {
+	stdio.Closer
+	Named
+	Read(ctx context.Context, key string) ([]byte, error)
+}
+
+// Named represents named interface
+type Named interface {
+	Name() string
+}
+
+// List represents a generic list
+type List[T any] struct {
+	Items []T `json:"items,omitempty" yaml:"items"`
+}
+
+// Pair represents a generic pair
+type Pair[K comparable, V Number] struct {
+	Key   K
+	Value V
+}
+
+// Holder embeds generic list
+type Holder struct {
+	*List[string]
+	Pairs Pair[string, int] "json:\"pairs\""
+}
+
+// Kind represents a kind enumeration
+type Kind int
+
+// Size represents a size
+type Size = int64
+
+const (
+	KindUnknown Kind = iota //unknown kind
+	KindFile
+	KindDir
+	_
+	KindLink = KindDir + 10
+)
+
+const (
+	_  = iota
+	KB = 1 << (10 * iota)
+	MB
+)
+
+const (
+	Prefix       = "pre"
+	Label        = Prefix + "fix"
+	Ratio        = 1.5
+	Enabled      = KB > 1
+	Limit   Size = GB / 2
+)
+
+// DefaultList represents default list
+var DefaultList = &List[int]{}
+
+var (
+	counter int
+	encoder = yaml.Marshal
+)
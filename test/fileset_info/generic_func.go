@@ -0,0 +1,27 @@
+package fileset_info
+
+const GB = MB << 10
+
+// Push appends an item
+func (l *List[T]) Push(item T) {
+	l.Items = append(l.Items, item)
+}
+
+// String returns kind name
+func (k Kind) String() string {
+	return "kind"
+}
+
+// Get returns pair value
+func (p Pair[K, V]) Get() V {
+	return p.Value
+}
+
+// Map maps supplied items
+func Map[T, R any](items []T, fn func(T) R) []R {
+	var result = make([]R, 0, len(items))
+	for _, item := range items {
+		result = append(result, fn(item))
+	}
+	return result
+}
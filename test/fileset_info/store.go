@@ -0,0 +1,75 @@
+package fileset_info
+
+// Store represents a user store
+type Store interface {
+	Named
+	Get(key string) (*User, error)
+	Put(key string, users ...*User) error
+}
+
+type memoryStore struct {
+	users map[string]*User
+}
+
+// Get returns a user
+func (s *memoryStore) Get(key string) (*User, error) {
+	return s.users[key], nil
+}
+
+// Put stores users
+func (s *memoryStore) Put(key string, users ...*User) error {
+	return nil
+}
+
+// Name returns store name
+func (s memoryStore) Name() string {
+	return "memory"
+}
+
+// First returns first user
+func (s *memoryStore) First() *User {
+	return nil
+}
+
+// sharedStore embeds a pointer, its value implements Store
+type sharedStore struct {
+	*memoryStore
+}
+
+// readOnlyStore embeds a value, only its pointer implements Store
+type readOnlyStore struct {
+	memoryStore
+}
+
+// badStore uses incompatible signature
+type badStore struct{}
+
+// Get returns a user
+func (b badStore) Get(key string) (User, error) {
+	return User{}, nil
+}
+
+// Put stores users
+func (b badStore) Put(key string, users ...*User) error {
+	return nil
+}
+
+// Name returns store name
+func (b badStore) Name() string {
+	return "bad"
+}
+
+// NewStore creates a store
+func NewStore() Store {
+	return &memoryStore{}
+}
+
+// NewUser creates a user
+func NewUser(name string) (*User, error) {
+	return &User{Name: name}, nil
+}
+
+// LoadUsers loads users
+func LoadUsers() ([]*User, error) {
+	return nil, nil
+}
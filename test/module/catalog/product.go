@@ -0,0 +1,14 @@
+package catalog
+
+import (
+	. "example.com/shop/model/customer"
+)
+
+// Product represents a product
+type Product struct {
+	Name  string
+	Owner Customer
+}
+
+// Discount represents a discount
+type Discount float64
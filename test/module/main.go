@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/shop/model"
+)
+
+func main() {
+	fmt.Println(model.Order{})
+}
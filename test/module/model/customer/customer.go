@@ -0,0 +1,6 @@
+package customer
+
+// Customer represents a customer
+type Customer struct {
+	Name string
+}
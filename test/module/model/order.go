@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	cat "example.com/shop/catalog"
+	"example.com/shop/model/customer"
+)
+
+// Order represents an order
+type Order struct {
+	ID       int
+	Customer *customer.Customer
+	Items    []*cat.Product
+	Lines    map[string]cat.Product
+	Status   Status
+	Created  time.Time
+}
+
+// Total returns order total
+func (o *Order) Total(discount cat.Discount) float64 {
+	return 0
+}
+
+// NewOrder creates an order
+func NewOrder(customer *customer.Customer, items ...*cat.Product) *Order {
+	return &Order{Customer: customer, Items: items}
+}
@@ -0,0 +1,4 @@
+package model_test
+
+// Fixture represents a test only type
+type Fixture struct{}
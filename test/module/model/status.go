@@ -0,0 +1,4 @@
+package model
+
+// Status represents an order status
+type Status string
@@ -0,0 +1,4 @@
+package tools
+
+// Tool represents a nested module type
+type Tool struct{}
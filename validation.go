@@ -0,0 +1,238 @@
+package toolbox
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//validation rule tags, i.e. `required:"true" min:"1" max:"64" pattern:"^[a-z]+$" oneof:"new,active,closed"`, omitempty:"true" skips rules for zero value
+const (
+	ValidationRequired  = "required"
+	ValidationMin       = "min"
+	ValidationMax       = "max"
+	ValidationPattern   = "pattern"
+	ValidationOneOf     = "oneof"
+	ValidationOmitEmpty = "omitempty"
+)
+
+//ValidationErrorCode machine readable error code of ValidationError
+const ValidationErrorCode = "validation_failed"
+
+//FieldError represents a field validation error
+type FieldError struct {
+	Field   string      `json:"field"` //field path using JSON names, i.e. items[1].name
+	Rule    string      `json:"rule"`
+	Message string      `json:"message"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+//ValidationError represents struct validation field errors, it is written as 400 response by ServiceRouter
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+//Error returns all field errors messages
+func (e *ValidationError) Error() string {
+	var messages = make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.Field + ": " + fieldError.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+//GetStatusCode returns 400 status code
+func (e *ValidationError) GetStatusCode() int {
+	return 400
+}
+
+//GetErrorCode returns ValidationErrorCode
+func (e *ValidationError) GetErrorCode() string {
+	return ValidationErrorCode
+}
+
+//fieldValidation represents a struct field validation rules
+type fieldValidation struct {
+	index     int
+	name      string
+	required  bool
+	omitEmpty bool
+	min       *float64
+	max       *float64
+	pattern   *regexp.Regexp
+	oneOf     []string
+	nested    bool
+}
+
+var fieldValidations = &sync.Map{}
+
+func getFieldValidations(structType reflect.Type) ([]*fieldValidation, error) {
+	if cached, ok := fieldValidations.Load(structType); ok {
+		return cached.([]*fieldValidation), nil
+	}
+	var result = make([]*fieldValidation, 0)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		validation := &fieldValidation{index: i, name: field.Name}
+		if jsonName := strings.Split(field.Tag.Get("json"), ",")[0]; jsonName == "-" {
+			continue
+		} else if jsonName != "" {
+			validation.name = jsonName
+		}
+		validation.required = AsBoolean(field.Tag.Get(ValidationRequired))
+		validation.omitEmpty = AsBoolean(field.Tag.Get(ValidationOmitEmpty))
+		for _, bound := range []struct {
+			tag    string
+			target **float64
+		}{{ValidationMin, &validation.min}, {ValidationMax, &validation.max}} {
+			if value, ok := field.Tag.Lookup(bound.tag); ok {
+				number, err := ToFloat(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %v.%v %v tag %q: %v", structType.Name(), field.Name, bound.tag, value, err)
+				}
+				*bound.target = &number
+			}
+		}
+		if value, ok := field.Tag.Lookup(ValidationPattern); ok {
+			expression, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v.%v pattern tag %q: %v", structType.Name(), field.Name, value, err)
+			}
+			validation.pattern = expression
+		}
+		if value, ok := field.Tag.Lookup(ValidationOneOf); ok {
+			validation.oneOf = strings.Split(value, ",")
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array || fieldType.Kind() == reflect.Map {
+			fieldType = fieldType.Elem()
+		}
+		validation.nested = fieldType.Kind() == reflect.Struct
+		if validation.required || validation.min != nil || validation.max != nil || validation.pattern != nil || len(validation.oneOf) > 0 || validation.nested {
+			result = append(result, validation)
+		}
+	}
+	fieldValidations.Store(structType, result)
+	return result, nil
+}
+
+//ValidateStruct validates struct or struct pointer fields with required, min, max, pattern and oneof tags, nested structs, slices and maps are validated recursively.
+//min and max limit numeric value or length of string, slice and map. Rules apply to zero values too, i.e. 0 fails min:"1" and "" fails oneof,
+//unless the field is tagged with omitempty:"true"; nil pointers are only checked with required. It returns *ValidationError listing all failing fields.
+func ValidateStruct(source interface{}) error {
+	var validationError = &ValidationError{}
+	if err := validateValue(reflect.ValueOf(source), "", validationError, map[uintptr]bool{}); err != nil {
+		return err
+	}
+	if len(validationError.Errors) > 0 {
+		return validationError
+	}
+	return nil
+}
+
+func validateValue(value reflect.Value, path string, validationError *ValidationError, visited map[uintptr]bool) error {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		if value.Kind() == reflect.Ptr {
+			if visited[value.Pointer()] {
+				return nil
+			}
+			visited[value.Pointer()] = true
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateValue(value.Index(i), fmt.Sprintf("%v[%d]", path, i), validationError, visited); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			if err := validateValue(value.MapIndex(key), fmt.Sprintf("%v[%v]", path, key.Interface()), validationError, visited); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	validations, err := getFieldValidations(value.Type())
+	if err != nil {
+		return err
+	}
+	for _, validation := range validations {
+		fieldPath := validation.name
+		if path != "" {
+			fieldPath = path + "." + validation.name
+		}
+		fieldValue := value.Field(validation.index)
+		if fieldError := validation.validate(fieldValue); fieldError != nil {
+			fieldError.Field = fieldPath
+			validationError.Errors = append(validationError.Errors, fieldError)
+			continue
+		}
+		if validation.nested {
+			if err := validateValue(fieldValue, fieldPath, validationError, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//validate returns field error for the first failing rule
+func (v *fieldValidation) validate(value reflect.Value) *FieldError {
+	if value.IsZero() {
+		if v.required {
+			return &FieldError{Rule: ValidationRequired, Message: "is required"}
+		}
+		if v.omitEmpty {
+			return nil
+		}
+	}
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	var actual = value.Interface()
+	if v.min != nil || v.max != nil {
+		var measure float64
+		var subject = "value"
+		switch value.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			measure, subject = float64(value.Len()), "length"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			measure = float64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			measure = float64(value.Uint())
+		case reflect.Float32, reflect.Float64:
+			measure = value.Float()
+		}
+		if v.min != nil && measure < *v.min {
+			return &FieldError{Rule: ValidationMin, Message: fmt.Sprintf("%v must be at least %v", subject, *v.min), Value: actual}
+		}
+		if v.max != nil && measure > *v.max {
+			return &FieldError{Rule: ValidationMax, Message: fmt.Sprintf("%v must be at most %v", subject, *v.max), Value: actual}
+		}
+	}
+	if v.pattern != nil && value.Kind() == reflect.String && !v.pattern.MatchString(value.String()) {
+		return &FieldError{Rule: ValidationPattern, Message: fmt.Sprintf("must match %v", v.pattern.String()), Value: actual}
+	}
+	if len(v.oneOf) > 0 && !HasSliceAnyElements(v.oneOf, AsString(actual)) {
+		return &FieldError{Rule: ValidationOneOf, Message: fmt.Sprintf("must be one of %v", strings.Join(v.oneOf, ", ")), Value: actual}
+	}
+	return nil
+}
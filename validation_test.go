@@ -0,0 +1,74 @@
+package toolbox_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+type validatedItem struct {
+	Name     string  `json:"name" required:"true" max:"5"`
+	Quantity int     `json:"quantity" min:"1" max:"10"`
+	Price    float64 `min:"0.5"`
+}
+
+type validatedOrder struct {
+	ID     string           `json:"id" required:"true" pattern:"^[A-Z]{2}[0-9]+$"`
+	Status string           `json:"status" oneof:"new,paid,shipped"`
+	Items  []*validatedItem `json:"items" required:"true" min:"1"`
+	Tags   []string         `json:"tags" max:"2"`
+	Note   *string          `json:"note" min:"2"`
+	Source string           `json:"source" oneof:"web,store" omitempty:"true"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	note := "ok"
+	valid := &validatedOrder{ID: "AB12", Status: "paid", Items: []*validatedItem{{Name: "desk", Quantity: 1, Price: 3}}, Note: &note}
+	assert.Nil(t, toolbox.ValidateStruct(valid))
+	assert.Nil(t, toolbox.ValidateStruct(*valid))
+	assert.Nil(t, toolbox.ValidateStruct([]string{"not a struct"}))
+
+	note = "x"
+	invalid := &validatedOrder{ID: "ab12", Status: "lost", Items: []*validatedItem{{Name: "desk", Quantity: -1, Price: 0.1}, {Name: "cabinet", Quantity: 11, Price: 1}}, Tags: []string{"a", "b", "c"}, Note: &note}
+	err := toolbox.ValidateStruct(invalid)
+	if !assert.NotNil(t, err) {
+		return
+	}
+	validationError, ok := err.(*toolbox.ValidationError)
+	assert.True(t, ok)
+	var actual = make(map[string]string)
+	for _, fieldError := range validationError.Errors {
+		actual[fieldError.Field] = fieldError.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"id":                "pattern",
+		"status":            "oneof",
+		"items[0].quantity": "min",
+		"items[0].Price":    "min",
+		"items[1].name":     "max",
+		"items[1].quantity": "max",
+		"tags":              "max",
+		"note":              "min",
+	}, actual)
+	assert.Equal(t, 400, validationError.GetStatusCode())
+	assert.Equal(t, toolbox.ValidationErrorCode, validationError.GetErrorCode())
+
+	err = toolbox.ValidateStruct(&validatedOrder{})
+	assert.EqualValues(t, []*toolbox.FieldError{
+		{Field: "id", Rule: "required", Message: "is required"},
+		{Field: "status", Rule: "oneof", Message: "must be one of new, paid, shipped", Value: ""},
+		{Field: "items", Rule: "required", Message: "is required"},
+	}, err.(*toolbox.ValidationError).Errors)
+
+	err = toolbox.ValidateStruct(&validatedOrder{ID: "AB12", Status: "new", Items: []*validatedItem{{Name: "desk"}}, Source: "mail"})
+	assert.EqualValues(t, []*toolbox.FieldError{
+		{Field: "items[0].quantity", Rule: "min", Message: "value must be at least 1", Value: 0},
+		{Field: "items[0].Price", Rule: "min", Message: "value must be at least 0.5", Value: 0.0},
+		{Field: "source", Rule: "oneof", Message: "must be one of web, store", Value: "mail"},
+	}, err.(*toolbox.ValidationError).Errors)
+
+	assert.NotNil(t, toolbox.ValidateStruct(struct {
+		ID int `min:"abc"`
+	}{}))
+}